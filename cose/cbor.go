@@ -0,0 +1,404 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package cose
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// the CBOR major types, as described in RFC 8949
+const (
+	majorUnsigned byte = iota
+	majorNegative
+	majorBytes
+	majorText
+	majorArray
+	majorMap
+	majorTag
+	majorSimple
+)
+
+const (
+	simpleFalse   byte = 20
+	simpleTrue    byte = 21
+	simpleNull    byte = 22
+	simpleFloat16 byte = 25
+	simpleFloat32 byte = 26
+	simpleFloat64 byte = 27
+)
+
+const (
+	// MaxDepth is the deepest nesting of arrays, maps, and tags that Unmarshal decodes
+	MaxDepth = 16
+
+	// MaxLength is the largest encoded data item, in bytes, that Unmarshal decodes
+	MaxLength = 1 << 20
+)
+
+var (
+	ErrUnsupportedType = errors.New("the value cannot be encoded as CBOR")
+	ErrMalformed       = errors.New("malformed CBOR")
+)
+
+// Tag is a CBOR tagged data item
+type Tag struct {
+	Number  uint64
+	Content any
+}
+
+// Marshal produces the deterministic CBOR encoding of a value, as described in RFC 8949 section 4.2.
+// Supported values are nil, booleans, integers, floats, json.Number, strings, byte slices, slices,
+// maps keyed by strings or integers, and Tag.  Map keys are sorted by their encoded bytes.
+func Marshal(v any) ([]byte, error) {
+	return appendValue(nil, v)
+}
+
+// appendHead appends the initial byte and argument of a data item
+func appendHead(b []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, major|27), n)
+	}
+}
+
+func appendInt(b []byte, n int64) []byte {
+	if n < 0 {
+		return appendHead(b, majorNegative, uint64(-1-n))
+	}
+
+	return appendHead(b, majorUnsigned, uint64(n))
+}
+
+func appendFloat(b []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(append(b, majorSimple<<5|simpleFloat64), math.Float64bits(f))
+}
+
+func appendValue(b []byte, v any) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(b, majorSimple<<5|simpleNull), nil
+
+	case bool:
+		if v {
+			return append(b, majorSimple<<5|simpleTrue), nil
+		}
+
+		return append(b, majorSimple<<5|simpleFalse), nil
+
+	case int:
+		return appendInt(b, int64(v)), nil
+	case int8:
+		return appendInt(b, int64(v)), nil
+	case int16:
+		return appendInt(b, int64(v)), nil
+	case int32:
+		return appendInt(b, int64(v)), nil
+	case int64:
+		return appendInt(b, v), nil
+	case uint:
+		return appendHead(b, majorUnsigned, uint64(v)), nil
+	case uint8:
+		return appendHead(b, majorUnsigned, uint64(v)), nil
+	case uint16:
+		return appendHead(b, majorUnsigned, uint64(v)), nil
+	case uint32:
+		return appendHead(b, majorUnsigned, uint64(v)), nil
+	case uint64:
+		return appendHead(b, majorUnsigned, v), nil
+	case float32:
+		return appendFloat(b, float64(v)), nil
+	case float64:
+		return appendFloat(b, v), nil
+
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return appendInt(b, n), nil
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnsupportedType, err)
+		}
+
+		return appendFloat(b, f), nil
+
+	case string:
+		return append(appendHead(b, majorText, uint64(len(v))), v...), nil
+
+	case []byte:
+		return append(appendHead(b, majorBytes, uint64(len(v))), v...), nil
+
+	case []any:
+		return appendArray(b, v)
+	case []string:
+		return appendArray(b, v)
+	case map[string]any:
+		return appendMap(b, v)
+	case map[int]any:
+		return appendMap(b, v)
+	case map[any]any:
+		return appendMap(b, v)
+
+	case Tag:
+		return appendValue(appendHead(b, majorTag, v.Number), v.Content)
+
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedType, v)
+	}
+}
+
+func appendArray[E any](b []byte, a []E) ([]byte, error) {
+	b = appendHead(b, majorArray, uint64(len(a)))
+	for _, e := range a {
+		var err error
+		if b, err = appendValue(b, e); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+func appendMap[K comparable](b []byte, m map[K]any) ([]byte, error) {
+	type entry struct {
+		key, value []byte
+	}
+
+	entries := make([]entry, 0, len(m))
+	for k, v := range m {
+		key, err := appendValue(nil, k)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := appendValue(nil, v)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry{key: key, value: value})
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int {
+		return bytes.Compare(a.key, b.key)
+	})
+
+	b = appendHead(b, majorMap, uint64(len(entries)))
+	for _, e := range entries {
+		b = append(append(b, e.key...), e.value...)
+	}
+
+	return b, nil
+}
+
+// Unmarshal decodes a single CBOR data item.  Integers decode as int64, or uint64 if too large,
+// floats as float64, text as string, byte strings as []byte, arrays as []any, maps as map[any]any,
+// and tagged items as Tag.  Indefinite-length items are not supported.
+//
+// Data longer than MaxLength, or nested deeper than MaxDepth, is rejected as malformed.  The lengths
+// of arrays and maps are checked against the remaining data before anything is allocated for them.
+func Unmarshal(data []byte) (any, error) {
+	if len(data) > MaxLength {
+		return nil, fmt.Errorf("%w: longer than %d bytes", ErrMalformed, MaxLength)
+	}
+
+	d := decoder{data: data}
+	v, err := d.value(0)
+	if err == nil && d.offset < len(d.data) {
+		err = fmt.Errorf("%w: trailing data", ErrMalformed)
+	}
+
+	return v, err
+}
+
+type decoder struct {
+	data   []byte
+	offset int
+}
+
+// remaining returns the number of bytes left to decode
+func (d *decoder) remaining() uint64 {
+	return uint64(len(d.data) - d.offset)
+}
+
+func (d *decoder) next(n uint64) ([]byte, error) {
+	if n > d.remaining() {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrMalformed)
+	}
+
+	b := d.data[d.offset : d.offset+int(n)]
+	d.offset += int(n)
+	return b, nil
+}
+
+// head decodes the major type, additional information, and argument of the next data item
+func (d *decoder) head() (major, info byte, n uint64, err error) {
+	b, err := d.next(1)
+	if err != nil {
+		return
+	}
+
+	major, info = b[0]>>5, b[0]&0x1f
+	switch {
+	case info < 24:
+		n = uint64(info)
+	case info <= 27:
+		var arg []byte
+		if arg, err = d.next(1 << (info - 24)); err != nil {
+			return
+		}
+
+		for _, a := range arg {
+			n = n<<8 | uint64(a)
+		}
+
+	default:
+		err = fmt.Errorf("%w: unsupported additional information %d", ErrMalformed, info)
+	}
+
+	return
+}
+
+func (d *decoder) value(depth int) (any, error) {
+	if depth > MaxDepth {
+		return nil, fmt.Errorf("%w: nested too deeply", ErrMalformed)
+	}
+
+	major, info, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case majorUnsigned:
+		if n > math.MaxInt64 {
+			return n, nil
+		}
+
+		return int64(n), nil
+
+	case majorNegative:
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: negative integer out of range", ErrMalformed)
+		}
+
+		return -1 - int64(n), nil
+
+	case majorBytes:
+		b, err := d.next(n)
+		return bytes.Clone(b), err
+
+	case majorText:
+		b, err := d.next(n)
+		return string(b), err
+
+	case majorArray:
+		// every element takes at least one byte
+		if n > d.remaining() {
+			return nil, fmt.Errorf("%w: array too long", ErrMalformed)
+		}
+
+		a := make([]any, n)
+		for i := range a {
+			if a[i], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return a, nil
+
+	case majorMap:
+		// every entry takes at least two bytes
+		if n > d.remaining()/2 {
+			return nil, fmt.Errorf("%w: map too long", ErrMalformed)
+		}
+
+		m := make(map[any]any, n)
+		for range n {
+			k, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+
+			switch k.(type) {
+			case int64, uint64, string:
+			default:
+				return nil, fmt.Errorf("%w: unsupported map key %T", ErrMalformed, k)
+			}
+
+			if _, ok := m[k]; ok {
+				return nil, fmt.Errorf("%w: duplicate map key %v", ErrMalformed, k)
+			}
+
+			if m[k], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return m, nil
+
+	case majorTag:
+		content, err := d.value(depth + 1)
+		return Tag{Number: n, Content: content}, err
+
+	default:
+		switch info {
+		case simpleFalse:
+			return false, nil
+		case simpleTrue:
+			return true, nil
+		case simpleNull:
+			return nil, nil
+		case simpleFloat16:
+			return float16(uint16(n)), nil
+		case simpleFloat32:
+			return float64(math.Float32frombits(uint32(n))), nil
+		case simpleFloat64:
+			return math.Float64frombits(n), nil
+		default:
+			return nil, fmt.Errorf("%w: unsupported simple value %d", ErrMalformed, info)
+		}
+	}
+}
+
+// float16 converts an IEEE 754 half-precision float
+func float16(h uint16) float64 {
+	var (
+		exponent = int(h>>10) & 0x1f
+		fraction = float64(h & 0x3ff)
+		f        float64
+	)
+
+	switch exponent {
+	case 0:
+		f = math.Ldexp(fraction, -24)
+	case 0x1f:
+		f = math.Inf(1)
+		if fraction != 0 {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(fraction+1024, exponent-25)
+	}
+
+	if h&0x8000 != 0 {
+		f = -f
+	}
+
+	return f
+}
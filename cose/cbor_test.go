@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package cose
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMarshalValid(t *testing.T) {
+	// the expected encodings are from RFC 8949, appendix A
+	testData := []struct {
+		value    any
+		expected string
+	}{
+		{0, "00"},
+		{23, "17"},
+		{24, "1818"},
+		{uint64(1000000000000), "1b000000e8d4a51000"},
+		{uint64(math.MaxUint64), "1bffffffffffffffff"},
+		{-1, "20"},
+		{int64(-1000), "3903e7"},
+		{json.Number("100"), "1864"},
+		{1.1, "fb3ff199999999999a"},
+		{json.Number("1.1"), "fb3ff199999999999a"},
+		{false, "f4"},
+		{true, "f5"},
+		{nil, "f6"},
+		{"", "60"},
+		{"IETF", "6449455446"},
+		{"ü", "62c3bc"},
+		{[]byte{1, 2, 3, 4}, "4401020304"},
+		{[]any{1, []any{2, 3}, []string{"a"}}, "8301820203816161"},
+		{map[string]any{"b": []any{2, 3}, "a": 1}, "a26161016162820203"},
+		{map[int]any{10: 1, -1: 2, 100: 3, 1: 4}, "a401040a011864032002"},
+		{map[any]any{"a": 1, 2: "b"}, "a2026162616101"},
+		{Tag{Number: 1, Content: 1363896240}, "c11a514b67b0"},
+	}
+
+	for _, record := range testData {
+		t.Run(record.expected, func(t *testing.T) {
+			data, err := Marshal(record.value)
+			require.NoError(t, err)
+			assert.Equal(t, record.expected, hex.EncodeToString(data))
+		})
+	}
+}
+
+func testMarshalInvalid(t *testing.T) {
+	for _, value := range []any{struct{}{}, map[string]any{"a": struct{}{}}, []any{make(chan int)}, json.Number("x")} {
+		data, err := Marshal(value)
+		assert.Empty(t, data)
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	t.Run("Valid", testMarshalValid)
+	t.Run("Invalid", testMarshalInvalid)
+}
+
+func testUnmarshalValid(t *testing.T) {
+	testData := []struct {
+		encoded  string
+		expected any
+	}{
+		{"00", int64(0)},
+		{"1bffffffffffffffff", uint64(math.MaxUint64)},
+		{"3903e7", int64(-1000)},
+		{"f93c00", 1.0},
+		{"f9c400", -4.0},
+		{"fa47c35000", 100000.0},
+		{"fb3ff199999999999a", 1.1},
+		{"f4", false},
+		{"f5", true},
+		{"f6", nil},
+		{"6449455446", "IETF"},
+		{"4401020304", []byte{1, 2, 3, 4}},
+		{"8301820203820405", []any{int64(1), []any{int64(2), int64(3)}, []any{int64(4), int64(5)}}},
+		{"a201020304", map[any]any{int64(1): int64(2), int64(3): int64(4)}},
+		{"a26161016162820203", map[any]any{"a": int64(1), "b": []any{int64(2), int64(3)}}},
+		{"c11a514b67b0", Tag{Number: 1, Content: int64(1363896240)}},
+	}
+
+	for _, record := range testData {
+		t.Run(record.encoded, func(t *testing.T) {
+			data, err := hex.DecodeString(record.encoded)
+			require.NoError(t, err)
+
+			v, err := Unmarshal(data)
+			require.NoError(t, err)
+			assert.Equal(t, record.expected, v)
+		})
+	}
+}
+
+func testUnmarshalInvalid(t *testing.T) {
+	testData := []string{
+		"",
+		"18",                 // missing argument
+		"62c3",               // truncated text
+		"5f4101ff",           // indefinite length
+		"a1010203",           // trailing data
+		"a20102",             // truncated map
+		"a201020103",         // duplicate key
+		"a1410102",           // byte string key
+		"9bffffffffffffffff", // array too long
+		"830102",             // array longer than the remaining data
+		"a2010203",           // map longer than the remaining data
+		"3bffffffffffffffff", // negative out of range
+		"f8ff",               // unsupported simple value
+	}
+
+	for _, encoded := range testData {
+		t.Run(encoded, func(t *testing.T) {
+			data, err := hex.DecodeString(encoded)
+			require.NoError(t, err)
+
+			_, err = Unmarshal(data)
+			assert.ErrorIs(t, err, ErrMalformed)
+		})
+	}
+
+	t.Run("TooDeep", func(t *testing.T) {
+		data := make([]byte, MaxDepth+2)
+		for i := range data {
+			data[i] = 0x81
+		}
+
+		_, err := Unmarshal(append(data, 0x00))
+		assert.ErrorIs(t, err, ErrMalformed)
+	})
+
+	t.Run("TooLong", func(t *testing.T) {
+		data := make([]byte, MaxLength+1)
+		data[0], data[1], data[2], data[3], data[4] = 0x5a, 0x00, 0x0f, 0xff, 0xfc
+
+		_, err := Unmarshal(data)
+		assert.ErrorIs(t, err, ErrMalformed)
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("Valid", testUnmarshalValid)
+	t.Run("Invalid", testUnmarshalInvalid)
+}
+
+func FuzzDecode(f *testing.F) {
+	for _, seed := range []string{"00", "1bffffffffffffffff", "f93c00", "8301820203820405", "a26161016162820203", "c11a514b67b0", "5f4101ff", "9bffffffffffffffff"} {
+		data, err := hex.DecodeString(seed)
+		require.NoError(f, err)
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := Unmarshal(data)
+		if err != nil {
+			if !errors.Is(err, ErrMalformed) {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			return
+		}
+
+		// anything that decodes can be encoded again, and that encoding decodes as well
+		encoded, err := Marshal(v)
+		if err != nil {
+			t.Fatalf("unable to encode %#v: %s", v, err)
+		}
+
+		if _, err := Unmarshal(encoded); err != nil {
+			t.Fatalf("unable to decode the encoding of %#v: %s", v, err)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package cose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"fmt"
+	"math/big"
+)
+
+// the COSE_Key labels and values, as described in RFC 9052, RFC 9053, and RFC 8230
+const (
+	KeyLabelKty = 1
+	KeyLabelKID = 2
+	KeyLabelAlg = 3
+
+	// KeyLabelCrv, KeyLabelX, and KeyLabelY are the curve and coordinates of OKP and EC2 keys
+	KeyLabelCrv = -1
+	KeyLabelX   = -2
+	KeyLabelY   = -3
+
+	// KeyLabelN and KeyLabelE are the modulus and exponent of RSA keys
+	KeyLabelN = -1
+	KeyLabelE = -2
+
+	KeyTypeOKP = 1
+	KeyTypeEC2 = 2
+	KeyTypeRSA = 3
+
+	CurveP256    = 1
+	CurveP384    = 2
+	CurveP521    = 3
+	CurveEd25519 = 6
+)
+
+var curves = map[elliptic.Curve]int{
+	elliptic.P256(): CurveP256,
+	elliptic.P384(): CurveP384,
+	elliptic.P521(): CurveP521,
+}
+
+// Key produces the CBOR encoding of a public key as a COSE_Key.  The kid and the COSE identifier of
+// the JWA algorithm alg are included when set.
+func Key(public crypto.PublicKey, kid, alg string) ([]byte, error) {
+	k := make(map[int]any, 6)
+	switch p := public.(type) {
+	case ed25519.PublicKey:
+		k[KeyLabelKty] = KeyTypeOKP
+		k[KeyLabelCrv] = CurveEd25519
+		k[KeyLabelX] = []byte(p)
+
+	case *ecdsa.PublicKey:
+		crv, ok := curves[p.Curve]
+		if !ok {
+			return nil, fmt.Errorf("%w: unsupported curve %s", ErrKeyMismatch, p.Curve.Params().Name)
+		}
+
+		point, err := p.Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		// the uncompressed point is 0x04 followed by the fixed-width x and y coordinates
+		size := (len(point) - 1) / 2
+		k[KeyLabelKty] = KeyTypeEC2
+		k[KeyLabelCrv] = crv
+		k[KeyLabelX] = point[1 : 1+size]
+		k[KeyLabelY] = point[1+size:]
+
+	case *rsa.PublicKey:
+		k[KeyLabelKty] = KeyTypeRSA
+		k[KeyLabelN] = p.N.Bytes()
+		k[KeyLabelE] = big.NewInt(int64(p.E)).Bytes()
+
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %T", ErrKeyMismatch, public)
+	}
+
+	if len(kid) > 0 {
+		k[KeyLabelKID] = []byte(kid)
+	}
+
+	if len(alg) > 0 {
+		id, ok := Algorithm(alg)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlg, alg)
+		}
+
+		k[KeyLabelAlg] = id
+	}
+
+	return Marshal(k)
+}
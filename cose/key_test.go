@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package cose
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(t *testing.T, public any, kid, alg string) map[any]any {
+	data, err := Key(public, kid, alg)
+	require.NoError(t, err)
+
+	v, err := Unmarshal(data)
+	require.NoError(t, err)
+	return v.(map[any]any)
+}
+
+func TestKey(t *testing.T) {
+	t.Run("EC2", func(t *testing.T) {
+		private, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		k := testKey(t, &private.PublicKey, "test", "ES384")
+		assert.Equal(t, int64(KeyTypeEC2), k[int64(KeyLabelKty)])
+		assert.Equal(t, []byte("test"), k[int64(KeyLabelKID)])
+		assert.Equal(t, int64(-35), k[int64(KeyLabelAlg)])
+		assert.Equal(t, int64(CurveP384), k[int64(KeyLabelCrv)])
+		assert.Equal(t, private.X.FillBytes(make([]byte, 48)), k[int64(KeyLabelX)])
+		assert.Equal(t, private.Y.FillBytes(make([]byte, 48)), k[int64(KeyLabelY)])
+	})
+
+	t.Run("OKP", func(t *testing.T) {
+		public, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		k := testKey(t, public, "", "")
+		assert.Equal(t, map[any]any{
+			int64(KeyLabelKty): int64(KeyTypeOKP),
+			int64(KeyLabelCrv): int64(CurveEd25519),
+			int64(KeyLabelX):   []byte(public),
+		}, k)
+	})
+
+	t.Run("RSA", func(t *testing.T) {
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		k := testKey(t, &private.PublicKey, "test", "PS256")
+		assert.Equal(t, int64(KeyTypeRSA), k[int64(KeyLabelKty)])
+		assert.Equal(t, int64(-37), k[int64(KeyLabelAlg)])
+		assert.Equal(t, private.N.Bytes(), k[int64(KeyLabelN)])
+		assert.Equal(t, []byte{1, 0, 1}, k[int64(KeyLabelE)])
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		private, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+		require.NoError(t, err)
+
+		data, err := Key(&private.PublicKey, "", "")
+		assert.Empty(t, data)
+		assert.ErrorIs(t, err, ErrKeyMismatch)
+
+		data, err = Key([]byte("secret"), "", "")
+		assert.Empty(t, data)
+		assert.ErrorIs(t, err, ErrKeyMismatch)
+
+		public, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		data, err = Key(public, "", "HS256")
+		assert.Empty(t, data)
+		assert.ErrorIs(t, err, ErrUnsupportedAlg)
+	})
+}
@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package cose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// TagSign1 is the CBOR tag of a COSE_Sign1 message
+	TagSign1 = 18
+
+	// HeaderAlg is the label of the alg header parameter
+	HeaderAlg = 1
+
+	// HeaderKID is the label of the kid header parameter
+	HeaderKID = 4
+
+	// contextSign1 is the context of the Sig_structure for COSE_Sign1 messages
+	contextSign1 = "Signature1"
+)
+
+var (
+	ErrUnsupportedAlg = errors.New("unsupported COSE algorithm")
+	ErrNotSign1       = errors.New("not a COSE_Sign1 message")
+	ErrKeyMismatch    = errors.New("the key is not compatible with the algorithm")
+	ErrInvalidSig     = errors.New("invalid COSE_Sign1 signature")
+)
+
+// algorithm is a COSE signature algorithm, with the JWA algorithm it corresponds to
+type algorithm struct {
+	jwa  string
+	id   int64
+	hash crypto.Hash
+}
+
+// algorithms are the COSE signature algorithms registered by RFC 9053 and RFC 8230, keyed by their JWA
+// name.  The signatures of these algorithms are the same in JWS and COSE.
+var algorithms = map[string]algorithm{
+	"ES256": {"ES256", -7, crypto.SHA256},
+	"ES384": {"ES384", -35, crypto.SHA384},
+	"ES512": {"ES512", -36, crypto.SHA512},
+	"EdDSA": {"EdDSA", -8, crypto.Hash(0)},
+	"PS256": {"PS256", -37, crypto.SHA256},
+	"PS384": {"PS384", -38, crypto.SHA384},
+	"PS512": {"PS512", -39, crypto.SHA512},
+	"RS256": {"RS256", -257, crypto.SHA256},
+	"RS384": {"RS384", -258, crypto.SHA384},
+	"RS512": {"RS512", -259, crypto.SHA512},
+}
+
+// Algorithm returns the COSE algorithm identifier for a JWA signature algorithm, e.g. -7 for ES256
+func Algorithm(jwa string) (int64, bool) {
+	a, ok := algorithms[jwa]
+	return a.id, ok
+}
+
+// algorithmByID looks up a COSE signature algorithm by its identifier
+func algorithmByID(id int64) (algorithm, bool) {
+	for _, a := range algorithms {
+		if a.id == id {
+			return a, true
+		}
+	}
+
+	return algorithm{}, false
+}
+
+// sigStructure produces the bytes that are signed for a COSE_Sign1 message, with no external data
+func sigStructure(protected, payload []byte) ([]byte, error) {
+	return Marshal([]any{contextSign1, protected, []byte{}, payload})
+}
+
+// Sign1 produces a tagged COSE_Sign1 message, as described in RFC 9052, with the algorithm in the protected
+// header and the kid, if any, in the unprotected header.  The sign function computes the signature of its
+// input in the same form as a JWS signature for the JWA algorithm alg.
+func Sign1(alg, kid string, payload []byte, sign func([]byte) ([]byte, error)) ([]byte, error) {
+	id, ok := Algorithm(alg)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlg, alg)
+	}
+
+	protected, err := Marshal(map[int]any{HeaderAlg: id})
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := sigStructure(protected, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := sign(input)
+	if err != nil {
+		return nil, err
+	}
+
+	unprotected := make(map[int]any, 1)
+	if len(kid) > 0 {
+		unprotected[HeaderKID] = []byte(kid)
+	}
+
+	return Marshal(Tag{
+		Number:  TagSign1,
+		Content: []any{protected, unprotected, payload, signature},
+	})
+}
+
+// Verify1 verifies a tagged COSE_Sign1 message with a public key, returning its payload and the JWA name
+// of its algorithm.
+func Verify1(message []byte, public crypto.PublicKey) ([]byte, string, error) {
+	v, err := Unmarshal(message)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tag, ok := v.(Tag)
+	if !ok || tag.Number != TagSign1 {
+		return nil, "", ErrNotSign1
+	}
+
+	parts, _ := tag.Content.([]any)
+	if len(parts) != 4 {
+		return nil, "", ErrNotSign1
+	}
+
+	protected, _ := parts[0].([]byte)
+	payload, _ := parts[2].([]byte)
+	signature, _ := parts[3].([]byte)
+	if protected == nil || payload == nil || signature == nil {
+		return nil, "", ErrNotSign1
+	}
+
+	headers, err := Unmarshal(protected)
+	if err != nil {
+		return nil, "", err
+	}
+
+	h, _ := headers.(map[any]any)
+	id, _ := h[int64(HeaderAlg)].(int64)
+	a, ok := algorithmByID(id)
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedAlg, h[int64(HeaderAlg)])
+	}
+
+	input, err := sigStructure(protected, payload)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := verify(a, public, input, signature); err != nil {
+		return nil, "", err
+	}
+
+	return payload, a.jwa, nil
+}
+
+// verify checks the signature of an input for a signature algorithm
+func verify(a algorithm, public crypto.PublicKey, input, signature []byte) error {
+	digest := input
+	if a.hash != 0 {
+		h := a.hash.New()
+		h.Write(input)
+		digest = h.Sum(nil)
+	}
+
+	var valid bool
+	switch k := public.(type) {
+	case ed25519.PublicKey:
+		if a.jwa != "EdDSA" {
+			return fmt.Errorf("%w: %s cannot be used with %T", ErrKeyMismatch, a.jwa, public)
+		}
+
+		valid = ed25519.Verify(k, input, signature)
+
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if a.jwa[0] != 'E' || a.jwa == "EdDSA" {
+			return fmt.Errorf("%w: %s cannot be used with %T", ErrKeyMismatch, a.jwa, public)
+		}
+
+		if len(signature) == 2*size {
+			r := new(big.Int).SetBytes(signature[:size])
+			s := new(big.Int).SetBytes(signature[size:])
+			valid = ecdsa.Verify(k, digest, r, s)
+		}
+
+	case *rsa.PublicKey:
+		switch a.jwa[0] {
+		case 'R':
+			valid = rsa.VerifyPKCS1v15(k, a.hash, digest, signature) == nil
+		case 'P':
+			valid = rsa.VerifyPSS(k, a.hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		default:
+			return fmt.Errorf("%w: %s cannot be used with %T", ErrKeyMismatch, a.jwa, public)
+		}
+
+	default:
+		return fmt.Errorf("%w: %s cannot be used with %T", ErrKeyMismatch, a.jwa, public)
+	}
+
+	if !valid {
+		return ErrInvalidSig
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package cose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSigner produces JWS-style signatures for a private key
+func testSigner(t *testing.T, alg string, private crypto.Signer) func([]byte) ([]byte, error) {
+	return func(input []byte) ([]byte, error) {
+		a := algorithms[alg]
+		digest := input
+		if a.hash != 0 {
+			h := a.hash.New()
+			h.Write(input)
+			digest = h.Sum(nil)
+		}
+
+		switch k := private.(type) {
+		case *ecdsa.PrivateKey:
+			r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+			require.NoError(t, err)
+
+			size := (k.Curve.Params().BitSize + 7) / 8
+			signature := make([]byte, 2*size)
+			r.FillBytes(signature[:size])
+			s.FillBytes(signature[size:])
+			return signature, nil
+
+		case *rsa.PrivateKey:
+			if alg[0] == 'P' {
+				return rsa.SignPSS(rand.Reader, k, a.hash, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+			}
+
+			return rsa.SignPKCS1v15(rand.Reader, k, a.hash, digest)
+
+		default:
+			return private.Sign(rand.Reader, digest, crypto.Hash(0))
+		}
+	}
+}
+
+func testSign1Valid(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecdsa384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testData := []struct {
+		alg     string
+		private crypto.Signer
+	}{
+		{"ES256", ecdsaKey},
+		{"ES384", ecdsa384Key},
+		{"RS256", rsaKey},
+		{"PS256", rsaKey},
+		{"EdDSA", ed25519Key},
+	}
+
+	for _, record := range testData {
+		t.Run(record.alg, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+				payload = []byte("payload")
+			)
+
+			message, err := Sign1(record.alg, "test", payload, testSigner(t, record.alg, record.private))
+			require.NoError(err)
+
+			v, err := Unmarshal(message)
+			require.NoError(err)
+			tag, ok := v.(Tag)
+			require.True(ok)
+			assert.Equal(uint64(TagSign1), tag.Number)
+
+			parts := tag.Content.([]any)
+			require.Len(parts, 4)
+			id, _ := Algorithm(record.alg)
+			headers, err := Unmarshal(parts[0].([]byte))
+			require.NoError(err)
+			assert.Equal(map[any]any{int64(HeaderAlg): id}, headers)
+			assert.Equal(map[any]any{int64(HeaderKID): []byte("test")}, parts[1])
+
+			actual, alg, err := Verify1(message, record.private.Public())
+			require.NoError(err)
+			assert.Equal(payload, actual)
+			assert.Equal(record.alg, alg)
+
+			// any change to the payload invalidates the signature
+			parts[2] = []byte("tampered")
+			tampered, err := Marshal(tag)
+			require.NoError(err)
+			_, _, err = Verify1(tampered, record.private.Public())
+			assert.ErrorIs(err, ErrInvalidSig)
+		})
+	}
+}
+
+func testSign1Invalid(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	sign := testSigner(t, "ES256", ecdsaKey)
+
+	t.Run("UnsupportedAlg", func(t *testing.T) {
+		message, err := Sign1("HS256", "", []byte("payload"), sign)
+		assert.Empty(t, message)
+		assert.ErrorIs(t, err, ErrUnsupportedAlg)
+	})
+
+	t.Run("SignError", func(t *testing.T) {
+		expected := errors.New("expected")
+		message, err := Sign1("ES256", "", []byte("payload"), func([]byte) ([]byte, error) { return nil, expected })
+		assert.Empty(t, message)
+		assert.ErrorIs(t, err, expected)
+	})
+
+	message, err := Sign1("ES256", "", []byte("payload"), sign)
+	require.NoError(t, err)
+
+	t.Run("WrongKey", func(t *testing.T) {
+		other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		_, _, err = Verify1(message, &other.PublicKey)
+		assert.ErrorIs(t, err, ErrInvalidSig)
+	})
+
+	t.Run("WrongKeyType", func(t *testing.T) {
+		public, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		_, _, err = Verify1(message, public)
+		assert.ErrorIs(t, err, ErrKeyMismatch)
+	})
+
+	t.Run("NotSign1", func(t *testing.T) {
+		for _, v := range []any{
+			[]any{1, 2, 3, 4},
+			Tag{Number: 17, Content: []any{[]byte{}, map[int]any{}, []byte{}, []byte{}}},
+			Tag{Number: TagSign1, Content: []any{[]byte{}, map[int]any{}, []byte{}}},
+			Tag{Number: TagSign1, Content: []any{1, map[int]any{}, []byte{}, []byte{}}},
+		} {
+			data, err := Marshal(v)
+			require.NoError(t, err)
+			_, _, err = Verify1(data, &ecdsaKey.PublicKey)
+			assert.ErrorIs(t, err, ErrNotSign1)
+		}
+	})
+
+	t.Run("UnknownAlg", func(t *testing.T) {
+		protected, err := Marshal(map[int]any{HeaderAlg: 5})
+		require.NoError(t, err)
+		data, err := Marshal(Tag{Number: TagSign1, Content: []any{protected, map[int]any{}, []byte{}, []byte{}}})
+		require.NoError(t, err)
+		_, _, err = Verify1(data, &ecdsaKey.PublicKey)
+		assert.ErrorIs(t, err, ErrUnsupportedAlg)
+	})
+}
+
+func TestSign1(t *testing.T) {
+	t.Run("Valid", testSign1Valid)
+	t.Run("Invalid", testSign1Invalid)
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package themis
+
+import (
+	"net"
+	"strings"
+
+	"github.com/xmidt-org/themis/v2/config"
+	"github.com/xmidt-org/themis/v2/token"
+	"github.com/xmidt-org/themis/v2/xhttp/xhttpserver"
+	"go.uber.org/fx"
+)
+
+type DiscoveryIn struct {
+	fx.In
+	Unmarshaller config.Unmarshaller
+	Options      token.Options
+	Discovery    token.DiscoveryOptions `optional:"true"`
+}
+
+// serverURL computes the base URL of one of the themis servers.  An externally configured URL is
+// used as is.  Otherwise, the URL is built from the server's configuration and has no host, so that
+// the host of each discovery request is used.  If neither is available, this function returns
+// an empty string.
+func serverURL(u config.Unmarshaller, serverKey, external string) (string, error) {
+	if len(external) > 0 {
+		return strings.TrimSuffix(external, "/"), nil
+	}
+
+	if !u.IsSet(serverKey) {
+		return "", nil
+	}
+
+	var o xhttpserver.Options
+	if err := u.UnmarshalKey(serverKey, &o); err != nil {
+		return "", err
+	}
+
+	scheme := "http"
+	if o.Tls != nil {
+		scheme = "https"
+	}
+
+	// an address without a port leaves the port to the discovery request as well
+	if _, port, err := net.SplitHostPort(o.Address); err == nil && len(port) > 0 {
+		return scheme + "://:" + port, nil
+	}
+
+	return scheme + "://", nil
+}
+
+// provideDiscoveryHandler builds the OpenID Connect discovery handler from the token and server
+// configuration.  If no keys server is configured, there is nowhere to serve the document and
+// no handler is produced.
+func provideDiscoveryHandler(in DiscoveryIn) (token.DiscoveryHandler, error) {
+	keysURL, err := serverURL(in.Unmarshaller, "servers.key", in.Discovery.KeysURL)
+	if err != nil || len(keysURL) == 0 {
+		return nil, err
+	}
+
+	issuerURL, err := serverURL(in.Unmarshaller, "servers.issuer", in.Discovery.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	claimsURL, err := serverURL(in.Unmarshaller, "servers.claims", in.Discovery.ClaimsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	e := token.DiscoveryEndpoints{
+		JWKS: keysURL + JWKSPath,
+	}
+
+	if len(issuerURL) > 0 {
+		e.Issue = issuerURL + IssuePath
+	}
+
+	if len(claimsURL) > 0 {
+		e.Claims = claimsURL + ClaimsPath
+	}
+
+	return token.NewDiscoveryHandler(in.Options, e, token.DefaultDiscoveryMaxAge)
+}
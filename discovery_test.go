@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package themis
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/config"
+	"github.com/xmidt-org/themis/v2/token"
+)
+
+func testDiscoveryIn(t *testing.T, configuration string, o token.DiscoveryOptions) DiscoveryIn {
+	v := viper.New()
+	require.NoError(t, config.Json(configuration)(config.ViperIn{}, v))
+	return DiscoveryIn{
+		Unmarshaller: config.ViperUnmarshaller{Viper: v},
+		Options: token.Options{
+			Claims: []token.Value{
+				{Key: "iss", Value: "themis"},
+			},
+		},
+		Discovery: o,
+	}
+}
+
+func TestProvideDiscoveryHandler(t *testing.T) {
+	t.Run("NoKeysServer", func(t *testing.T) {
+		h, err := provideDiscoveryHandler(testDiscoveryIn(t, `{"servers": {"claims": {"address": ":6502"}}}`, token.DiscoveryOptions{}))
+		assert.NoError(t, err)
+		assert.Nil(t, h)
+	})
+
+	t.Run("Servers", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+		)
+
+		h, err := provideDiscoveryHandler(testDiscoveryIn(t,
+			`{"servers": {"key": {"address": ":6500"}, "issuer": {"address": "localhost:6501", "tls": {"certificateFile": "cert.pem"}}}}`,
+			token.DiscoveryOptions{
+				ClaimsURL: "https://claims.example.com/",
+			},
+		))
+
+		require.NoError(err)
+		require.NotNil(h)
+
+		var (
+			response = httptest.NewRecorder()
+			request  = httptest.NewRequest("GET", DiscoveryPath, nil)
+		)
+
+		request.Host = "themis.example.com:6500"
+		h.ServeHTTP(response, request)
+		require.Equal(http.StatusOK, response.Code)
+
+		var d token.Discovery
+		require.NoError(json.Unmarshal(response.Body.Bytes(), &d))
+		assert.Equal(
+			token.Discovery{
+				Issuer:                           "themis",
+				JWKSURI:                          "http://themis.example.com:6500/.well-known/jwks.json",
+				IDTokenSigningAlgValuesSupported: []string{token.DefaultAlg},
+				IssueEndpoint:                    "https://themis.example.com:6501/issue",
+				ClaimsEndpoint:                   "https://claims.example.com/claims",
+			},
+			d,
+		)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		h, err := provideDiscoveryHandler(testDiscoveryIn(t, `{}`, token.DiscoveryOptions{KeysURL: "keys.example.com"}))
+		assert.ErrorIs(t, err, token.ErrInvalidEndpoint)
+		assert.Nil(t, h)
+	})
+}
@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package expression
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	// opTrue is a condition that is a bare reference to a bool
+	opTrue = ""
+
+	// opExists is a condition that tests if a reference is present
+	opExists = "exists"
+)
+
+// operators are the comparison operators, longest first so that two character
+// operators are preferred over their one character prefixes
+var operators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// Condition is a compiled condition.  A Condition is safe for concurrent use.
+type Condition struct {
+	source  string
+	ref     reference
+	op      string
+	operand any
+}
+
+// CompileCondition parses a condition and checks it against the declared variables
+func CompileCondition(source string, vars map[string]*Type) (*Condition, error) {
+	if len(source) > MaxLength {
+		return nil, syntaxError("conditions cannot be longer than %d bytes", MaxLength)
+	}
+
+	var (
+		c       = &Condition{source: source}
+		trimmed = strings.TrimSpace(source)
+		err     error
+	)
+
+	// the reference extends up to the first character that cannot be part of it
+	end := strings.IndexFunc(trimmed, func(c rune) bool { return c != '.' && !validNameRune(c) })
+	if end < 0 {
+		end = len(trimmed)
+	}
+
+	rest := strings.TrimSpace(trimmed[end:])
+	switch {
+	case len(rest) == 0:
+		c.op = opTrue
+	case rest == opExists:
+		c.op = opExists
+	default:
+		for _, op := range operators {
+			if strings.HasPrefix(rest, op) {
+				c.op = op
+				break
+			}
+		}
+
+		if len(c.op) == 0 {
+			return nil, syntaxError("expected exists or a comparison after %s in %q", trimmed[:end], source)
+		}
+
+		if c.operand, err = parseLiteral(strings.TrimSpace(rest[len(c.op):])); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.ref, err = parseReference(trimmed[:end], vars); err != nil {
+		return nil, err
+	}
+
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// parseLiteral parses the JSON literal of a comparison, which must be null, a bool, a number or a string
+func parseLiteral(source string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(source))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, syntaxError("malformed literal %q", source)
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, syntaxError("unexpected text after the literal %q", source)
+	}
+
+	switch v.(type) {
+	case nil, bool, json.Number, string:
+	default:
+		return nil, syntaxError("literals must be null, a bool, a number or a string, not %q", source)
+	}
+
+	literal, err := Normalize(v)
+	if err != nil {
+		return nil, syntaxError("malformed literal %q: %s", source, err)
+	}
+
+	return literal, nil
+}
+
+// check verifies that the reference of this condition has a type that can ever satisfy it
+func (c *Condition) check() error {
+	switch c.op {
+	case opExists:
+		return nil
+	case opTrue:
+		if !c.ref.typ.is(kindBool) {
+			return typeError("%s is a %s, not a bool", c.ref, c.ref.typ)
+		}
+
+		return nil
+	}
+
+	var compatible bool
+	switch c.operand.(type) {
+	case nil:
+		compatible = c.op == "==" || c.op == "!="
+	case bool:
+		compatible = (c.op == "==" || c.op == "!=") && c.ref.typ.is(kindBool)
+	case int64, float64:
+		compatible = c.ref.typ.is(kindNumber)
+	case string:
+		compatible = c.ref.typ.is(kindString)
+	}
+
+	if !compatible {
+		return typeError("%s is a %s, which cannot be compared with %s using %s", c.ref, c.ref.typ, typeName(c.operand), c.op)
+	}
+
+	return nil
+}
+
+// String returns the source of this condition
+func (c *Condition) String() string {
+	return c.source
+}
+
+// Eval evaluates this condition
+func (c *Condition) Eval(vars map[string]any) (bool, error) {
+	if c.op == opExists {
+		_, ok, err := c.ref.resolve(vars)
+		return ok && err == nil, err
+	}
+
+	v, err := c.ref.value(vars)
+	if err != nil {
+		return false, err
+	}
+
+	switch c.op {
+	case opTrue:
+		b, ok := v.(bool)
+		if !ok {
+			return false, evalError(c.ref, "expected a bool, not %s", typeName(v))
+		}
+
+		return b, nil
+	case "==":
+		return equal(v, c.operand), nil
+	case "!=":
+		return !equal(v, c.operand), nil
+	}
+
+	order, ok := compare(v, c.operand)
+	if !ok {
+		return false, evalError(c.ref, "a %s cannot be compared with %s", typeName(v), typeName(c.operand))
+	}
+
+	switch c.op {
+	case "<":
+		return order < 0, nil
+	case "<=":
+		return order <= 0, nil
+	case ">":
+		return order > 0, nil
+	default:
+		return order >= 0, nil
+	}
+}
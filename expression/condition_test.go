@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package expression
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCompileConditionSyntaxError(t *testing.T) {
+	testData := []string{
+		"",
+		"claims.",
+		"< 500",
+		"claims.trust <",
+		"claims.trust = 500",
+		"claims.trust < 500 && true",
+		"claims.trust < 'a'",
+		"claims.trust < [500]",
+		"claims.trust == {}",
+		"claims.trust == 1e400",
+		"claims.mac missing",
+		"has(claims.mac)",
+		"!tls.present",
+		"claims.mac.startsWith('AA')",
+		strings.Repeat(" ", MaxLength+1),
+	}
+
+	for _, source := range testData {
+		t.Run(source[:min(len(source), 20)], func(t *testing.T) {
+			c, err := CompileCondition(source, testVariableTypes())
+			assert.Nil(t, c)
+			assert.ErrorIs(t, err, ErrSyntax)
+		})
+	}
+}
+
+func testCompileConditionTypeError(t *testing.T) {
+	testData := []string{
+		"nosuch",
+		"nosuch exists",
+		"request.nosuch exists",
+		"request.format",
+		"tls.notAfter",
+		"request.format < 5",
+		"tls.notAfter == \"a\"",
+		"tls.present < true",
+		"request.format == true",
+		"tls.dnsNames == \"a\"",
+		"claims.mac < null",
+		"claims.enabled > false",
+	}
+
+	for _, source := range testData {
+		t.Run(source, func(t *testing.T) {
+			c, err := CompileCondition(source, testVariableTypes())
+			assert.Nil(t, c)
+			assert.ErrorIs(t, err, ErrType)
+		})
+	}
+}
+
+func TestCompileCondition(t *testing.T) {
+	t.Run("SyntaxError", testCompileConditionSyntaxError)
+	t.Run("TypeError", testCompileConditionTypeError)
+}
+
+func testConditionEvalSuccess(t *testing.T) {
+	testData := []struct {
+		source   string
+		expected bool
+	}{
+		{"tls.present", true},
+		{"claims.enabled", true},
+		{"claims.mac exists", true},
+		{"claims.none exists", true},
+		{"claims.nosuch exists", false},
+		{"claims.mac.nosuch exists", false},
+		{"request.metadata.serial exists", true},
+		{"claims.trust < 500", false},
+		{"claims.trust<5000", true},
+		{"claims.trust <= 1000", true},
+		{"claims.trust > 999.5", true},
+		{"claims.trust >= 1e3", true},
+		{"claims.trust == 1000", true},
+		{"claims.trust == 1000.0", true},
+		{"claims.trust != 1000", false},
+		{"claims.ratio < 2", true},
+		{"claims.mac == \"AABBCC\"", true},
+		{"claims.mac != \"aabbcc\"", true},
+		{"claims.mac < \"B\"", true},
+		{"claims.mac == 1", false},
+		{"claims.enabled == true", true},
+		{"claims.none == null", true},
+		{"claims.mac != null", true},
+		{"claims.partner-id == \"comcast\"", true},
+		{"request.format == \"jws\"", true},
+		{"tls.notAfter > 0", true},
+		{"  tls.present  ", true},
+	}
+
+	for _, record := range testData {
+		t.Run(record.source, func(t *testing.T) {
+			c, err := CompileCondition(record.source, testVariableTypes())
+			require.NoError(t, err)
+			assert.Equal(t, record.source, c.String())
+
+			actual, err := c.Eval(testVariables())
+			require.NoError(t, err)
+			assert.Equal(t, record.expected, actual)
+		})
+	}
+}
+
+func testConditionEvalError(t *testing.T) {
+	testData := []struct {
+		source    string
+		reference string
+	}{
+		{"claims.nosuch", "claims.nosuch"},
+		{"claims.mac", "claims.mac"},
+		{"claims.nosuch == 1", "claims.nosuch"},
+		{"claims.mac < 500", "claims.mac"},
+		{"claims.trust > \"a\"", "claims.trust"},
+		{"claims.capabilities.first == 1", "claims.capabilities.first"},
+		{"request.metadata.nosuch == \"ABC\"", "request.metadata.nosuch"},
+	}
+
+	for _, record := range testData {
+		t.Run(record.source, func(t *testing.T) {
+			c, err := CompileCondition(record.source, testVariableTypes())
+			require.NoError(t, err)
+
+			actual, err := c.Eval(testVariables())
+			assert.False(t, actual)
+			assert.ErrorIs(t, err, ErrEval)
+
+			var evalErr *EvalError
+			require.True(t, errors.As(err, &evalErr))
+			assert.Equal(t, record.reference, evalErr.Reference)
+		})
+	}
+}
+
+func testConditionEvalUnsupportedValue(t *testing.T) {
+	c, err := CompileCondition("claims.mac exists", testVariableTypes())
+	require.NoError(t, err)
+
+	actual, err := c.Eval(map[string]any{"claims": map[string]any{"mac": make(chan int)}})
+	assert.False(t, actual)
+	assert.ErrorIs(t, err, ErrUnsupportedValue)
+}
+
+func TestConditionEval(t *testing.T) {
+	t.Run("Success", testConditionEvalSuccess)
+	t.Run("Error", testConditionEvalError)
+	t.Run("UnsupportedValue", testConditionEvalUnsupportedValue)
+}
+
+func FuzzCompileCondition(f *testing.F) {
+	for _, seed := range []string{"", "tls.present", "claims.mac exists", "claims.trust < 500", "claims.mac == \"a\"", "claims.trust == 1e400"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, source string) {
+		c, err := CompileCondition(source, testVariableTypes())
+		if err != nil {
+			if !errors.Is(err, ErrSyntax) && !errors.Is(err, ErrType) {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			return
+		}
+
+		if _, err := c.Eval(testVariables()); err != nil && !errors.Is(err, ErrEval) {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+// Package expression implements the templates and conditions used to transform claims.  It is
+// deliberately not a general purpose expression language:  there are no operators, function calls
+// or literals beyond those described here, and nothing that is evaluated can loop or allocate more
+// than the values it refers to.
+//
+// A reference names a variable and, optionally, a path of fields within it, separated by dots,
+// e.g. claims.mac or request.metadata.serial.  Each name consists of letters, digits, underscores
+// and hyphens.
+//
+// A template is text with placeholders, each a reference followed by any number of filters, e.g.
+// mac:${claims.mac | lower}.  The filters are lower, upper and trim, and each applies to a string.
+// A template that is exactly one placeholder evaluates to the referenced value, whatever its type.
+// Any other template evaluates to a string, in which case each placeholder must refer to a bool,
+// a number or a string.  $$ is a literal $.
+//
+// A condition is either a reference to a bool, e.g. tls.present, a reference followed by exists,
+// e.g. claims.mac exists, or a reference compared with a JSON literal by one of ==, !=, <, <=, >
+// or >=, e.g. claims.trust < 500.  Only numbers and strings can be ordered.
+//
+// Templates and conditions are compiled against a set of typed variables, so that malformed
+// references, unknown fields and filters, and comparisons that can never succeed are reported
+// at startup rather than when a token is issued.
+package expression
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxLength is the longest template or condition, in bytes, that can be compiled
+const MaxLength = 4096
+
+var (
+	// ErrSyntax indicates that a template or condition is malformed
+	ErrSyntax = errors.New("syntax error")
+
+	// ErrType indicates that a template or condition is well formed but refers to unknown variables,
+	// fields or filters, or to values of the wrong types
+	ErrType = errors.New("type error")
+
+	// ErrEval indicates that a template or condition could not be evaluated, such as when a field
+	// is missing
+	ErrEval = errors.New("evaluation error")
+
+	// ErrUnsupportedValue indicates that a variable's value cannot be used in a template or condition
+	ErrUnsupportedValue = errors.New("unsupported value")
+)
+
+// EvalError is returned when a template or condition cannot be evaluated.  Every such failure is
+// caused by the value of a reference, which allows callers to tell which input was at fault.
+type EvalError struct {
+	// Reference is the reference whose value caused the failure, e.g. claims.mac
+	Reference string
+
+	// Err describes the failure.  It always wraps ErrEval.
+	Err error
+}
+
+func (e *EvalError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Reference, e.Err)
+}
+
+func (e *EvalError) Unwrap() error {
+	return e.Err
+}
+
+// evalError creates the EvalError for a reference
+func evalError(r reference, format string, args ...any) error {
+	return &EvalError{
+		Reference: r.String(),
+		Err:       fmt.Errorf("%w: %s", ErrEval, fmt.Sprintf(format, args...)),
+	}
+}
+
+// syntaxError reports a malformed template or condition
+func syntaxError(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrSyntax, fmt.Sprintf(format, args...))
+}
+
+// typeError reports a template or condition that is well formed but not well typed
+func typeError(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrType, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package expression
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testVariableTypes() map[string]*Type {
+	return map[string]*Type{
+		"claims": Any,
+		"request": Object(map[string]*Type{
+			"format":   String,
+			"metadata": Any,
+		}),
+		"tls": Object(map[string]*Type{
+			"present":  Bool,
+			"notAfter": Number,
+			"dnsNames": List,
+		}),
+	}
+}
+
+func testVariables() map[string]any {
+	return map[string]any{
+		"claims": map[string]any{
+			"mac":          "AABBCC",
+			"partner-id":   "comcast",
+			"trust":        json.Number("1000"),
+			"ratio":        1.5,
+			"enabled":      true,
+			"capabilities": json.RawMessage(`["x1:issuer:test:.*:all"]`),
+			"nested":       map[string]any{"value": " Padded "},
+			"none":         nil,
+		},
+		"request": map[string]any{
+			"format":   "jws",
+			"metadata": map[string]any{"serial": "ABC"},
+		},
+		"tls": map[string]any{
+			"present":  true,
+			"notAfter": 1700000000,
+			"dnsNames": []string{"device.example.com"},
+		},
+	}
+}
+
+func TestEvalError(t *testing.T) {
+	err := evalError(reference{path: []string{"claims", "mac"}}, "missing")
+	assert.ErrorIs(t, err, ErrEval)
+	assert.Equal(t, "claims.mac: evaluation error: missing", err.Error())
+
+	var evalErr *EvalError
+	assert.True(t, errors.As(err, &evalErr))
+	assert.Equal(t, "claims.mac", evalErr.Reference)
+}
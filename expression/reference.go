@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reference is a variable and a path of fields within it, e.g. request.metadata.serial
+type reference struct {
+	path []string
+	typ  *Type
+}
+
+func (r reference) String() string {
+	return strings.Join(r.path, ".")
+}
+
+// validName tests if a variable or field name is made up of letters, digits, underscores and hyphens
+func validName(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+
+	for _, c := range name {
+		if !validNameRune(c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validNameRune tests if a character can be part of a variable or field name
+func validNameRune(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// parseReference parses a reference and checks it against the declared variables
+func parseReference(source string, vars map[string]*Type) (reference, error) {
+	r := reference{path: strings.Split(source, ".")}
+	for _, name := range r.path {
+		if !validName(name) {
+			return reference{}, syntaxError("malformed reference %q", source)
+		}
+	}
+
+	var ok bool
+	if r.typ, ok = vars[r.path[0]]; !ok {
+		return reference{}, typeError("undeclared variable %s", r.path[0])
+	}
+
+	for i, name := range r.path[1:] {
+		switch r.typ.kind {
+		case kindAny:
+		case kindObject:
+			field, ok := r.typ.fields[name]
+			if !ok {
+				return reference{}, typeError("%s has no field %s; its fields are %s", strings.Join(r.path[:i+1], "."), name, r.typ.fieldNames())
+			}
+
+			r.typ = field
+		default:
+			return reference{}, typeError("cannot select field %s of %s, which is a %s", name, strings.Join(r.path[:i+1], "."), r.typ)
+		}
+	}
+
+	return r, nil
+}
+
+// resolve returns the normalized value of this reference.  If the variable or any field along its
+// path is missing, or is not a map, this method returns false.
+func (r reference) resolve(vars map[string]any) (any, bool, error) {
+	v, ok := vars[r.path[0]]
+	if !ok {
+		return nil, false, nil
+	}
+
+	for _, name := range r.path[1:] {
+		m, ok := v.(map[string]any)
+		if !ok {
+			nv, err := Normalize(v)
+			if err != nil {
+				return nil, false, &EvalError{Reference: r.String(), Err: fmt.Errorf("%w: %w", ErrEval, err)}
+			}
+
+			if m, ok = nv.(map[string]any); !ok {
+				// only maps have fields
+				return nil, false, nil
+			}
+		}
+
+		if v, ok = m[name]; !ok {
+			return nil, false, nil
+		}
+	}
+
+	nv, err := Normalize(v)
+	if err != nil {
+		return nil, false, &EvalError{Reference: r.String(), Err: fmt.Errorf("%w: %w", ErrEval, err)}
+	}
+
+	return nv, true, nil
+}
+
+// value returns the normalized value of this reference, which must be present
+func (r reference) value(vars map[string]any) (any, error) {
+	v, ok, err := r.resolve(vars)
+	if err == nil && !ok {
+		err = evalError(r, "missing")
+	}
+
+	return v, err
+}
@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package expression
+
+import (
+	"strconv"
+	"strings"
+)
+
+// filters are the functions that can be applied to placeholders, by name
+var filters = map[string]func(string) string{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+}
+
+// placeholder is a reference within a template, along with the filters applied to its value
+type placeholder struct {
+	ref     reference
+	filters []func(string) string
+}
+
+// eval returns the value of this placeholder
+func (p placeholder) eval(vars map[string]any) (any, error) {
+	v, err := p.ref.value(vars)
+	if err != nil || len(p.filters) == 0 {
+		return v, err
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return nil, evalError(p.ref, "filters apply to strings, not %s", typeName(v))
+	}
+
+	for _, f := range p.filters {
+		s = f(s)
+	}
+
+	return s, nil
+}
+
+// Template is a compiled template.  A Template is safe for concurrent use.
+type Template struct {
+	source string
+
+	// text and placeholders alternate, beginning and ending with text, so
+	// there is always one more text than there are placeholders
+	text         []string
+	placeholders []placeholder
+}
+
+// CompileTemplate parses a template and checks it against the declared variables
+func CompileTemplate(source string, vars map[string]*Type) (*Template, error) {
+	if len(source) > MaxLength {
+		return nil, syntaxError("templates cannot be longer than %d bytes", MaxLength)
+	}
+
+	var (
+		t    = &Template{source: source}
+		text strings.Builder
+	)
+
+	for rest := source; len(rest) > 0; {
+		i := strings.IndexByte(rest, '$')
+		if i < 0 {
+			text.WriteString(rest)
+			break
+		}
+
+		text.WriteString(rest[:i])
+		rest = rest[i+1:]
+		switch {
+		case strings.HasPrefix(rest, "$"):
+			text.WriteByte('$')
+			rest = rest[1:]
+
+		case strings.HasPrefix(rest, "{"):
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return nil, syntaxError("unterminated placeholder in %q", source)
+			}
+
+			p, err := parsePlaceholder(rest[1:end], vars)
+			if err != nil {
+				return nil, err
+			}
+
+			t.text = append(t.text, text.String())
+			t.placeholders = append(t.placeholders, p)
+			text.Reset()
+			rest = rest[end+1:]
+
+		default:
+			return nil, syntaxError("a $ must be followed by { or by another $ in %q", source)
+		}
+	}
+
+	t.text = append(t.text, text.String())
+	if !t.whole() {
+		for _, p := range t.placeholders {
+			if len(p.filters) == 0 && !p.ref.typ.is(kindBool, kindNumber, kindString) {
+				return nil, typeError("%s is a %s, which cannot be part of a string", p.ref, p.ref.typ)
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// parsePlaceholder parses the contents of a placeholder, e.g. claims.mac | lower
+func parsePlaceholder(source string, vars map[string]*Type) (placeholder, error) {
+	stages := strings.Split(source, "|")
+	ref, err := parseReference(strings.TrimSpace(stages[0]), vars)
+	if err != nil {
+		return placeholder{}, err
+	}
+
+	p := placeholder{ref: ref}
+	for _, stage := range stages[1:] {
+		name := strings.TrimSpace(stage)
+		f, ok := filters[name]
+		switch {
+		case !ok:
+			return placeholder{}, typeError("unknown filter %q", name)
+		case !ref.typ.is(kindString):
+			return placeholder{}, typeError("%s is a %s, but filters apply to strings", ref, ref.typ)
+		}
+
+		p.filters = append(p.filters, f)
+	}
+
+	return p, nil
+}
+
+// whole tests if this template is exactly one placeholder
+func (t *Template) whole() bool {
+	return len(t.placeholders) == 1 && len(t.text[0]) == 0 && len(t.text[1]) == 0
+}
+
+// String returns the source of this template
+func (t *Template) String() string {
+	return t.source
+}
+
+// Eval evaluates this template.  The result is always one of nil, bool, int64, float64,
+// string, []any or map[string]any.
+func (t *Template) Eval(vars map[string]any) (any, error) {
+	if t.whole() {
+		return t.placeholders[0].eval(vars)
+	}
+
+	var result strings.Builder
+	for i, p := range t.placeholders {
+		result.WriteString(t.text[i])
+		v, err := p.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+
+		switch v := v.(type) {
+		case bool:
+			result.WriteString(strconv.FormatBool(v))
+		case int64:
+			result.WriteString(strconv.FormatInt(v, 10))
+		case float64:
+			result.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		case string:
+			result.WriteString(v)
+		default:
+			return nil, evalError(p.ref, "a %s cannot be part of a string", typeName(v))
+		}
+	}
+
+	result.WriteString(t.text[len(t.text)-1])
+	return result.String(), nil
+}
@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package expression
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCompileTemplateSyntaxError(t *testing.T) {
+	testData := []string{
+		"${",
+		"${claims.mac",
+		"${}",
+		"${ | lower}",
+		"${claims.}",
+		"${.mac}",
+		"${claims..mac}",
+		"${claims['mac']}",
+		"${'mac:' + claims.mac}",
+		"${lower(claims.mac)}",
+		"$mac",
+		"mac:$",
+		strings.Repeat(" ", MaxLength+1),
+	}
+
+	for _, source := range testData {
+		t.Run(source[:min(len(source), 20)], func(t *testing.T) {
+			tmpl, err := CompileTemplate(source, testVariableTypes())
+			assert.Nil(t, tmpl)
+			assert.ErrorIs(t, err, ErrSyntax)
+		})
+	}
+}
+
+func testCompileTemplateTypeError(t *testing.T) {
+	testData := []string{
+		"${nosuch}",
+		"${request.nosuch}",
+		"${request.format.length}",
+		"${tls.dnsNames.first}",
+		"${claims.mac | nosuch}",
+		"${tls.notAfter | lower}",
+		"${request.format | lower | nosuch}",
+		"names: ${tls.dnsNames}",
+		"${request}${request.format}",
+	}
+
+	for _, source := range testData {
+		t.Run(source, func(t *testing.T) {
+			tmpl, err := CompileTemplate(source, testVariableTypes())
+			assert.Nil(t, tmpl)
+			assert.ErrorIs(t, err, ErrType)
+		})
+	}
+}
+
+func TestCompileTemplate(t *testing.T) {
+	t.Run("SyntaxError", testCompileTemplateSyntaxError)
+	t.Run("TypeError", testCompileTemplateTypeError)
+}
+
+func testTemplateEvalSuccess(t *testing.T) {
+	testData := []struct {
+		source   string
+		expected any
+	}{
+		{"", ""},
+		{"static", "static"},
+		{"$$1.00", "$1.00"},
+		{"${claims.mac}", "AABBCC"},
+		{"${ claims.mac | lower }", "aabbcc"},
+		{"${claims.nested.value|trim|upper}", "PADDED"},
+		{"mac:${claims.mac | lower}", "mac:aabbcc"},
+		{"${claims.partner-id}/${request.format}", "comcast/jws"},
+		{"${claims.trust}", int64(1000)},
+		{"${claims.ratio}", 1.5},
+		{"${claims.enabled}", true},
+		{"${claims.none}", nil},
+		{"${claims.capabilities}", []any{"x1:issuer:test:.*:all"}},
+		{"${request.metadata}", map[string]any{"serial": "ABC"}},
+		{"${tls.dnsNames}", []any{"device.example.com"}},
+		{"trust=${claims.trust}, ratio=${claims.ratio}, enabled=${claims.enabled}", "trust=1000, ratio=1.5, enabled=true"},
+		{"${request.metadata.serial}-${tls.notAfter}", "ABC-1700000000"},
+	}
+
+	for _, record := range testData {
+		t.Run(record.source, func(t *testing.T) {
+			tmpl, err := CompileTemplate(record.source, testVariableTypes())
+			require.NoError(t, err)
+			assert.Equal(t, record.source, tmpl.String())
+
+			actual, err := tmpl.Eval(testVariables())
+			require.NoError(t, err)
+			assert.Equal(t, record.expected, actual)
+		})
+	}
+}
+
+func testTemplateEvalError(t *testing.T) {
+	testData := []struct {
+		source    string
+		reference string
+	}{
+		{"${claims.nosuch}", "claims.nosuch"},
+		{"${claims.mac.nosuch}", "claims.mac.nosuch"},
+		{"${claims.capabilities.first}", "claims.capabilities.first"},
+		{"${claims.trust | lower}", "claims.trust"},
+		{"sub:${claims.none}", "claims.none"},
+		{"sub:${claims.capabilities}", "claims.capabilities"},
+		{"sub:${request.metadata.nosuch}", "request.metadata.nosuch"},
+	}
+
+	for _, record := range testData {
+		t.Run(record.source, func(t *testing.T) {
+			tmpl, err := CompileTemplate(record.source, testVariableTypes())
+			require.NoError(t, err)
+
+			actual, err := tmpl.Eval(testVariables())
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, ErrEval)
+
+			var evalErr *EvalError
+			require.True(t, errors.As(err, &evalErr))
+			assert.Equal(t, record.reference, evalErr.Reference)
+		})
+	}
+}
+
+func testTemplateEvalMissingVariable(t *testing.T) {
+	tmpl, err := CompileTemplate("${request.format}", testVariableTypes())
+	require.NoError(t, err)
+
+	actual, err := tmpl.Eval(map[string]any{})
+	assert.Nil(t, actual)
+	assert.ErrorIs(t, err, ErrEval)
+}
+
+func testTemplateEvalUnsupportedValue(t *testing.T) {
+	tmpl, err := CompileTemplate("${claims.mac}", testVariableTypes())
+	require.NoError(t, err)
+
+	actual, err := tmpl.Eval(map[string]any{"claims": map[string]any{"mac": make(chan int)}})
+	assert.Nil(t, actual)
+	assert.ErrorIs(t, err, ErrEval)
+	assert.ErrorIs(t, err, ErrUnsupportedValue)
+}
+
+func TestTemplateEval(t *testing.T) {
+	t.Run("Success", testTemplateEvalSuccess)
+	t.Run("Error", testTemplateEvalError)
+	t.Run("MissingVariable", testTemplateEvalMissingVariable)
+	t.Run("UnsupportedValue", testTemplateEvalUnsupportedValue)
+}
+
+func FuzzCompileTemplate(f *testing.F) {
+	for _, seed := range []string{"", "$$", "mac:${claims.mac | lower}", "${request.metadata.serial}-${tls.notAfter}", "${", "${claims.|}"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, source string) {
+		tmpl, err := CompileTemplate(source, testVariableTypes())
+		if err != nil {
+			if !errors.Is(err, ErrSyntax) && !errors.Is(err, ErrType) {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			return
+		}
+
+		if _, err := tmpl.Eval(testVariables()); err != nil && !errors.Is(err, ErrEval) {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package expression
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+type kind int
+
+const (
+	kindAny kind = iota
+	kindBool
+	kindNumber
+	kindString
+	kindList
+	kindObject
+)
+
+// Type is the declared type of a variable or of one of its fields
+type Type struct {
+	kind kind
+
+	// fields are the fields of an object
+	fields map[string]*Type
+}
+
+var (
+	// Any is the type of values whose type is only known when they are evaluated, such as claims.
+	// Any field can be selected from a value of this type.
+	Any = &Type{kind: kindAny}
+
+	// Bool is the type of true and false
+	Bool = &Type{kind: kindBool}
+
+	// Number is the type of integers and floating point numbers
+	Number = &Type{kind: kindNumber}
+
+	// String is the type of strings
+	String = &Type{kind: kindString}
+
+	// List is the type of lists
+	List = &Type{kind: kindList}
+)
+
+// Object returns the type of maps with a fixed set of fields.  Selecting any other field of
+// an object is a type error.
+func Object(fields map[string]*Type) *Type {
+	return &Type{kind: kindObject, fields: fields}
+}
+
+func (t *Type) String() string {
+	switch t.kind {
+	case kindBool:
+		return "bool"
+	case kindNumber:
+		return "number"
+	case kindString:
+		return "string"
+	case kindList:
+		return "list"
+	case kindObject:
+		return "object"
+	default:
+		return "any"
+	}
+}
+
+// is tests if this type is either Any or one of the given kinds
+func (t *Type) is(kinds ...kind) bool {
+	return t.kind == kindAny || slices.Contains(kinds, t.kind)
+}
+
+// fieldNames returns the sorted field names of an object, for error messages
+func (t *Type) fieldNames() string {
+	return strings.Join(slices.Sorted(maps.Keys(t.fields)), ", ")
+}
@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package expression
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Normalize converts a value, such as a claim, into the values that templates and conditions operate on: nil,
+// bool, int64, float64, string, []any and map[string]any.  Other values, including json.RawMessage,
+// are converted by way of their JSON encoding.
+func Normalize(v any) (any, error) {
+	switch v := v.(type) {
+	case nil, bool, int64, float64, string:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case float32:
+		return float64(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedValue, err)
+		}
+
+		return f, nil
+	case []string:
+		list := make([]any, 0, len(v))
+		for _, e := range v {
+			list = append(list, e)
+		}
+
+		return list, nil
+	case []any:
+		list := make([]any, 0, len(v))
+		for _, e := range v {
+			ne, err := Normalize(e)
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, ne)
+		}
+
+		return list, nil
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			ne, err := Normalize(e)
+			if err != nil {
+				return nil, err
+			}
+
+			m[k] = ne
+		}
+
+		return m, nil
+	case json.RawMessage:
+		return normalizeJSON(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedValue, err)
+		}
+
+		return normalizeJSON(data)
+	}
+}
+
+// normalizeJSON decodes JSON, keeping integers as int64
+func normalizeJSON(data []byte) (any, error) {
+	var v any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedValue, err)
+	}
+
+	return Normalize(v)
+}
+
+// typeName returns the name of the type of a normalized value, for error messages
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case int64, float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// toDouble converts a numeric value to a float64
+func toDouble(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// equal tests if two normalized values are equal.  Values of different types are never
+// equal, except for ints and doubles with the same numeric value.
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		bb, ok := b.(bool)
+		return ok && a == bb
+	case string:
+		bs, ok := b.(string)
+		return ok && a == bs
+	case int64, float64:
+		if bi, ok := b.(int64); ok {
+			if ai, ok := a.(int64); ok {
+				return ai == bi
+			}
+		}
+
+		af, _ := toDouble(a)
+		bf, ok := toDouble(b)
+		return ok && af == bf
+	case []any:
+		bl, ok := b.([]any)
+		if !ok || len(a) != len(bl) {
+			return false
+		}
+
+		for i := range a {
+			if !equal(a[i], bl[i]) {
+				return false
+			}
+		}
+
+		return true
+	case map[string]any:
+		bm, ok := b.(map[string]any)
+		if !ok || len(a) != len(bm) {
+			return false
+		}
+
+		for k, av := range a {
+			bv, ok := bm[k]
+			if !ok || !equal(av, bv) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+// compare orders two numbers or two strings, returning false if they cannot be ordered
+func compare(a, b any) (int, bool) {
+	if as, ok := a.(string); ok {
+		bs, ok := b.(string)
+		return strings.Compare(as, bs), ok
+	}
+
+	if ai, ok := a.(int64); ok {
+		if bi, ok := b.(int64); ok {
+			switch {
+			case ai < bi:
+				return -1, true
+			case ai > bi:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	}
+
+	af, aok := toDouble(a)
+	bf, bok := toDouble(b)
+	if !aok || !bok || math.IsNaN(af) || math.IsNaN(bf) {
+		return 0, false
+	}
+
+	switch {
+	case af < bf:
+		return -1, true
+	case af > bf:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
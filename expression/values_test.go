@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package expression
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	testData := []struct {
+		name     string
+		value    any
+		expected any
+	}{
+		{"Nil", nil, nil},
+		{"Bool", true, true},
+		{"Int", 12, int64(12)},
+		{"Int32", int32(12), int64(12)},
+		{"Int64", int64(12), int64(12)},
+		{"Float32", float32(1.5), 1.5},
+		{"Float64", 1.5, 1.5},
+		{"String", "a", "a"},
+		{"IntNumber", json.Number("12"), int64(12)},
+		{"FloatNumber", json.Number("1.5"), 1.5},
+		{"Strings", []string{"a", "b"}, []any{"a", "b"}},
+		{"List", []any{1, "a"}, []any{int64(1), "a"}},
+		{"Map", map[string]any{"a": 1}, map[string]any{"a": int64(1)}},
+		{"RawMessage", json.RawMessage(`{"a": [1, 1.5, "b", null]}`), map[string]any{"a": []any{int64(1), 1.5, "b", nil}}},
+		{"Struct", struct{ A int }{A: 1}, map[string]any{"A": int64(1)}},
+		{"Ints", []int{1, 2}, []any{int64(1), int64(2)}},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			actual, err := Normalize(record.value)
+			assert.NoError(t, err)
+			assert.Equal(t, record.expected, actual)
+		})
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		for _, v := range []any{json.Number("x"), json.RawMessage(`{`), make(chan int), []any{make(chan int)}} {
+			actual, err := Normalize(v)
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, ErrUnsupportedValue)
+		}
+	})
+}
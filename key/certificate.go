@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"crypto"
+	"crypto/sha1" // nolint:gosec
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	// PEMTypeCertificate is the PEM block type for X.509 certificates
+	PEMTypeCertificate = "CERTIFICATE"
+)
+
+var (
+	ErrNoCertificates      = errors.New("no certificates found")
+	ErrCertificateMismatch = errors.New("the certificate does not match the key")
+	ErrInvalidChain        = errors.New("the certificate chain is not valid")
+)
+
+// ReadCertificateChain reads every PEM-encoded certificate in a file.  The leaf certificate,
+// which certifies the signing key, must be first, followed by any intermediates.
+func ReadCertificateChain(file string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var chain []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != PEMTypeCertificate {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse certificate in %s: %w", file, err)
+		}
+
+		chain = append(chain, cert)
+	}
+
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoCertificates, file)
+	}
+
+	return chain, nil
+}
+
+// CheckCertificateChain verifies that a chain's leaf certificate is for the given public key, and that
+// each certificate in the chain is signed by the next.  The chain is not verified against any roots,
+// as that is the responsibility of the verifiers that trust it.
+func CheckCertificateChain(public crypto.PublicKey, chain []*x509.Certificate) error {
+	if len(chain) == 0 {
+		return ErrNoCertificates
+	}
+
+	leafKey, ok := chain[0].PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !leafKey.Equal(public) {
+		return fmt.Errorf("%w: leaf certificate %s", ErrCertificateMismatch, chain[0].Subject)
+	}
+
+	for i := 0; i < len(chain)-1; i++ {
+		if err := chain[i].CheckSignatureFrom(chain[i+1]); err != nil {
+			return fmt.Errorf("%w: %s is not signed by %s: %s", ErrInvalidChain, chain[i].Subject, chain[i+1].Subject, err)
+		}
+	}
+
+	return nil
+}
+
+// CertificateThumbprint returns the x5t value for a certificate, the base64url encoded SHA-1 digest of its DER
+func CertificateThumbprint(cert *x509.Certificate) string {
+	digest := sha1.Sum(cert.Raw) // nolint:gosec
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// CertificateThumbprintS256 returns the x5t#S256 value for a certificate, the base64url encoded SHA-256 digest of its DER
+func CertificateThumbprintS256(cert *x509.Certificate) string {
+	digest := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
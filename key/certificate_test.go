@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCertificate issues a certificate for a public key.  If parent is nil, the
+// certificate is self-signed by signer.
+func newTestCertificate(t *testing.T, cn string, public crypto.PublicKey, parent *x509.Certificate, signer crypto.Signer) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+
+	if parent == nil {
+		parent = template
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, public, signer)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func writeTestCertificates(t *testing.T, file string, chain ...*x509.Certificate) {
+	var buffer bytes.Buffer
+	for _, cert := range chain {
+		require.NoError(t, pem.Encode(&buffer, &pem.Block{Type: PEMTypeCertificate, Bytes: cert.Raw}))
+	}
+
+	require.NoError(t, os.WriteFile(file, buffer.Bytes(), 0600))
+}
+
+func TestCertificateChain(t *testing.T) {
+	var (
+		directory = t.TempDir()
+		keyFile   = filepath.Join(directory, "key.pem")
+		chainFile = filepath.Join(directory, "chain.pem")
+	)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ca := newTestCertificate(t, "ca", caKey.Public(), nil, caKey)
+
+	writeTestReloadKey(t, keyFile, Descriptor{Kid: "test", Type: KeyTypeECDSA, Bits: 256})
+	p, err := ReadPair("test", keyFile)
+	require.NoError(t, err)
+	leaf := newTestCertificate(t, "leaf", p.Signer().Public(), ca, caKey)
+
+	t.Run("Success", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+		)
+
+		writeTestCertificates(t, chainFile, leaf, ca)
+		p, err := NewRegistry(nil).Register(Descriptor{Kid: "test", File: keyFile, CertificateFile: chainFile, Alg: "ES256"})
+		require.NoError(err)
+		require.Len(p.Certificates(), 2)
+		assert.Equal(leaf.Raw, p.Certificates()[0].Raw)
+		assert.Equal("ES256", p.Alg())
+
+		var buffer bytes.Buffer
+		_, err = p.WriteJWK(&buffer)
+		require.NoError(err)
+
+		published, err := jwk.ParseKey(buffer.Bytes())
+		require.NoError(err)
+		require.Len(published.X509CertChain(), 2)
+		assert.Equal(leaf.Raw, published.X509CertChain()[0].Raw)
+		assert.Equal(ca.Raw, published.X509CertChain()[1].Raw)
+		assert.Equal(CertificateThumbprint(leaf), published.X509CertThumbprint())
+		assert.Equal(CertificateThumbprintS256(leaf), published.X509CertThumbprintS256())
+		assert.Len(published.X509CertThumbprintS256(), 43)
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		writeTestCertificates(t, chainFile, ca)
+		p, err := NewRegistry(nil).Register(Descriptor{Kid: "test", File: keyFile, CertificateFile: chainFile})
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrCertificateMismatch)
+	})
+
+	t.Run("InvalidChain", func(t *testing.T) {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		writeTestCertificates(t, chainFile, leaf, newTestCertificate(t, "other", otherKey.Public(), nil, otherKey))
+		p, err := NewRegistry(nil).Register(Descriptor{Kid: "test", File: keyFile, CertificateFile: chainFile})
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidChain)
+	})
+
+	t.Run("NoCertificates", func(t *testing.T) {
+		writeTestCertificates(t, chainFile)
+		p, err := NewRegistry(nil).Register(Descriptor{Kid: "test", File: keyFile, CertificateFile: chainFile})
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrNoCertificates)
+
+		p, err = NewRegistry(nil).Register(Descriptor{Kid: "test", File: keyFile, CertificateFile: chainFile + ".nosuch"})
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Symmetric", func(t *testing.T) {
+		writeTestCertificates(t, chainFile, leaf)
+		p, err := NewRegistry(nil).Register(Descriptor{Kid: "test", Type: KeyTypeSecret, CertificateFile: chainFile})
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrCertificateMismatch)
+	})
+
+	t.Run("Rotation", func(t *testing.T) {
+		writeTestCertificates(t, chainFile, leaf)
+		d := Descriptor{Kid: "test", File: keyFile, CertificateFile: chainFile, RotationInterval: time.Hour}
+		registry := NewRegistry(nil)
+		current, err := registry.Register(d)
+		require.NoError(t, err)
+
+		r, err := NewRotator(registry, d, current, time.Hour, nil, nil)
+		assert.Nil(t, r)
+		assert.ErrorIs(t, err, ErrRotateCertificate)
+	})
+}
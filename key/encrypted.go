@@ -0,0 +1,276 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/pbkdf2"
+	"crypto/sha1" // nolint:gosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// PEMTypeEncryptedPrivateKey is the PEM block type for encrypted PKCS#8 private keys
+	PEMTypeEncryptedPrivateKey = "ENCRYPTED PRIVATE KEY"
+)
+
+// The limits on key derivation parameters.  These parameters come from the key file, and a key file
+// is read at startup and on every reload, so without limits a malformed file could consume any
+// amount of memory or CPU.
+const (
+	// maxPBKDF2Iterations is the largest PBKDF2 iteration count
+	maxPBKDF2Iterations = 10_000_000
+
+	// maxScryptCost is the largest scrypt cost parameter, N
+	maxScryptCost = 1 << 20
+
+	// maxScryptMemory is the most memory scrypt can use, in bytes, which is 128 * N * r
+	maxScryptMemory = 1 << 30
+
+	// maxScryptWork limits the CPU scrypt can use, which is proportional to N * r * p
+	maxScryptWork = 1 << 26
+)
+
+var (
+	ErrPassphraseRequired        = errors.New("the key is encrypted, but no passphrase was supplied")
+	ErrIncorrectPassphrase       = errors.New("unable to decrypt key: the passphrase is incorrect")
+	ErrUnsupportedKeyEncryption  = errors.New("unsupported private key encryption")
+	ErrMalformedEncryptedKeyData = errors.New("malformed encrypted private key")
+)
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidScrypt = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11591, 4, 11}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA224 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 8}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+// encryptedPrivateKeyInfo is the PKCS#8 structure for encrypted keys, from RFC 5208
+type encryptedPrivateKeyInfo struct {
+	EncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedData       []byte
+}
+
+// pbes2Params are the PBES2 parameters from RFC 8018
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params are the PBKDF2 parameters from RFC 8018.  Only the specified salt is supported.
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// scryptParams are the scrypt parameters from RFC 7914
+type scryptParams struct {
+	Salt                     []byte
+	CostParameter            int
+	BlockSize                int
+	ParallelizationParameter int
+	KeyLength                int `asn1:"optional"`
+}
+
+// pbes2Cipher describes a supported PBES2 encryption scheme
+type pbes2Cipher struct {
+	keySize  int
+	newBlock func([]byte) (cipher.Block, error)
+}
+
+var pbes2Ciphers = map[string]pbes2Cipher{
+	oidAES128CBC.String():  {keySize: 16, newBlock: aes.NewCipher},
+	oidAES192CBC.String():  {keySize: 24, newBlock: aes.NewCipher},
+	oidAES256CBC.String():  {keySize: 32, newBlock: aes.NewCipher},
+	oidDESEDE3CBC.String(): {keySize: 24, newBlock: des.NewTripleDESCipher},
+}
+
+var pbkdf2Hashes = map[string]func() hash.Hash{
+	oidHMACWithSHA1.String():   sha1.New,
+	oidHMACWithSHA224.String(): sha256.New224,
+	oidHMACWithSHA256.String(): sha256.New,
+	oidHMACWithSHA384.String(): sha512.New384,
+	oidHMACWithSHA512.String(): sha512.New,
+}
+
+// DecryptPKCS8PrivateKey decrypts the DER bytes of an encrypted PKCS#8 private key, as found in a PEM block
+// of type PEMTypeEncryptedPrivateKey.  The returned bytes are an unencrypted PKCS#8 private key suitable for
+// x509.ParsePKCS8PrivateKey.
+//
+// Only PBES2 is supported, with either PBKDF2 or scrypt key derivation and AES-CBC or DES-EDE3-CBC encryption.
+// This covers the output of modern versions of openssl.  Any other scheme results in ErrUnsupportedKeyEncryption.
+// A passphrase that fails to decrypt the key results in ErrIncorrectPassphrase.
+func DecryptPKCS8PrivateKey(der, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrPassphraseRequired
+	}
+
+	var info encryptedPrivateKeyInfo
+	if err := unmarshalDER(der, &info); err != nil {
+		return nil, err
+	}
+
+	if !info.EncryptionAlgorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("%w: encryption scheme %s is not PBES2", ErrUnsupportedKeyEncryption, info.EncryptionAlgorithm.Algorithm)
+	}
+
+	var params pbes2Params
+	if err := unmarshalDER(info.EncryptionAlgorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, err
+	}
+
+	c, ok := pbes2Ciphers[params.EncryptionScheme.Algorithm.String()]
+	if !ok {
+		return nil, fmt.Errorf("%w: cipher %s", ErrUnsupportedKeyEncryption, params.EncryptionScheme.Algorithm)
+	}
+
+	var iv []byte
+	if err := unmarshalDER(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, err
+	}
+
+	key, err := deriveKey(params.KeyDerivationFunc, passphrase, c.keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := c.newBlock(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(iv) != block.BlockSize() || len(info.EncryptedData) == 0 || len(info.EncryptedData)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("%w: invalid iv or ciphertext length", ErrMalformedEncryptedKeyData)
+	}
+
+	decrypted := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, info.EncryptedData)
+
+	// the only indication of a wrong passphrase is invalid padding, or garbage where
+	// the private key should be
+	decrypted, ok = unpad(decrypted, block.BlockSize())
+	if !ok {
+		return nil, ErrIncorrectPassphrase
+	}
+
+	var check asn1.RawValue
+	if rest, err := asn1.Unmarshal(decrypted, &check); err != nil || len(rest) > 0 {
+		return nil, ErrIncorrectPassphrase
+	}
+
+	return decrypted, nil
+}
+
+// deriveKey produces an encryption key of the given size using a PBES2 key derivation function
+func deriveKey(kdf pkix.AlgorithmIdentifier, passphrase []byte, keySize int) ([]byte, error) {
+	switch {
+	case kdf.Algorithm.Equal(oidPBKDF2):
+		var params pbkdf2Params
+		if err := unmarshalDER(kdf.Parameters.FullBytes, &params); err != nil {
+			return nil, err
+		}
+
+		if params.KeyLength > 0 && params.KeyLength != keySize {
+			return nil, fmt.Errorf("%w: key length %d does not match the cipher", ErrMalformedEncryptedKeyData, params.KeyLength)
+		} else if params.IterationCount <= 0 || params.IterationCount > maxPBKDF2Iterations {
+			return nil, fmt.Errorf("%w: invalid iteration count %d", ErrMalformedEncryptedKeyData, params.IterationCount)
+		}
+
+		prf := sha1.New
+		if len(params.PRF.Algorithm) > 0 {
+			var ok bool
+			if prf, ok = pbkdf2Hashes[params.PRF.Algorithm.String()]; !ok {
+				return nil, fmt.Errorf("%w: pbkdf2 prf %s", ErrUnsupportedKeyEncryption, params.PRF.Algorithm)
+			}
+		}
+
+		return pbkdf2.Key(prf, string(passphrase), params.Salt, params.IterationCount, keySize)
+
+	case kdf.Algorithm.Equal(oidScrypt):
+		var params scryptParams
+		if err := unmarshalDER(kdf.Parameters.FullBytes, &params); err != nil {
+			return nil, err
+		}
+
+		if params.KeyLength > 0 && params.KeyLength != keySize {
+			return nil, fmt.Errorf("%w: key length %d does not match the cipher", ErrMalformedEncryptedKeyData, params.KeyLength)
+		} else if err := checkScryptParams(params); err != nil {
+			return nil, err
+		}
+
+		return scrypt.Key(passphrase, params.Salt, params.CostParameter, params.BlockSize, params.ParallelizationParameter, keySize)
+
+	default:
+		return nil, fmt.Errorf("%w: key derivation function %s", ErrUnsupportedKeyEncryption, kdf.Algorithm)
+	}
+}
+
+// checkScryptParams verifies that scrypt parameters are valid and within the limits on memory and CPU
+func checkScryptParams(params scryptParams) error {
+	var (
+		n = params.CostParameter
+		r = params.BlockSize
+		p = params.ParallelizationParameter
+	)
+
+	switch {
+	case n <= 1 || n > maxScryptCost || n&(n-1) != 0:
+		return fmt.Errorf("%w: invalid scrypt cost parameter %d", ErrMalformedEncryptedKeyData, n)
+	case r <= 0 || r > maxScryptMemory/(128*n):
+		return fmt.Errorf("%w: invalid scrypt block size %d", ErrMalformedEncryptedKeyData, r)
+	case p <= 0 || p > maxScryptWork/(n*r):
+		return fmt.Errorf("%w: invalid scrypt parallelization parameter %d", ErrMalformedEncryptedKeyData, p)
+	default:
+		return nil
+	}
+}
+
+// unpad removes and verifies PKCS#7 padding
+func unpad(data []byte, blockSize int) ([]byte, bool) {
+	n := int(data[len(data)-1])
+	if n == 0 || n > blockSize || n > len(data) {
+		return nil, false
+	}
+
+	for _, b := range data[len(data)-n:] {
+		if int(b) != n {
+			return nil, false
+		}
+	}
+
+	return data[:len(data)-n], true
+}
+
+// unmarshalDER parses a complete DER structure, reporting malformed data consistently
+func unmarshalDER(der []byte, v any) error {
+	rest, err := asn1.Unmarshal(der, v)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrMalformedEncryptedKeyData, err)
+	} else if len(rest) > 0 {
+		return fmt.Errorf("%w: trailing data", ErrMalformedEncryptedKeyData)
+	}
+
+	return nil
+}
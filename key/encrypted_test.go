@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPassphrase = "themis"
+
+func TestReadPairWithPassphrase(t *testing.T) {
+	expected, err := ReadPair("test", "test.pkcs8.pem")
+	require.NoError(t, err)
+
+	for _, file := range []string{"test.encrypted.pem", "test.encrypted.des3.pem", "test.encrypted.scrypt.pem"} {
+		t.Run(file, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+			)
+
+			p, err := ReadPairWithPassphrase("test", file, []byte(testPassphrase))
+			require.NoError(err)
+			require.NotNil(p)
+			assert.Equal(expected.Sign(), p.Sign())
+
+			p, err = ReadPairWithPassphrase("test", file, []byte("wrong"))
+			assert.Nil(p)
+			assert.ErrorIs(err, ErrIncorrectPassphrase)
+			assert.NotErrorIs(err, ErrUnsupportedKeyEncryption)
+
+			p, err = ReadPair("test", file)
+			assert.Nil(p)
+			assert.ErrorIs(err, ErrPassphraseRequired)
+		})
+	}
+
+	t.Run("PBES1", func(t *testing.T) {
+		p, err := ReadPairWithPassphrase("test", "test.encrypted.pbes1.pem", []byte(testPassphrase))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrUnsupportedKeyEncryption)
+		assert.NotErrorIs(t, err, ErrIncorrectPassphrase)
+	})
+
+	t.Run("LegacyPEM", func(t *testing.T) {
+		data := pem.EncodeToMemory(&pem.Block{
+			Type: "RSA PRIVATE KEY",
+			Headers: map[string]string{
+				"Proc-Type": "4,ENCRYPTED",
+				"DEK-Info":  "AES-256-CBC,00000000000000000000000000000000",
+			},
+			Bytes: []byte("not a real key"),
+		})
+
+		p, err := ReadPairBytesWithPassphrase("test", data, []byte(testPassphrase))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrUnsupportedKeyEncryption)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		data := pem.EncodeToMemory(&pem.Block{
+			Type:  PEMTypeEncryptedPrivateKey,
+			Bytes: []byte("garbage"),
+		})
+
+		p, err := ReadPairBytesWithPassphrase("test", data, []byte(testPassphrase))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrMalformedEncryptedKeyData)
+	})
+}
+
+// testEncryptedKeyDER produces an AES-256-CBC encrypted key, with garbage contents, whose key
+// is derived with the given key derivation function and parameters
+func testEncryptedKeyDER(t *testing.T, kdf asn1.ObjectIdentifier, kdfParams any) []byte {
+	marshal := func(v any) asn1.RawValue {
+		data, err := asn1.Marshal(v)
+		require.NoError(t, err)
+		return asn1.RawValue{FullBytes: data}
+	}
+
+	der, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		EncryptionAlgorithm: pkix.AlgorithmIdentifier{
+			Algorithm: oidPBES2,
+			Parameters: marshal(pbes2Params{
+				KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: kdf, Parameters: marshal(kdfParams)},
+				EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: marshal(make([]byte, 16))},
+			}),
+		},
+		EncryptedData: make([]byte, 32),
+	})
+
+	require.NoError(t, err)
+	return der
+}
+
+func TestDeriveKeyLimits(t *testing.T) {
+	var (
+		salt = []byte("saltsalt")
+
+		pbkdf2 = func(iterations int) []byte {
+			return testEncryptedKeyDER(t, oidPBKDF2, pbkdf2Params{Salt: salt, IterationCount: iterations})
+		}
+
+		scrypt = func(n, r, p int) []byte {
+			return testEncryptedKeyDER(t, oidScrypt, scryptParams{Salt: salt, CostParameter: n, BlockSize: r, ParallelizationParameter: p})
+		}
+	)
+
+	testData := []struct {
+		name      string
+		der       []byte
+		malformed bool
+	}{
+		{"PBKDF2", pbkdf2(1000), false},
+		{"PBKDF2NoIterations", pbkdf2(0), true},
+		{"PBKDF2TooManyIterations", pbkdf2(maxPBKDF2Iterations + 1), true},
+		{"Scrypt", scrypt(1024, 8, 1), false},
+		{"ScryptCostTooLarge", scrypt(maxScryptCost*2, 1, 1), true},
+		{"ScryptCostNotAPowerOfTwo", scrypt(1000, 8, 1), true},
+		{"ScryptTooMuchMemory", scrypt(maxScryptCost, 16, 1), true},
+		{"ScryptTooMuchWork", scrypt(1024, 8, maxScryptWork/(1024*8)+1), true},
+		{"ScryptNoBlockSize", scrypt(1024, 0, 1), true},
+		{"ScryptNoParallelization", scrypt(1024, 8, 0), true},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			decrypted, err := DecryptPKCS8PrivateKey(record.der, []byte(testPassphrase))
+			assert.Nil(t, decrypted)
+			if record.malformed {
+				assert.ErrorIs(t, err, ErrMalformedEncryptedKeyData)
+			} else {
+				// the key is derived, but the garbage contents cannot be decrypted
+				assert.ErrorIs(t, err, ErrIncorrectPassphrase)
+			}
+		})
+	}
+}
+
+func TestRegistryPassphrase(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		registry = NewRegistry(nil)
+	)
+
+	t.Setenv("THEMIS_TEST_PASSPHRASE", testPassphrase)
+	p, err := registry.Register(Descriptor{
+		Kid:        "test",
+		File:       "test.encrypted.pem",
+		Passphrase: &PassphraseDescriptor{Env: "THEMIS_TEST_PASSPHRASE"},
+	})
+
+	require.NoError(err)
+	assert.NotNil(p)
+
+	p, err = registry.Register(Descriptor{
+		Kid:        "wrong",
+		File:       "test.encrypted.pem",
+		Passphrase: &PassphraseDescriptor{Env: "THEMIS_TEST_NOSUCH"},
+	})
+
+	assert.Nil(p)
+	assert.ErrorIs(err, ErrPassphraseEnvironmentVar)
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/go-kit/kit/endpoint"
+	"github.com/lestrrat-go/jwx/jwk"
 )
 
 type KeyNotFoundError struct {
@@ -22,14 +23,47 @@ func (knfe KeyNotFoundError) StatusCode() int {
 	return http.StatusNotFound
 }
 
+// NewEndpoint returns a go-kit endpoint that looks up a Pair by kid.  Symmetric Pairs are
+// reported as not found, since their verify key is the signing secret.
 func NewEndpoint(r Registry) endpoint.Endpoint {
 	return func(ctx context.Context, request any) (any, error) {
 		kid := request.(string)
 		pair, ok := r.Get(request.(string))
-		if !ok {
+		if !ok || pair.Symmetric() {
 			return nil, KeyNotFoundError{Kid: kid}
 		}
 
 		return pair, nil
 	}
 }
+
+// NewSecretEndpoint returns a go-kit endpoint that looks up a symmetric Pair by kid.  Asymmetric
+// Pairs are reported as not found, as those are published by NewEndpoint.  This endpoint must only
+// be exposed to trusted, authenticated verifiers.
+func NewSecretEndpoint(r Registry) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		kid := request.(string)
+		pair, ok := r.Get(kid)
+		if !ok || !pair.Symmetric() {
+			return nil, KeyNotFoundError{Kid: kid}
+		}
+
+		return pair, nil
+	}
+}
+
+// NewKeySetEndpoint returns a go-kit endpoint that produces a jwk.Set containing the
+// verify keys of every asymmetric Pair in the given Registry.  Symmetric keys have no public
+// component, so they are never included in the set.
+func NewKeySetEndpoint(r Registry) endpoint.Endpoint {
+	return func(ctx context.Context, _ any) (any, error) {
+		set := jwk.NewSet()
+		for _, p := range r.Pairs() {
+			if !p.Symmetric() {
+				set.Add(p.JWK())
+			}
+		}
+
+		return set, nil
+	}
+}
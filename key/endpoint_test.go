@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/lestrrat-go/jwx/jwk"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -55,4 +56,76 @@ func TestNewEndpoint(t *testing.T) {
 		assert.ErrorAs(err, &keyNotFoundError)
 		assert.Equal(http.StatusNotFound, keyNotFoundError.StatusCode())
 	})
+
+	t.Run("Symmetric", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+
+			registry = NewRegistry(nil)
+			endpoint = NewEndpoint(registry)
+		)
+
+		_, err := registry.Register(Descriptor{Kid: "test", Type: KeyTypeSecret})
+		require.NoError(err)
+
+		result, err := endpoint(context.Background(), "test")
+		assert.Nil(result)
+		assert.ErrorAs(err, new(KeyNotFoundError))
+	})
+}
+
+func TestNewSecretEndpoint(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		registry = NewRegistry(nil)
+		endpoint = NewSecretEndpoint(registry)
+	)
+
+	_, err := registry.Register(Descriptor{Kid: "secret", Type: KeyTypeSecret})
+	require.NoError(err)
+	_, err = registry.Register(Descriptor{Kid: "ecdsa", Type: KeyTypeECDSA})
+	require.NoError(err)
+
+	result, err := endpoint(context.Background(), "secret")
+	require.NoError(err)
+	assert.Equal("secret", result.(Pair).KID())
+	assert.True(result.(Pair).Symmetric())
+
+	for _, kid := range []string{"ecdsa", "nosuch"} {
+		result, err = endpoint(context.Background(), kid)
+		assert.Nil(result)
+		assert.ErrorAs(err, new(KeyNotFoundError))
+	}
+}
+
+func TestNewKeySetEndpoint(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		registry = NewRegistry(nil)
+		endpoint = NewKeySetEndpoint(registry)
+	)
+
+	require.NotNil(endpoint)
+	result, err := endpoint(context.Background(), nil)
+	require.NoError(err)
+	assert.Equal(0, result.(jwk.Set).Len())
+
+	_, err = registry.Register(Descriptor{Kid: "test"})
+	require.NoError(err)
+	_, err = registry.Register(Descriptor{Kid: "secret", Type: KeyTypeSecret})
+	require.NoError(err)
+
+	result, err = endpoint(context.Background(), nil)
+	require.NoError(err)
+
+	set := result.(jwk.Set)
+	require.Equal(1, set.Len())
+	k, ok := set.Get(0)
+	require.True(ok)
+	assert.Equal("test", k.KeyID())
 }
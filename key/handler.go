@@ -4,10 +4,18 @@ package key
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/xmidt-org/sallust"
+	"github.com/xmidt-org/themis/v2/cose"
+	"github.com/xmidt-org/themis/v2/paseto"
+	"github.com/xmidt-org/themis/v2/xhttp"
 	"go.uber.org/zap"
 
 	"github.com/go-kit/kit/endpoint"
@@ -16,8 +24,18 @@ import (
 )
 
 const (
-	ContentTypePEM = "application/x-pem-file"
-	ContentTypeJWK = "application/json"
+	ContentTypePEM  = "application/x-pem-file"
+	ContentTypeJWK  = "application/json"
+	ContentTypeJWKS = "application/jwk-set+json"
+
+	// ContentTypeCOSEKey is the content type of a public key encoded as a COSE_Key, as described in RFC 9052
+	ContentTypeCOSEKey = "application/cose-key"
+
+	// ContentTypePASERK is the content type of a public key serialized as a PASERK, which is plain text
+	ContentTypePASERK = "text/plain; charset=utf-8"
+
+	// DefaultKeySetMaxAge is the default Cache-Control max-age for key set responses
+	DefaultKeySetMaxAge = 5 * time.Minute
 )
 
 var (
@@ -72,3 +90,95 @@ func NewHandlerJWK(e endpoint.Endpoint) Handler {
 		},
 	)
 }
+
+type HandlerCOSE http.Handler
+
+// NewHandlerCOSE creates a handler that serves the public key of a Pair as a COSE_Key, so that
+// verifiers of CWTs need not parse JSON.
+func NewHandlerCOSE(e endpoint.Endpoint) HandlerCOSE {
+	return kithttp.NewServer(
+		e,
+		func(ctx context.Context, request *http.Request) (any, error) {
+			kid, ok := mux.Vars(request)["kid"]
+			if !ok {
+				return nil, ErrNoKidVariable
+			}
+
+			sallust.Get(ctx).Info("key request cose",
+				zap.String("kid", kid),
+			)
+
+			return kid, nil
+		},
+		func(_ context.Context, response http.ResponseWriter, value any) error {
+			pair := value.(Pair)
+			data, err := cose.Key(pair.Signer().Public(), pair.KID(), pair.Alg())
+			if err != nil {
+				return err
+			}
+
+			response.Header().Set("Content-Type", ContentTypeCOSEKey)
+			_, err = response.Write(data)
+			return err
+		},
+	)
+}
+
+type HandlerPASERK http.Handler
+
+// NewHandlerPASERK creates a handler that serves the public key of a Pair as a k4.public PASERK, so
+// that verifiers of PASETO tokens can use it.  PASETO v4 keys are always Ed25519, so other Pairs
+// are reported as not found.
+func NewHandlerPASERK(e endpoint.Endpoint) HandlerPASERK {
+	return kithttp.NewServer(
+		e,
+		func(ctx context.Context, request *http.Request) (any, error) {
+			kid, ok := mux.Vars(request)["kid"]
+			if !ok {
+				return nil, ErrNoKidVariable
+			}
+
+			sallust.Get(ctx).Info("key request paserk",
+				zap.String("kid", kid),
+			)
+
+			return kid, nil
+		},
+		func(_ context.Context, response http.ResponseWriter, value any) error {
+			pair := value.(Pair)
+			public, ok := pair.Signer().Public().(ed25519.PublicKey)
+			if !ok {
+				return KeyNotFoundError{Kid: pair.KID()}
+			}
+
+			response.Header().Set("Content-Type", ContentTypePASERK)
+			_, err := io.WriteString(response, paseto.PublicKey(public))
+			return err
+		},
+	)
+}
+
+type HandlerJWKS http.Handler
+
+// NewHandlerJWKS creates a handler that serves the jwk.Set produced by an endpoint, typically
+// one created with NewKeySetEndpoint.  Responses carry an ETag computed from the set's contents
+// along with a Cache-Control header using maxAge, so that verifiers can cache the set.
+func NewHandlerJWKS(e endpoint.Endpoint, maxAge time.Duration) HandlerJWKS {
+	cacheControl := fmt.Sprintf("public, max-age=%d", int64(maxAge/time.Second))
+	return kithttp.NewServer(
+		e,
+		func(ctx context.Context, _ *http.Request) (any, error) {
+			sallust.Get(ctx).Info("key set request")
+			return nil, nil
+		},
+		func(ctx context.Context, response http.ResponseWriter, value any) error {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+
+			return xhttp.WriteCacheable(ctx, response, ContentTypeJWKS, cacheControl, data)
+		},
+		kithttp.ServerBefore(xhttp.WithIfNoneMatch),
+	)
+}
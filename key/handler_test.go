@@ -4,6 +4,7 @@ package key
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"encoding/pem"
 	"io"
 	"net/http"
@@ -11,6 +12,8 @@ import (
 	"testing"
 
 	"github.com/xmidt-org/sallust"
+	"github.com/xmidt-org/themis/v2/cose"
+	"github.com/xmidt-org/themis/v2/paseto"
 
 	"github.com/gorilla/mux"
 	"github.com/lestrrat-go/jwx/jwk"
@@ -53,6 +56,29 @@ func TestNewHandler(t *testing.T) {
 		assert.NotNil(block)
 	})
 
+	t.Run("ThumbprintKid", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+
+			registry = NewRegistry(nil)
+			handler  = NewHandler(NewEndpoint(registry))
+		)
+
+		pair, err := registry.Register(Descriptor{Kid: "test", Type: KeyTypeEd25519, ThumbprintKid: true})
+		require.NoError(err)
+
+		ctx := sallust.With(context.Background(), sallust.Default())
+		request := mux.SetURLVars(
+			httptest.NewRequest("GET", "/", nil).WithContext(ctx),
+			map[string]string{"kid": pair.KID()},
+		)
+
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, request)
+		assert.Equal(http.StatusOK, response.Code)
+	})
+
 	t.Run("NotFound", func(t *testing.T) {
 		var (
 			assert = assert.New(t)
@@ -167,3 +193,198 @@ func TestNewHandlerJWK(t *testing.T) {
 		assert.Equal(http.StatusInternalServerError, response.Code)
 	})
 }
+
+func TestNewHandlerCOSE(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+
+			registry = NewRegistry(nil)
+			endpoint = NewEndpoint(registry)
+			handler  = NewHandlerCOSE(endpoint)
+
+			ctx     = sallust.With(context.Background(), sallust.Default())
+			request = mux.SetURLVars(
+				httptest.NewRequest("GET", "/", nil).WithContext(ctx),
+				map[string]string{"kid": "test"},
+			)
+
+			response = httptest.NewRecorder()
+		)
+
+		pair, err := registry.Register(Descriptor{Kid: "test", Type: KeyTypeECDSA, Bits: 256, Alg: "ES256"})
+		require.NoError(err)
+
+		handler.ServeHTTP(response, request)
+		assert.Equal(http.StatusOK, response.Code)
+		assert.Equal(ContentTypeCOSEKey, response.Header().Get("Content-Type"))
+
+		v, err := cose.Unmarshal(response.Body.Bytes())
+		require.NoError(err)
+		k, ok := v.(map[any]any)
+		require.True(ok)
+		assert.Equal(int64(cose.KeyTypeEC2), k[int64(cose.KeyLabelKty)])
+		assert.Equal([]byte("test"), k[int64(cose.KeyLabelKID)])
+		assert.Equal(int64(-7), k[int64(cose.KeyLabelAlg)])
+
+		point, err := pair.Signer().Public().(*ecdsa.PublicKey).Bytes()
+		require.NoError(err)
+		assert.Equal(point[1:33], k[int64(cose.KeyLabelX)])
+		assert.Equal(point[33:], k[int64(cose.KeyLabelY)])
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		var (
+			assert = assert.New(t)
+
+			registry = NewRegistry(nil)
+			endpoint = NewEndpoint(registry)
+			handler  = NewHandlerCOSE(endpoint)
+
+			ctx     = sallust.With(context.Background(), sallust.Default())
+			request = mux.SetURLVars(
+				httptest.NewRequest("GET", "/", nil).WithContext(ctx),
+				map[string]string{"kid": "test"},
+			)
+
+			response = httptest.NewRecorder()
+		)
+
+		handler.ServeHTTP(response, request)
+		assert.Equal(http.StatusNotFound, response.Code)
+	})
+}
+
+func TestNewHandlerPASERK(t *testing.T) {
+	testData := []struct {
+		name       string
+		descriptor Descriptor
+		expected   int
+	}{
+		{"Ed25519", Descriptor{Kid: "test", Type: KeyTypeEd25519}, http.StatusOK},
+		{"ECDSA", Descriptor{Kid: "test", Type: KeyTypeECDSA, Bits: 256}, http.StatusNotFound},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+
+				registry = NewRegistry(nil)
+				endpoint = NewEndpoint(registry)
+				handler  = NewHandlerPASERK(endpoint)
+
+				ctx     = sallust.With(context.Background(), sallust.Default())
+				request = mux.SetURLVars(
+					httptest.NewRequest("GET", "/", nil).WithContext(ctx),
+					map[string]string{"kid": "test"},
+				)
+
+				response = httptest.NewRecorder()
+			)
+
+			pair, err := registry.Register(record.descriptor)
+			require.NoError(err)
+
+			handler.ServeHTTP(response, request)
+			assert.Equal(record.expected, response.Code)
+			if record.expected == http.StatusOK {
+				assert.Equal(ContentTypePASERK, response.Header().Get("Content-Type"))
+				public, err := paseto.ParsePublicKey(response.Body.String())
+				require.NoError(err)
+				assert.Equal(pair.Signer().Public(), public)
+			}
+		})
+	}
+}
+
+func testNewHandlerJWKSSetup(t *testing.T) (Registry, HandlerJWKS) {
+	var (
+		require = require.New(t)
+
+		registry = NewRegistry(nil)
+		handler  = NewHandlerJWKS(NewKeySetEndpoint(registry), DefaultKeySetMaxAge)
+	)
+
+	_, err := registry.Register(Descriptor{Kid: "rsa", Alg: "RS256"})
+	require.NoError(err)
+	_, err = registry.Register(Descriptor{Kid: "ecdsa", Type: KeyTypeECDSA, Alg: "ES384"})
+	require.NoError(err)
+	_, err = registry.Register(Descriptor{Kid: "secret", Type: KeyTypeSecret, Alg: "HS256"})
+	require.NoError(err)
+
+	return registry, handler
+}
+
+func testNewHandlerJWKSSuccess(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		_, handler = testNewHandlerJWKSSetup(t)
+		ctx        = sallust.With(context.Background(), sallust.Default())
+		response   = httptest.NewRecorder()
+	)
+
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil).WithContext(ctx))
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal(ContentTypeJWKS, response.Header().Get("Content-Type"))
+	assert.Equal("public, max-age=300", response.Header().Get("Cache-Control"))
+	assert.NotEmpty(response.Header().Get("ETag"))
+
+	set, err := jwk.Parse(response.Body.Bytes())
+	require.NoError(err)
+	require.Equal(2, set.Len())
+
+	k, ok := set.LookupKeyID("rsa")
+	require.True(ok)
+	assert.Equal("RS256", k.Algorithm())
+	assert.Equal(UseSignature, k.KeyUsage())
+
+	k, ok = set.LookupKeyID("ecdsa")
+	require.True(ok)
+	assert.Equal("ES384", k.Algorithm())
+
+	_, ok = set.LookupKeyID("secret")
+	assert.False(ok)
+}
+
+func testNewHandlerJWKSNotModified(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		registry, handler = testNewHandlerJWKSSetup(t)
+		ctx               = sallust.With(context.Background(), sallust.Default())
+		response          = httptest.NewRecorder()
+	)
+
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil).WithContext(ctx))
+	require.Equal(http.StatusOK, response.Code)
+	etag := response.Header().Get("ETag")
+	require.NotEmpty(etag)
+
+	request := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	request.Header.Set("If-None-Match", `"other", W/`+etag)
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	assert.Equal(http.StatusNotModified, response.Code)
+	assert.Equal(etag, response.Header().Get("ETag"))
+	assert.Empty(response.Body.Bytes())
+
+	// a change to the registry changes the entity tag
+	_, err := registry.Register(Descriptor{Kid: "another"})
+	require.NoError(err)
+
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	assert.Equal(http.StatusOK, response.Code)
+	assert.NotEqual(etag, response.Header().Get("ETag"))
+}
+
+func TestNewHandlerJWKS(t *testing.T) {
+	t.Run("Success", testNewHandlerJWKSSuccess)
+	t.Run("NotModified", testNewHandlerJWKSNotModified)
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xmidt-org/themis/v2/xmetrics"
+	"go.uber.org/fx"
+)
+
+// Metric names.
+const (
+	RotationCounter = "key_rotation_total"
+	ReloadCounter   = "key_reload_total"
+)
+
+// Metric label keys.
+const (
+	KidLabelKey     = "kid"
+	OutcomeLabelKey = "outcome"
+)
+
+// Metric label values for outcomes.
+const (
+	FailOutcome    = "fail"
+	SuccessOutcome = "success"
+)
+
+// ProvideMetrics returns the key Metrics for the App.
+func ProvideMetrics() fx.Option {
+	return fx.Provide(
+		xmetrics.ProvideCounterVec(
+			prometheus.CounterOpts{
+				Name: RotationCounter,
+				Help: "The total number of signing key rotations.",
+			},
+			KidLabelKey,
+			OutcomeLabelKey,
+		),
+		xmetrics.ProvideCounterVec(
+			prometheus.CounterOpts{
+				Name: ReloadCounter,
+				Help: "The total number of attempts to reload changed signing key files.",
+			},
+			KidLabelKey,
+			OutcomeLabelKey,
+		),
+	)
+}
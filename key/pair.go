@@ -3,10 +3,13 @@
 package key
 
 import (
+	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"errors"
@@ -21,6 +24,10 @@ import (
 const (
 	DefaultRSABits    = 1024
 	DefaultSecretBits = 512
+
+	// UseSignature is the JWK "use" value for keys that verify signatures.  This is
+	// the default use for all Pairs.
+	UseSignature = "sig"
 )
 
 var (
@@ -36,27 +43,180 @@ type Pair interface {
 	// Sign returns the signing key for generating signed JWT tokens.
 	Sign() any
 
+	// Signer returns the crypto.Signer that produces signatures for this Pair.  For Pairs created
+	// from asymmetric keys, this is the private key itself.  For Pairs backed by an external signer,
+	// this is that signer.  Symmetric Pairs cannot be used as a crypto.Signer, and this method returns nil.
+	Signer() crypto.Signer
+
+	// Symmetric tests if this Pair is a shared secret, e.g. an HMAC key.  The verify key of a symmetric
+	// Pair is the signing key itself, so it must never be published to untrusted clients.
+	Symmetric() bool
+
+	// Alg is the JWA algorithm this Pair is used with, e.g. RS256.  This value may be empty
+	// if the Pair was not created with an algorithm.
+	Alg() string
+
+	// Use is the intended use of this Pair's verify key.  This is typically UseSignature.
+	Use() string
+
 	// WriteVerifyPEMto writes the PEM-encoded verify key to an arbitrary output sink.
 	WriteVerifyPEMTo(io.Writer) (int64, error)
 
 	WriteJWK(io.Writer) (int64, error)
+
+	// JWK returns the JSON Web Key for the verify key, with the kid, alg, and use
+	// parameters populated.  Callers must not modify the returned key.
+	JWK() jwk.Key
+
+	// Certificates returns the X.509 certificate chain bound to this Pair, leaf first.  If
+	// the Pair has no certificate, this method returns nil.
+	Certificates() []*x509.Certificate
 }
 
 type pair struct {
 	kid        string
+	alg        string
+	use        string
 	sign       any
 	verifyPEM  []byte
+	verifyJWK  jwk.Key
 	jsonWebKey []byte
+
+	certificates []*x509.Certificate
+}
+
+// newPair assembles a pair, stamping the key metadata and any certificate chain
+// onto the JWK and precomputing the JWK's JSON representation.
+func newPair(kid, alg, use string, sign any, verifyPEM []byte, verifyJWK jwk.Key, certificates []*x509.Certificate) (Pair, error) {
+	if len(use) == 0 {
+		use = UseSignature
+	}
+
+	if err := verifyJWK.Set(jwk.KeyIDKey, kid); err != nil {
+		return nil, err
+	}
+
+	if err := verifyJWK.Set(jwk.KeyUsageKey, use); err != nil {
+		return nil, err
+	}
+
+	if len(alg) > 0 {
+		if err := verifyJWK.Set(jwk.AlgorithmKey, alg); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(certificates) > 0 {
+		x5c := make([]string, len(certificates))
+		for i, cert := range certificates {
+			x5c[i] = base64.StdEncoding.EncodeToString(cert.Raw)
+		}
+
+		if err := verifyJWK.Set(jwk.X509CertChainKey, x5c); err != nil {
+			return nil, err
+		}
+
+		if err := verifyJWK.Set(jwk.X509CertThumbprintKey, CertificateThumbprint(certificates[0])); err != nil {
+			return nil, err
+		}
+
+		if err := verifyJWK.Set(jwk.X509CertThumbprintS256Key, CertificateThumbprintS256(certificates[0])); err != nil {
+			return nil, err
+		}
+	}
+
+	jsonWebKey, err := json.MarshalIndent(verifyJWK, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return pair{
+		kid:          kid,
+		alg:          alg,
+		use:          use,
+		sign:         sign,
+		verifyPEM:    verifyPEM,
+		verifyJWK:    verifyJWK,
+		jsonWebKey:   jsonWebKey,
+		certificates: certificates,
+	}, nil
+}
+
+// withMetadata produces a copy of this pair with a different algorithm and use.
+func (p pair) withMetadata(alg, use string) (Pair, error) {
+	// parsing the precomputed JSON gives us a copy of the JWK to modify
+	verifyJWK, err := jwk.ParseKey(p.jsonWebKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPair(p.kid, alg, use, p.sign, p.verifyPEM, verifyJWK, p.certificates)
+}
+
+// withKID produces a copy of this pair with a different key identifier
+func (p pair) withKID(kid string) (Pair, error) {
+	verifyJWK, err := jwk.ParseKey(p.jsonWebKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPair(kid, p.alg, p.use, p.sign, p.verifyPEM, verifyJWK, p.certificates)
+}
+
+// withCertificates produces a copy of this pair bound to a certificate chain.  The chain's
+// leaf certificate must be for this pair's key.
+func (p pair) withCertificates(chain []*x509.Certificate) (Pair, error) {
+	signer := p.Signer()
+	if signer == nil {
+		return nil, fmt.Errorf("%w: symmetric keys cannot have certificates", ErrCertificateMismatch)
+	}
+
+	if err := CheckCertificateChain(signer.Public(), chain); err != nil {
+		return nil, err
+	}
+
+	verifyJWK, err := jwk.ParseKey(p.jsonWebKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPair(p.kid, p.alg, p.use, p.sign, p.verifyPEM, verifyJWK, chain)
 }
 
 func (p pair) KID() string {
 	return p.kid
 }
 
+func (p pair) Alg() string {
+	return p.alg
+}
+
+func (p pair) Use() string {
+	return p.use
+}
+
+func (p pair) JWK() jwk.Key {
+	return p.verifyJWK
+}
+
+func (p pair) Certificates() []*x509.Certificate {
+	return p.certificates
+}
+
 func (p pair) Sign() any {
 	return p.sign
 }
 
+func (p pair) Signer() crypto.Signer {
+	s, _ := p.sign.(crypto.Signer)
+	return s
+}
+
+func (p pair) Symmetric() bool {
+	_, ok := p.sign.([]byte)
+	return ok
+}
+
 func (p pair) WriteVerifyPEMTo(w io.Writer) (int64, error) {
 	c, err := w.Write(p.verifyPEM)
 	return int64(c), err
@@ -79,17 +239,8 @@ func NewPair(kid string, key any) (Pair, error) {
 		if err != nil {
 			return nil, err
 		}
-		jsonWebKey, err := json.MarshalIndent(jwkKey, "", "  ")
-		if err != nil {
-			return nil, err
-		}
 
-		return pair{
-			kid:        kid,
-			sign:       key,
-			verifyPEM:  verifyPEM,
-			jsonWebKey: jsonWebKey,
-		}, nil
+		return newPair(kid, "", "", key, verifyPEM, jwkKey, nil)
 
 	case *ecdsa.PrivateKey:
 		verifyPEM, err := MarshalPKIXPublicKeyToPEM(&k.PublicKey)
@@ -101,89 +252,124 @@ func NewPair(kid string, key any) (Pair, error) {
 		if err != nil {
 			return nil, err
 		}
-		jsonWebKey, err := json.MarshalIndent(jwkKey, "", "  ")
+
+		return newPair(kid, "", "", key, verifyPEM, jwkKey, nil)
+
+	case ed25519.PrivateKey:
+		public := k.Public()
+		verifyPEM, err := MarshalPKIXPublicKeyToPEM(public)
 		if err != nil {
 			return nil, err
 		}
 
-		return pair{
-			kid:        kid,
-			sign:       key,
-			verifyPEM:  verifyPEM,
-			jsonWebKey: jsonWebKey,
-		}, nil
-
-	case []byte:
-		jwkKey, err := jwk.New(k)
+		jwkKey, err := jwk.New(public)
 		if err != nil {
 			return nil, err
 		}
-		jsonWebKey, err := json.MarshalIndent(jwkKey, "", "  ")
+
+		return newPair(kid, "", "", key, verifyPEM, jwkKey, nil)
+
+	case []byte:
+		jwkKey, err := jwk.New(k)
 		if err != nil {
 			return nil, err
 		}
 
-		return pair{
-			kid:  kid,
-			sign: key,
-			verifyPEM: pem.EncodeToMemory(
-				&pem.Block{
-					// nolint:goconst
-					Type:  "PUBLIC KEY",
-					Bytes: k,
-				},
-			),
-			jsonWebKey: jsonWebKey,
-		}, nil
+		verifyPEM := pem.EncodeToMemory(
+			&pem.Block{
+				// nolint:goconst
+				Type:  "PUBLIC KEY",
+				Bytes: k,
+			},
+		)
+
+		return newPair(kid, "", "", key, verifyPEM, jwkKey, nil)
 
 	case string:
 		keyBytes := []byte(k)
-
 		jwkKey, err := jwk.New(keyBytes)
 		if err != nil {
 			return nil, err
 		}
-		jsonWebKey, err := json.MarshalIndent(jwkKey, "", "  ")
-		if err != nil {
-			return nil, err
-		}
 
-		return pair{
-			kid:  kid,
-			sign: keyBytes,
-			verifyPEM: pem.EncodeToMemory(
-				&pem.Block{
-					Type:  "PUBLIC KEY",
-					Bytes: keyBytes,
-				},
-			),
-			jsonWebKey: jsonWebKey,
-		}, nil
+		verifyPEM := pem.EncodeToMemory(
+			&pem.Block{
+				Type:  "PUBLIC KEY",
+				Bytes: keyBytes,
+			},
+		)
+
+		return newPair(kid, "", "", keyBytes, verifyPEM, jwkKey, nil)
 	}
+
 	return nil, fmt.Errorf("unsupported key type: %v", key)
 }
 
 func ReadPair(kid string, file string) (Pair, error) {
+	return ReadPairWithPassphrase(kid, file, nil)
+}
+
+// ReadPairWithPassphrase reads a Pair from a file, which may contain an encrypted PKCS#8 private key.
+// The passphrase is only used for encrypted keys.
+func ReadPairWithPassphrase(kid string, file string, passphrase []byte) (Pair, error) {
 	data, err := os.ReadFile(file)
 	if err != nil {
 		return nil, err
 	}
 
-	return ReadPairBytes(kid, data)
+	return ReadPairBytesWithPassphrase(kid, data, passphrase)
 }
 
 func ReadPairBytes(kid string, data []byte) (Pair, error) {
+	return ReadPairBytesWithPassphrase(kid, data, nil)
+}
+
+// ReadPairBytesWithPassphrase parses a Pair from PEM data, which may contain an encrypted PKCS#8
+// private key.  The passphrase is only used for encrypted keys.  Data that is not PEM-encoded is
+// treated as a secret key.
+func ReadPairBytesWithPassphrase(kid string, data, passphrase []byte) (Pair, error) {
 	block, _ := pem.Decode(data)
 	if block == nil {
 		return NewPair(kid, data)
 	}
 
+	key, err := parsePrivateKey(block, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPair(kid, key)
+}
+
+// parsePrivateKey parses a PEM block holding a PKCS#1 or PKCS#8 private key.  Encrypted PKCS#8 keys
+// are decrypted with the passphrase.  Legacy encrypted PEM blocks are not supported.
+func parsePrivateKey(block *pem.Block, passphrase []byte) (any, error) {
+	if _, ok := block.Headers["DEK-Info"]; ok {
+		return nil, fmt.Errorf("%w: legacy PEM encryption, use encrypted PKCS#8 instead", ErrUnsupportedKeyEncryption)
+	}
+
+	if block.Type == PEMTypeEncryptedPrivateKey {
+		der, err := DecryptPKCS8PrivateKey(block.Bytes, passphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			// decryption produced a well-formed structure that isn't a key, which
+			// is almost always due to a wrong passphrase
+			return nil, fmt.Errorf("%w: %s", ErrIncorrectPassphrase, err)
+		}
+
+		return key, nil
+	}
+
 	if rsaKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
-		return NewPair(kid, rsaKey)
+		return rsaKey, nil
 	}
 
 	if pkcs8, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
-		return NewPair(kid, pkcs8)
+		return pkcs8, nil
 	}
 
 	return nil, ErrUnrecognizedKeyData
@@ -202,25 +388,32 @@ func GenerateRSAPair(kid string, random io.Reader, bits int) (Pair, error) {
 	return NewPair(kid, key)
 }
 
+// curveForBits returns the elliptic curve for a given JWT bit size.  If bits is nonpositive,
+// DefaultCurve is returned.
+func curveForBits(bits int) (elliptic.Curve, error) {
+	if bits <= 0 {
+		return DefaultCurve, nil
+	}
+
+	switch bits {
+	case 224:
+		return elliptic.P224(), nil
+	case 256:
+		return elliptic.P256(), nil
+	case 384:
+		return elliptic.P384(), nil
+	// oddity: the P521() method returns the curve for 512 bit JWT signing
+	case 512:
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve value: %d", bits)
+	}
+}
+
 func GenerateECDSAPair(kid string, random io.Reader, bits int) (Pair, error) {
-	curve := DefaultCurve
-
-	if bits > 0 {
-		switch bits {
-		case 224:
-			curve = elliptic.P224()
-		case 256:
-			curve = elliptic.P256()
-		case 384:
-			curve = elliptic.P384()
-
-		// oddity: the P521() method returns the curve for 512 bit JWT signing
-		case 512:
-			curve = elliptic.P521()
-
-		default:
-			return nil, fmt.Errorf("unsupported curve value: %d", curve)
-		}
+	curve, err := curveForBits(bits)
+	if err != nil {
+		return nil, err
 	}
 
 	key, err := ecdsa.GenerateKey(curve, random)
@@ -231,6 +424,17 @@ func GenerateECDSAPair(kid string, random io.Reader, bits int) (Pair, error) {
 	return NewPair(kid, key)
 }
 
+// GenerateEd25519Pair generates an Ed25519 key pair, used with the EdDSA signing algorithm.
+// Ed25519 keys have a fixed size, so there is no bits parameter.
+func GenerateEd25519Pair(kid string, random io.Reader) (Pair, error) {
+	_, key, err := ed25519.GenerateKey(random)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPair(kid, key)
+}
+
 func GenerateSecretPair(kid string, random io.Reader, bits int) (Pair, error) {
 	if bits <= 0 {
 		bits = DefaultSecretBits
@@ -244,6 +448,18 @@ func GenerateSecretPair(kid string, random io.Reader, bits int) (Pair, error) {
 	return NewPair(kid, secret)
 }
 
+// ThumbprintKID derives a key identifier from the RFC 7638 SHA-256 thumbprint of a Pair's verify key.
+// The result is the prefix followed by the base64url encoded thumbprint.  Since the thumbprint only
+// depends on the key material, the same key always has the same identifier.
+func ThumbprintKID(p Pair, prefix string) (string, error) {
+	thumbprint, err := p.JWK().Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + base64.RawURLEncoding.EncodeToString(thumbprint), nil
+}
+
 // MarshalPKIXPublicKeyToPEM handles marshaling a public key in PKIX format which is
 // then encoded as a PEM block
 func MarshalPKIXPublicKeyToPEM(key any) ([]byte, error) {
@@ -5,12 +5,16 @@ package key
 import (
 	"bytes"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"testing"
 
+	"github.com/lestrrat-go/jwx/jwa"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,6 +37,10 @@ func TestNewPair(t *testing.T) {
 
 		assert.Equal("test", p.KID())
 		assert.Equal(key, p.Sign())
+		assert.Empty(p.Alg())
+		assert.Equal(UseSignature, p.Use())
+		require.NotNil(p.JWK())
+		assert.Equal("test", p.JWK().KeyID())
 	})
 
 	t.Run("ecdsa", func(t *testing.T) {
@@ -54,6 +62,23 @@ func TestNewPair(t *testing.T) {
 		assert.Equal(key, p.Sign())
 	})
 
+	t.Run("ed25519", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+
+			_, key, err = ed25519.GenerateKey(rand.Reader)
+		)
+
+		require.NoError(err)
+		p, err := NewPair("test", key)
+		require.NoError(err)
+		require.NotNil(p)
+		assert.Equal("test", p.KID())
+		assert.Equal(key, p.Sign())
+		assert.Equal(jwa.OKP, p.JWK().KeyType())
+	})
+
 	t.Run("bytes", func(t *testing.T) {
 		var (
 			assert  = assert.New(t)
@@ -199,6 +224,40 @@ func TestGenerateECDSAPair(t *testing.T) {
 	})
 }
 
+func TestGenerateEd25519Pair(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		pair, err = GenerateEd25519Pair("test", rand.Reader)
+	)
+
+	require.NoError(err)
+	require.NotNil(pair)
+	assert.Equal("test", pair.KID())
+	key, ok := pair.Sign().(ed25519.PrivateKey)
+	require.True(ok)
+	require.Len(key, ed25519.PrivateKeySize)
+
+	var output bytes.Buffer
+	c, err := pair.WriteVerifyPEMTo(&output)
+	require.NoError(err)
+	assert.True(c > 0)
+	block, _ := pem.Decode(output.Bytes())
+	require.NotNil(block)
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	require.NoError(err)
+	assert.Equal(key.Public(), public)
+
+	output.Reset()
+	c, err = pair.WriteJWK(&output)
+	require.NoError(err)
+	assert.True(c > 0)
+	assert.Contains(output.String(), `"kty": "OKP"`)
+	assert.Contains(output.String(), `"crv": "Ed25519"`)
+	assert.NotContains(output.String(), `"d":`)
+}
+
 func TestGenerateSecretPair(t *testing.T) {
 	testBits := []int{
 		0,
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var (
+	ErrNoPassphraseSource       = errors.New("exactly one passphrase source must be configured")
+	ErrEmptyPassphrase          = errors.New("the passphrase source supplied an empty passphrase")
+	ErrPassphraseEnvironmentVar = errors.New("the passphrase environment variable is not set")
+)
+
+// PassphraseDescriptor configures where the passphrase for an encrypted key File comes from.
+// Exactly one source must be set.
+type PassphraseDescriptor struct {
+	// Env is the name of an environment variable that holds the passphrase
+	Env string
+
+	// File is the system path to a file holding the passphrase, such as a mounted Kubernetes secret.
+	// Any trailing newline is ignored.
+	File string
+
+	// Stdin indicates that the passphrase is the first line read from standard input.  Standard
+	// input is only read once, at startup, and that passphrase is shared by all keys that use it.
+	Stdin bool
+}
+
+// stdinPassphrase reads a single line from a reader at most once
+type stdinPassphrase struct {
+	once       sync.Once
+	reader     io.Reader
+	passphrase []byte
+	err        error
+}
+
+func (sp *stdinPassphrase) read() ([]byte, error) {
+	sp.once.Do(func() {
+		line, err := bufio.NewReader(sp.reader).ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			sp.err = fmt.Errorf("unable to read passphrase from stdin: %w", err)
+			return
+		}
+
+		sp.passphrase = bytes.TrimRight(line, "\r\n")
+	})
+
+	return sp.passphrase, sp.err
+}
+
+var defaultStdinPassphrase = &stdinPassphrase{reader: os.Stdin}
+
+// Read obtains the passphrase from the configured source
+func (pd PassphraseDescriptor) Read() ([]byte, error) {
+	return pd.read(defaultStdinPassphrase)
+}
+
+func (pd PassphraseDescriptor) read(stdin *stdinPassphrase) ([]byte, error) {
+	var (
+		passphrase []byte
+		err        error
+		sources    int
+	)
+
+	if len(pd.Env) > 0 {
+		sources++
+		value, ok := os.LookupEnv(pd.Env)
+		if !ok {
+			err = fmt.Errorf("%w: %s", ErrPassphraseEnvironmentVar, pd.Env)
+		}
+
+		passphrase = []byte(value)
+	}
+
+	if len(pd.File) > 0 {
+		sources++
+		passphrase, err = os.ReadFile(pd.File)
+		passphrase = bytes.TrimRight(passphrase, "\r\n")
+	}
+
+	if pd.Stdin {
+		sources++
+		passphrase, err = stdin.read()
+	}
+
+	switch {
+	case sources != 1:
+		return nil, ErrNoPassphraseSource
+	case err != nil:
+		return nil, err
+	case len(passphrase) == 0:
+		return nil, ErrEmptyPassphrase
+	default:
+		return passphrase, nil
+	}
+}
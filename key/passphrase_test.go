@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassphraseDescriptor(t *testing.T) {
+	t.Run("Env", func(t *testing.T) {
+		t.Setenv("THEMIS_TEST_PASSPHRASE", "env passphrase")
+		passphrase, err := PassphraseDescriptor{Env: "THEMIS_TEST_PASSPHRASE"}.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("env passphrase"), passphrase)
+
+		t.Setenv("THEMIS_TEST_PASSPHRASE", "")
+		passphrase, err = PassphraseDescriptor{Env: "THEMIS_TEST_PASSPHRASE"}.Read()
+		assert.Nil(t, passphrase)
+		assert.ErrorIs(t, err, ErrEmptyPassphrase)
+
+		passphrase, err = PassphraseDescriptor{Env: "THEMIS_TEST_NOSUCH"}.Read()
+		assert.Nil(t, passphrase)
+		assert.ErrorIs(t, err, ErrPassphraseEnvironmentVar)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "passphrase")
+		require.NoError(t, os.WriteFile(file, []byte("file passphrase\n"), 0600))
+
+		passphrase, err := PassphraseDescriptor{File: file}.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("file passphrase"), passphrase)
+
+		passphrase, err = PassphraseDescriptor{File: file + ".nosuch"}.Read()
+		assert.Nil(t, passphrase)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Stdin", func(t *testing.T) {
+		var (
+			assert = assert.New(t)
+			stdin  = &stdinPassphrase{reader: strings.NewReader("stdin passphrase\r\nsecond line\n")}
+		)
+
+		passphrase, err := PassphraseDescriptor{Stdin: true}.read(stdin)
+		assert.NoError(err)
+		assert.Equal([]byte("stdin passphrase"), passphrase)
+
+		// stdin is only ever read once
+		passphrase, err = PassphraseDescriptor{Stdin: true}.read(stdin)
+		assert.NoError(err)
+		assert.Equal([]byte("stdin passphrase"), passphrase)
+
+		passphrase, err = PassphraseDescriptor{Stdin: true}.read(&stdinPassphrase{reader: strings.NewReader("")})
+		assert.Nil(passphrase)
+		assert.Error(err)
+	})
+
+	t.Run("Sources", func(t *testing.T) {
+		for _, pd := range []PassphraseDescriptor{{}, {Env: "A", File: "b"}, {File: "b", Stdin: true}} {
+			passphrase, err := pd.Read()
+			assert.Nil(t, passphrase)
+			assert.ErrorIs(t, err, ErrNoPassphraseSource)
+		}
+	})
+}
@@ -15,6 +15,14 @@ type KeyIn struct {
 	// Random is the optional source of randomness.  If not present in the container,
 	// crypto/rand.Reader is used.
 	Random io.Reader `optional:"true"`
+
+	// Store is the optional Store used to persist generated keys.  If not present in the
+	// container, generated keys are kept only in memory.
+	Store Store `optional:"true"`
+
+	// SecretOptions configures the publication of symmetric keys to trusted verifiers.  If not present
+	// in the container, symmetric keys are never published.
+	SecretOptions SecretOptions `optional:"true"`
 }
 
 // KeyOut is the set of components emitted by this package
@@ -28,11 +36,24 @@ type KeyOut struct {
 	Handler Handler
 
 	HandlerJWK HandlerJWK
+
+	// HandlerCOSE is the http.Handler which serves each public key as a COSE_Key
+	HandlerCOSE HandlerCOSE
+
+	// HandlerPASERK is the http.Handler which serves each Ed25519 public key as a PASERK
+	HandlerPASERK HandlerPASERK
+
+	// HandlerJWKS is the http.Handler which serves every published key in the Registry as a JWK Set
+	HandlerJWKS HandlerJWKS
+
+	// HandlerSecret is the http.Handler which serves symmetric keys to authenticated verifiers.  This
+	// handler is nil unless SecretOptions has credentials.
+	HandlerSecret HandlerSecret
 }
 
 // Provide is an uber/fx style provider for this package's components
 func Provide(in KeyIn) KeyOut {
-	registry := NewRegistry(in.Random)
+	registry := NewRegistryWithStore(in.Random, in.Store)
 	endpoint := NewEndpoint(registry)
 
 	return KeyOut{
@@ -43,5 +64,19 @@ func Provide(in KeyIn) KeyOut {
 		HandlerJWK: NewHandlerJWK(
 			endpoint,
 		),
+		HandlerCOSE: NewHandlerCOSE(
+			endpoint,
+		),
+		HandlerPASERK: NewHandlerPASERK(
+			endpoint,
+		),
+		HandlerJWKS: NewHandlerJWKS(
+			NewKeySetEndpoint(registry),
+			DefaultKeySetMaxAge,
+		),
+		HandlerSecret: NewHandlerSecret(
+			NewSecretEndpoint(registry),
+			in.SecretOptions.Credentials,
+		),
 	}
 }
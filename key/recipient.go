@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	ErrNoRecipientFile = errors.New("a file is required for a recipient key")
+	ErrNoPublicKey     = errors.New("no public key found")
+)
+
+// RecipientDescriptor describes the public key of a party to whom themis encrypts content.
+// Unlike a Descriptor, there is never a private key, so recipient keys are always read from a file.
+type RecipientDescriptor struct {
+	// Kid is the key id of the recipient's key, sent along with encrypted content so that the
+	// recipient can select its decryption key.  If unset, no kid is sent.
+	Kid string
+
+	// File is the system path to a PEM file holding the recipient's public key, either as a
+	// PUBLIC KEY, an RSA PUBLIC KEY, or a CERTIFICATE.  If the file contains several blocks,
+	// the first one holding a public key is used, so a certificate chain must be leaf first.
+	File string
+}
+
+// Recipient is a public key to which content is encrypted
+type Recipient struct {
+	// Kid is the recipient's key id, which may be empty
+	Kid string
+
+	// Key is the recipient's public key
+	Key crypto.PublicKey
+}
+
+// ReadRecipient reads the public key described by a RecipientDescriptor
+func ReadRecipient(d RecipientDescriptor) (Recipient, error) {
+	if len(d.File) == 0 {
+		return Recipient{}, ErrNoRecipientFile
+	}
+
+	public, err := ReadPublicKey(d.File)
+	if err != nil {
+		return Recipient{}, err
+	}
+
+	return Recipient{Kid: d.Kid, Key: public}, nil
+}
+
+// ReadPublicKey reads the first public key from a PEM file.  PKIX public keys, PKCS#1 RSA
+// public keys, and X.509 certificates are supported.
+func ReadPublicKey(file string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		var public crypto.PublicKey
+		switch block.Type {
+		case "PUBLIC KEY":
+			public, err = x509.ParsePKIXPublicKey(block.Bytes)
+
+		case "RSA PUBLIC KEY":
+			public, err = x509.ParsePKCS1PublicKey(block.Bytes)
+
+		case PEMTypeCertificate:
+			var cert *x509.Certificate
+			if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+				public = cert.PublicKey
+			}
+
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s in %s: %w", block.Type, file, err)
+		}
+
+		return public, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoPublicKey, file)
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRecipient(t *testing.T) {
+	var directory = t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pkixFile := filepath.Join(directory, "pkix.pem")
+	pkix, err := MarshalPKIXPublicKeyToPEM(&ecdsaKey.PublicKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(pkixFile, pkix, 0600))
+
+	pkcs1File := filepath.Join(directory, "pkcs1.pem")
+	require.NoError(t, os.WriteFile(
+		pkcs1File,
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)}),
+		0600,
+	))
+
+	certificateFile := filepath.Join(directory, "certificate.pem")
+	writeTestCertificates(t, certificateFile, newTestCertificate(t, "recipient", ecdsaKey.Public(), nil, ecdsaKey))
+
+	testData := []struct {
+		name     string
+		file     string
+		expected any
+	}{
+		{"PKIX", pkixFile, &ecdsaKey.PublicKey},
+		{"PKCS1", pkcs1File, &rsaKey.PublicKey},
+		{"Certificate", certificateFile, &ecdsaKey.PublicKey},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+			)
+
+			recipient, err := ReadRecipient(RecipientDescriptor{Kid: "recipient", File: record.file})
+			require.NoError(err)
+			assert.Equal("recipient", recipient.Kid)
+			assert.Equal(record.expected, recipient.Key)
+		})
+	}
+
+	t.Run("NoFile", func(t *testing.T) {
+		_, err := ReadRecipient(RecipientDescriptor{Kid: "recipient"})
+		assert.ErrorIs(t, err, ErrNoRecipientFile)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		_, err := ReadRecipient(RecipientDescriptor{File: filepath.Join(directory, "missing.pem")})
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("NoPublicKey", func(t *testing.T) {
+		file := filepath.Join(directory, "private.pem")
+		writeTestReloadKey(t, file, Descriptor{Kid: "test", Type: KeyTypeECDSA, Bits: 256})
+		_, err := ReadRecipient(RecipientDescriptor{File: file})
+		assert.ErrorIs(t, err, ErrNoPublicKey)
+	})
+
+	t.Run("InvalidPublicKey", func(t *testing.T) {
+		file := filepath.Join(directory, "invalid.pem")
+		require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("invalid")}), 0600))
+		_, err := ReadRecipient(RecipientDescriptor{File: file})
+		assert.Error(t, err)
+	})
+}
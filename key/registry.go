@@ -4,15 +4,21 @@ package key
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 const (
-	KeyTypeRSA    = "rsa"
-	KeyTypeECDSA  = "ecdsa"
-	KeyTypeSecret = "secret"
+	KeyTypeRSA     = "rsa"
+	KeyTypeECDSA   = "ecdsa"
+	KeyTypeEd25519 = "ed25519"
+	KeyTypeSecret  = "secret"
 )
 
 // Descriptor holds the configurable options for a key Pair
@@ -21,16 +27,57 @@ type Descriptor struct {
 	// change is the key is rotated or updated during application execution.
 	Kid string
 
+	// ThumbprintKid indicates that the key's kid is derived from the RFC 7638 JWK thumbprint of its public
+	// key, so that kids are unique across clusters and stable for keys read from files.  The derived kid is
+	// KidPrefix followed by the base64url encoded SHA-256 thumbprint.  Kid is still used to name the key
+	// in a Store.
+	ThumbprintKid bool
+
+	// KidPrefix is prepended to thumbprint-derived kids.  This field is ignored unless ThumbprintKid is set.
+	KidPrefix string
+
 	// Type indicates the type of key.  This field dictates both how the key File is read or how the key
-	// is generated.  The default is "rsa".
+	// is generated.  Valid types are "rsa", "ecdsa", "ed25519", and "secret".  The default is "rsa".
 	Type string
 
-	// Bits indicates the bit size for a generated key
+	// Bits indicates the bit size for a generated key.  This field is ignored for ed25519 keys,
+	// which have a fixed size.
 	Bits int
 
+	// RotationInterval is how often this key is replaced with a new key.  Each new key is
+	// registered under a kid derived from Kid and the time of rotation.  If unset, the key is
+	// never rotated.
+	RotationInterval time.Duration
+
+	// ReloadInterval is how often File is checked for changes.  When File changes, the new key is
+	// registered under a kid derived from Kid and the time of the reload.  If unset, File is only read
+	// once.  This field cannot be combined with RotationInterval.
+	ReloadInterval time.Duration
+
 	// File is the system path to a file where the key is stored.  If set, this file must exist and contain
 	// either a secret or a PEM-encoded key pair.  If this field is not set, a key is generated.
 	File string
+
+	// CertificateFile is the optional system path to a PEM file holding the X.509 certificate chain for
+	// this key, leaf first.  The leaf certificate must be for this key.  If set, the chain is published
+	// with the key's JWK as the x5c, x5t, and x5t#S256 parameters.
+	CertificateFile string
+
+	// Passphrase is the source of the passphrase for an encrypted PKCS#8 File.  This field is
+	// required if File is encrypted, and ignored otherwise.
+	Passphrase *PassphraseDescriptor
+
+	// Alg is the JWA algorithm the key is used with, e.g. RS256.  This value is published
+	// as the alg parameter of the key's JWK.  If unset, no alg parameter is published.
+	Alg string
+
+	// Use is the intended use of the key, published as the use parameter of the key's JWK.
+	// If unset, UseSignature is used.
+	Use string
+
+	// Signer is the optional external signer that holds the private key.  If set, the private key
+	// is neither read from File nor generated, and Store is not used.  Secret keys cannot use a Signer.
+	Signer *SignerDescriptor
 }
 
 // Registry holds zero or more key Pairs
@@ -40,18 +87,35 @@ type Registry interface {
 
 	// Register creates a new Pair from a Descriptor and stores it in this registry
 	Register(Descriptor) (Pair, error)
+
+	// Remove deletes the Pair associated with a given key identifier, returning true if
+	// a Pair was removed.
+	Remove(kid string) bool
+
+	// Pairs returns a snapshot of all the Pairs in this registry, ordered by key identifier
+	Pairs() []Pair
 }
 
 // NewRegistry creates a new key Registry backed by a given source of randomness for generation.
 // If random is nil, crypto/rand.Reader is used.
 func NewRegistry(random io.Reader) Registry {
+	return NewRegistryWithStore(random, nil)
+}
+
+// NewRegistryWithStore creates a new key Registry that persists the keys it generates in a Store.
+// A generated key is only created if the Store has no key for the descriptor's kid.  If store is nil,
+// generated keys are kept only in memory, which is the same as NewRegistry.
+func NewRegistryWithStore(random io.Reader, store Store) Registry {
 	if random == nil {
 		random = rand.Reader
 	}
 
 	return &registry{
-		pairs:  make(map[string]Pair),
-		random: random,
+		pairs:        make(map[string]Pair),
+		storeKids:    make(map[string]string),
+		restorations: make(map[string]restoration),
+		random:       random,
+		store:        store,
 	}
 }
 
@@ -59,6 +123,54 @@ type registry struct {
 	lock   sync.RWMutex
 	pairs  map[string]Pair
 	random io.Reader
+	store  Store
+
+	// storeKids maps registered kids onto the names of their stored keys, which
+	// differ when kids are derived from thumbprints
+	storeKids map[string]string
+
+	// restorations describes the versions of rotated keys restored from the store,
+	// by the kid of their descriptor
+	restorations map[string]restoration
+}
+
+// storedVersion is a version of a descriptor's key found in the store
+type storedVersion struct {
+	storeKid string
+
+	// rotated is when this version replaced the one before it, which is zero for the first version
+	rotated time.Time
+}
+
+// restoration describes the versions of a rotated key restored from the store
+type restoration struct {
+	// rotated is when the current version replaced the one before it, which is zero
+	// when the current version is the first
+	rotated time.Time
+
+	// retired holds the versions that the current version has replaced
+	retired []retiredVersion
+}
+
+// retiredVersion is a restored version of a rotated key that had already been replaced
+type retiredVersion struct {
+	kid     string
+	retired time.Time
+}
+
+// renewer is implemented by registries that can replace a registered pair in place, so that a
+// Reloader can pick up a renewed certificate without changing the kid
+type renewer interface {
+	renew(d Descriptor, kid string) (Pair, error)
+}
+
+// errKidChanged indicates that a renewed pair would have a different kid than the pair it replaces
+var errKidChanged = errors.New("the renewed key has a different kid")
+
+// restorer is implemented by registries that restore the versions of rotated keys from a store,
+// so that a Rotator can keep to the schedule of the current version and retire the others
+type restorer interface {
+	restored(kid string) (restoration, bool)
 }
 
 func (r *registry) Get(kid string) (Pair, bool) {
@@ -68,11 +180,100 @@ func (r *registry) Get(kid string) (Pair, bool) {
 	return p, ok
 }
 
+func (r *registry) Pairs() []Pair {
+	r.lock.RLock()
+	pairs := make([]Pair, 0, len(r.pairs))
+	for _, p := range r.pairs {
+		pairs = append(pairs, p)
+	}
+
+	r.lock.RUnlock()
+	slices.SortFunc(pairs, func(a, b Pair) int {
+		return strings.Compare(a.KID(), b.KID())
+	})
+
+	return pairs
+}
+
+// Remove deletes a pair from this registry.  Any stored key for the kid is deleted on
+// a best-effort basis, since a removed key is never used to sign again.
+func (r *registry) Remove(kid string) bool {
+	defer r.lock.Unlock()
+	r.lock.Lock()
+
+	_, ok := r.pairs[kid]
+	delete(r.pairs, kid)
+	if storeKid, stored := r.storeKids[kid]; stored {
+		delete(r.storeKids, kid)
+		_ = r.store.Delete(storeKid)
+	}
+
+	return ok
+}
+
 func (r *registry) newPair(d Descriptor) (Pair, error) {
+	if d.Signer != nil {
+		s, err := NewSigner(d, r.random)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewSignerPair(d.Kid, s)
+	}
+
 	if len(d.File) > 0 {
-		return ReadPair(d.Kid, d.File)
+		return readPair(d)
+	}
+
+	if r.store == nil {
+		return r.generatePair(d)
 	}
 
+	stored, err := r.store.Load(d.Kid)
+	switch {
+	case err == nil:
+		if err := CheckKey(d, stored); err != nil {
+			return nil, err
+		}
+
+		return NewPair(d.Kid, stored)
+
+	case errors.Is(err, ErrKeyNotStored):
+		p, err := r.generatePair(d)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.store.Save(d.Kid, p.Sign()); err != nil {
+			return nil, fmt.Errorf("unable to store generated key %s: %w", d.Kid, err)
+		}
+
+		return p, nil
+
+	default:
+		return nil, err
+	}
+}
+
+// readPair reads the Pair from a Descriptor's File, obtaining the passphrase if one is configured
+func readPair(d Descriptor) (Pair, error) {
+	var passphrase []byte
+	if d.Passphrase != nil {
+		var err error
+		if passphrase, err = d.Passphrase.Read(); err != nil {
+			return nil, fmt.Errorf("unable to obtain passphrase for kid %s: %w", d.Kid, err)
+		}
+	}
+
+	p, err := ReadPairWithPassphrase(d.Kid, d.File, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read key file %s: %w", d.File, err)
+	}
+
+	return p, nil
+}
+
+func (r *registry) generatePair(d Descriptor) (Pair, error) {
 	switch d.Type {
 	case "":
 		fallthrough
@@ -80,6 +281,8 @@ func (r *registry) newPair(d Descriptor) (Pair, error) {
 		return GenerateRSAPair(d.Kid, r.random, d.Bits)
 	case KeyTypeECDSA:
 		return GenerateECDSAPair(d.Kid, r.random, d.Bits)
+	case KeyTypeEd25519:
+		return GenerateEd25519Pair(d.Kid, r.random)
 	case KeyTypeSecret:
 		return GenerateSecretPair(d.Kid, r.random, d.Bits)
 	default:
@@ -88,11 +291,119 @@ func (r *registry) newPair(d Descriptor) (Pair, error) {
 }
 
 func (r *registry) Register(d Descriptor) (Pair, error) {
+	if r.store != nil && d.Signer == nil && len(d.File) == 0 {
+		versions, err := r.storedVersions(d)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(versions) > 0 {
+			return r.restore(d, versions)
+		}
+	}
+
 	p, err := r.newPair(d)
 	if err != nil {
 		return nil, err
 	}
 
+	return r.add(d, p)
+}
+
+// storedVersions returns the versions of a descriptor's key in the store, oldest first.  The first
+// version is stored under the descriptor's kid, while a Rotator stores each later version under the
+// descriptor's kid followed by the unix time of the rotation.
+func (r *registry) storedVersions(d Descriptor) ([]storedVersion, error) {
+	kids, err := r.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list stored keys: %w", err)
+	}
+
+	var versions []storedVersion
+	for _, kid := range kids {
+		if kid == d.Kid {
+			versions = append(versions, storedVersion{storeKid: kid})
+			continue
+		}
+
+		suffix, ok := strings.CutPrefix(kid, d.Kid+"-")
+		if !ok {
+			continue
+		}
+
+		if unix, err := strconv.ParseInt(suffix, 10, 64); err == nil && unix > 0 {
+			versions = append(versions, storedVersion{storeKid: kid, rotated: time.Unix(unix, 0)})
+		}
+	}
+
+	slices.SortFunc(versions, func(a, b storedVersion) int {
+		return a.rotated.Compare(b.rotated)
+	})
+
+	return versions, nil
+}
+
+// restore registers the stored versions of a descriptor's key, returning the newest as the current pair.
+// When the key is rotated, the replaced versions are registered as well, so that tokens they signed
+// before a restart can still be verified until a Rotator retires them.
+func (r *registry) restore(d Descriptor, versions []storedVersion) (Pair, error) {
+	if d.RotationInterval <= 0 {
+		versions = versions[len(versions)-1:]
+	}
+
+	var (
+		current Pair
+		rs      = restoration{rotated: versions[len(versions)-1].rotated}
+	)
+
+	for i, v := range versions {
+		vd := d
+		vd.Kid = v.storeKid
+		stored, err := r.store.Load(v.storeKid)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := CheckKey(vd, stored); err != nil {
+			return nil, err
+		}
+
+		p, err := NewPair(vd.Kid, stored)
+		if err != nil {
+			return nil, err
+		}
+
+		if p, err = r.add(vd, p); err != nil {
+			return nil, err
+		}
+
+		if i < len(versions)-1 {
+			rs.retired = append(rs.retired, retiredVersion{kid: p.KID(), retired: versions[i+1].rotated})
+		}
+
+		current = p
+	}
+
+	r.lock.Lock()
+	r.restorations[d.Kid] = rs
+	r.lock.Unlock()
+	return current, nil
+}
+
+func (r *registry) restored(kid string) (restoration, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	rs, ok := r.restorations[kid]
+	return rs, ok
+}
+
+// add applies a descriptor's metadata, certificates, and derived kid to a new pair and stores it
+func (r *registry) add(d Descriptor, p Pair) (Pair, error) {
+	p, err := r.prepare(d, p)
+	if err != nil {
+		return nil, err
+	}
+
 	defer r.lock.Unlock()
 	r.lock.Lock()
 
@@ -101,5 +412,72 @@ func (r *registry) Register(d Descriptor) (Pair, error) {
 	}
 
 	r.pairs[p.KID()] = p
+	if r.store != nil && d.Signer == nil && len(d.File) == 0 {
+		r.storeKids[p.KID()] = d.Kid
+	}
+
+	return p, nil
+}
+
+// renew loads a descriptor's key and certificates and, if they produce the given kid, replaces the
+// registered pair with that kid.  This is how a renewed certificate is picked up for a key whose kid
+// is derived from its thumbprint, as the kid doesn't change.  If the kid differs, errKidChanged is
+// returned and nothing is replaced.
+func (r *registry) renew(d Descriptor, kid string) (Pair, error) {
+	p, err := r.newPair(d)
+	if err != nil {
+		return nil, err
+	}
+
+	if p, err = r.prepare(d, p); err != nil {
+		return nil, err
+	}
+
+	if p.KID() != kid {
+		return nil, errKidChanged
+	}
+
+	defer r.lock.Unlock()
+	r.lock.Lock()
+
+	if _, ok := r.pairs[kid]; !ok {
+		return nil, errKidChanged
+	}
+
+	r.pairs[kid] = p
+	return p, nil
+}
+
+// prepare applies a descriptor's metadata, certificates, and derived kid to a new pair
+func (r *registry) prepare(d Descriptor, p Pair) (Pair, error) {
+	var err error
+	if pp, ok := p.(pair); ok && (len(d.Alg) > 0 || len(d.Use) > 0) {
+		if p, err = pp.withMetadata(d.Alg, d.Use); err != nil {
+			return nil, err
+		}
+	}
+
+	if pp, ok := p.(pair); ok && len(d.CertificateFile) > 0 {
+		chain, err := ReadCertificateChain(d.CertificateFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if p, err = pp.withCertificates(chain); err != nil {
+			return nil, fmt.Errorf("kid %s: %w", d.Kid, err)
+		}
+	}
+
+	if pp, ok := p.(pair); ok && d.ThumbprintKid {
+		kid, err := ThumbprintKID(pp, d.KidPrefix)
+		if err != nil {
+			return nil, err
+		}
+
+		if p, err = pp.withKID(kid); err != nil {
+			return nil, err
+		}
+	}
+
 	return p, nil
 }
@@ -3,7 +3,9 @@
 package key
 
 import (
+	"bytes"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"strconv"
 	"testing"
@@ -40,6 +42,9 @@ func testRegistryValidDescriptor(t *testing.T, d Descriptor) {
 	case KeyTypeECDSA:
 		_, ok := pair.Sign().(*ecdsa.PrivateKey)
 		assert.True(ok)
+	case KeyTypeEd25519:
+		_, ok := pair.Sign().(ed25519.PrivateKey)
+		assert.True(ok)
 	case KeyTypeSecret:
 		_, ok := pair.Sign().([]byte)
 		assert.True(ok)
@@ -87,6 +92,10 @@ func TestRegistry(t *testing.T) {
 				// nolint:goconst
 				{Kid: "test", Type: "ecdsa"},
 				// nolint:goconst
+				{Kid: "test", Type: "ed25519"},
+				// nolint:goconst
+				{Kid: "test", Type: "ed25519", File: "test.ed25519.pem"},
+				// nolint:goconst
 				{Kid: "test", Type: "secret"},
 			}
 
@@ -110,4 +119,76 @@ func TestRegistry(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("Pairs", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+
+			registry = NewRegistry(nil)
+		)
+
+		assert.Empty(registry.Pairs())
+		for _, kid := range []string{"charlie", "alpha", "bravo"} {
+			_, err := registry.Register(Descriptor{Kid: kid, Type: KeyTypeECDSA})
+			require.NoError(err)
+		}
+
+		pairs := registry.Pairs()
+		require.Len(pairs, 3)
+		assert.Equal("alpha", pairs[0].KID())
+		assert.Equal("bravo", pairs[1].KID())
+		assert.Equal("charlie", pairs[2].KID())
+	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+
+			registry = NewRegistry(nil)
+		)
+
+		pair, err := registry.Register(Descriptor{Kid: "test", Type: KeyTypeECDSA, Bits: 256, Alg: "ES256"})
+		require.NoError(err)
+		assert.Equal("ES256", pair.Alg())
+		assert.Equal(UseSignature, pair.Use())
+		assert.Equal("test", pair.JWK().KeyID())
+		assert.Equal("ES256", pair.JWK().Algorithm())
+
+		var output bytes.Buffer
+		_, err = pair.WriteJWK(&output)
+		require.NoError(err)
+		assert.Contains(output.String(), `"alg": "ES256"`)
+	})
+
+	t.Run("ThumbprintKid", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+
+			registry = NewRegistry(nil)
+			d        = Descriptor{Kid: "test", File: "test.pkcs8.pem", Alg: "RS256", ThumbprintKid: true, KidPrefix: "themis-"}
+		)
+
+		pair, err := registry.Register(d)
+		require.NoError(err)
+		assert.Regexp(`^themis-[A-Za-z0-9_-]{43}$`, pair.KID())
+		assert.Equal(pair.KID(), pair.JWK().KeyID())
+		assert.Equal("RS256", pair.Alg())
+
+		found, ok := registry.Get(pair.KID())
+		assert.True(ok)
+		assert.Equal(pair, found)
+		_, ok = registry.Get("test")
+		assert.False(ok)
+
+		// the same key always has the same kid, regardless of format or configured name
+		same, err := NewRegistry(nil).Register(Descriptor{Kid: "other", File: "test.pkcs1.pem", ThumbprintKid: true, KidPrefix: "themis-"})
+		require.NoError(err)
+		assert.Equal(pair.KID(), same.KID())
+
+		_, err = registry.Register(d)
+		assert.Error(err)
+	})
 }
@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNoReloadInterval = errors.New("a positive reload interval is required")
+	ErrNoReloadFile     = errors.New("a key file is required for reloading")
+	ErrReloaderStarted  = errors.New("the reloader has already been started")
+	ErrIncompatibleKey  = errors.New("the reloaded key is not compatible with the current key")
+)
+
+// Reloader periodically checks a Descriptor's key File for changes.  When the file changes, the new
+// key is validated and registered under a new kid, becoming the current Pair.  As with a Rotator, the
+// replaced Pair remains in the Registry until the retention period has elapsed.
+//
+// Files are polled rather than watched, since mounted secrets are typically replaced through symlinks
+// that file system notifications do not reliably report.
+type Reloader struct {
+	registry   Registry
+	descriptor Descriptor
+	logger     *zap.Logger
+	reloads    *prometheus.CounterVec
+
+	schedule
+	current      Pair
+	digest       [sha256.Size]byte
+	failedDigest [sha256.Size]byte
+	listeners    []func(Pair)
+}
+
+// NewReloader creates a Reloader for a Descriptor whose current Pair has already been registered from
+// its File.  Replaced pairs are removed from the Registry once retain has elapsed, which should be at
+// least as long as the lifetime of any token signed by them.  If retain is nonpositive, replaced pairs
+// are never removed.
+//
+// The reloads counter is optional.  If supplied, it must have the KidLabelKey and OutcomeLabelKey labels.
+func NewReloader(r Registry, d Descriptor, current Pair, retain time.Duration, logger *zap.Logger, reloads *prometheus.CounterVec) (*Reloader, error) {
+	switch {
+	case d.ReloadInterval <= 0:
+		return nil, ErrNoReloadInterval
+	case len(d.File) == 0:
+		return nil, ErrNoReloadFile
+	case current == nil:
+		return nil, ErrNoCurrentPair
+	}
+
+	digest, err := fileDigest(d)
+	if err != nil {
+		return nil, err
+	}
+
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	logger = logger.With(zap.String("kid", d.Kid), zap.String("file", d.File))
+	return &Reloader{
+		registry:   r,
+		descriptor: d,
+		logger:     logger,
+		reloads:    reloads,
+		current:    current,
+		digest:     digest,
+		schedule: schedule{
+			now: time.Now,
+			retirement: retirement{
+				registry: r,
+				retain:   retain,
+				logger:   logger,
+			},
+		},
+	}, nil
+}
+
+// Current returns the Pair that should presently be used for signing
+func (r *Reloader) Current() Pair {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.current
+}
+
+// OnReload registers a listener that is invoked with each new Pair after a successful reload.
+// Listeners are invoked synchronously, under the reloader's lock, and must not call back into the Reloader.
+func (r *Reloader) OnReload(l func(Pair)) {
+	r.lock.Lock()
+	r.listeners = append(r.listeners, l)
+	r.lock.Unlock()
+}
+
+// Reload immediately checks the key file.  If the file has changed, the new Pair is returned.  If the file
+// is unchanged, or its contents have already failed to load, this method returns a nil Pair and no error.
+func (r *Reloader) Reload() (Pair, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.reload(r.now())
+}
+
+func (r *Reloader) reload(now time.Time) (Pair, error) {
+	r.next = now.Add(r.descriptor.ReloadInterval)
+	digest, err := fileDigest(r.descriptor)
+	if err != nil {
+		r.count(FailOutcome)
+		r.logger.Error("unable to read signing key file", zap.Error(err))
+		return nil, err
+	}
+
+	if digest == r.digest || digest == r.failedDigest {
+		return nil, nil
+	}
+
+	// renewing only the certificate of a key with a thumbprint kid leaves the kid unchanged,
+	// so the current pair is replaced in place rather than retired
+	if rn, ok := r.registry.(renewer); ok && r.descriptor.ThumbprintKid {
+		next, err := rn.renew(r.descriptor, r.current.KID())
+		if err == nil {
+			r.renewed(next, digest)
+			return next, nil
+		} else if !errors.Is(err, errKidChanged) {
+			r.failedDigest = digest
+			r.count(FailOutcome)
+			r.logger.Error("signing key renewal failed", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	d := r.descriptor
+	d.Kid = r.versionKid(r.descriptor.Kid, now)
+	next, err := r.registry.Register(d)
+	if err == nil {
+		if err = compatible(r.current, next); err != nil {
+			r.registry.Remove(next.KID())
+		}
+	}
+
+	if err != nil {
+		// don't retry the same file contents, which would only produce the same error
+		r.failedDigest = digest
+		r.count(FailOutcome)
+		r.logger.Error("signing key reload failed", zap.String("nextKid", d.Kid), zap.Error(err))
+		return nil, err
+	}
+
+	previous := r.current
+	r.current = next
+	r.digest = digest
+	r.retirement.retire(previous.KID(), now)
+	for _, l := range r.listeners {
+		l(next)
+	}
+
+	r.count(SuccessOutcome)
+	r.logger.Info("reloaded signing key",
+		zap.String("previousKid", previous.KID()),
+		zap.String("currentKid", next.KID()),
+	)
+
+	r.retirement.purge(now)
+	return next, nil
+}
+
+// renewed makes a renewed pair, with the same kid as the current pair, current
+func (r *Reloader) renewed(next Pair, digest [sha256.Size]byte) {
+	r.current = next
+	r.digest = digest
+	for _, l := range r.listeners {
+		l(next)
+	}
+
+	r.count(SuccessOutcome)
+	r.logger.Info("renewed signing key certificate", zap.String("currentKid", next.KID()))
+}
+
+// fileDigest computes a digest over the contents of a Descriptor's key file and its certificate
+// file, if any.  A key and its certificate are often replaced together, but not atomically, so a
+// change to either file must trigger a reload.
+func fileDigest(d Descriptor) ([sha256.Size]byte, error) {
+	h := sha256.New()
+	for _, file := range []string{d.File, d.CertificateFile} {
+		if len(file) == 0 {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return [sha256.Size]byte{}, err
+		}
+
+		h.Write(data)
+	}
+
+	var digest [sha256.Size]byte
+	h.Sum(digest[:0])
+	return digest, nil
+}
+
+// compatible verifies that a reloaded Pair can be used with the same algorithm as the current Pair
+func compatible(current, next Pair) error {
+	var (
+		currentJWK = current.JWK()
+		nextJWK    = next.JWK()
+	)
+
+	if currentJWK.KeyType() != nextJWK.KeyType() {
+		return fmt.Errorf("%w: expected key type %s, got %s", ErrIncompatibleKey, currentJWK.KeyType(), nextJWK.KeyType())
+	}
+
+	if currentEC, ok := currentJWK.(jwk.ECDSAPublicKey); ok {
+		if nextEC := nextJWK.(jwk.ECDSAPublicKey); currentEC.Crv() != nextEC.Crv() {
+			return fmt.Errorf("%w: expected curve %s, got %s", ErrIncompatibleKey, currentEC.Crv(), nextEC.Crv())
+		}
+	}
+
+	return nil
+}
+
+func (r *Reloader) count(outcome string) {
+	if r.reloads != nil {
+		r.reloads.With(prometheus.Labels{
+			KidLabelKey:     r.descriptor.Kid,
+			OutcomeLabelKey: outcome,
+		}).Add(1)
+	}
+}
+
+// firstCheck computes when a started Reloader first checks its key file
+func (r *Reloader) firstCheck(now time.Time) time.Time {
+	return now.Add(r.descriptor.ReloadInterval)
+}
+
+// scheduledReload is the task run whenever a file check is due.  Failures are logged and counted,
+// and the file is checked again next interval.
+func (r *Reloader) scheduledReload(now time.Time) {
+	_, _ = r.reload(now)
+}
+
+// Start begins polling the key file in a background goroutine.  This method
+// may be used as an uber/fx OnStart hook.
+func (r *Reloader) Start(context.Context) error {
+	if !r.start(r.firstCheck, r.scheduledReload) {
+		return ErrReloaderStarted
+	}
+
+	return nil
+}
+
+// Stop halts polling, waiting for the background goroutine to exit or the
+// context to be canceled.  This method may be used as an uber/fx OnStop hook.
+func (r *Reloader) Stop(ctx context.Context) error {
+	return r.stop(ctx)
+}
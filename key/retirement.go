@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// retiredPair is a Pair that has been replaced, but is still published until
+// tokens signed with it have expired.
+type retiredPair struct {
+	kid     string
+	expires time.Time
+}
+
+// retirement tracks replaced Pairs, removing each from the Registry once its retention
+// period has elapsed.  This type is not safe for concurrent use.
+type retirement struct {
+	registry Registry
+	retain   time.Duration
+	logger   *zap.Logger
+	retired  []retiredPair
+}
+
+// retire schedules a replaced Pair for removal
+func (rt *retirement) retire(kid string, now time.Time) {
+	rt.retired = append(rt.retired, retiredPair{
+		kid:     kid,
+		expires: now.Add(rt.retain),
+	})
+}
+
+// purge removes any retired pairs whose retention period has elapsed
+func (rt *retirement) purge(now time.Time) {
+	if rt.retain <= 0 {
+		return
+	}
+
+	retained := rt.retired[:0]
+	for _, rp := range rt.retired {
+		if now.Before(rp.expires) {
+			retained = append(retained, rp)
+			continue
+		}
+
+		rt.registry.Remove(rp.kid)
+		rt.logger.Info("removed retired signing key", zap.String("retiredKid", rp.kid))
+	}
+
+	rt.retired = retained
+}
+
+// next returns the earlier of the given time and the next retired pair's expiry
+func (rt *retirement) next(next time.Time) time.Time {
+	if rt.retain > 0 {
+		for _, rp := range rt.retired {
+			if rp.expires.Before(next) {
+				next = rp.expires
+			}
+		}
+	}
+
+	return next
+}
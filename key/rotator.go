@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNoRotationInterval = errors.New("a positive rotation interval is required")
+	ErrNoCurrentPair      = errors.New("a current key pair is required")
+	ErrRotatorStarted     = errors.New("the rotator has already been started")
+	ErrRotateCertificate  = errors.New("keys bound to a certificate cannot be rotated")
+)
+
+// Rotator periodically replaces the current Pair for a Descriptor with a new Pair.  Replaced
+// pairs remain in the Registry, so that verifiers can continue to fetch them, until the
+// retention period has elapsed.
+type Rotator struct {
+	registry   Registry
+	descriptor Descriptor
+	logger     *zap.Logger
+	rotations  *prometheus.CounterVec
+
+	// restored indicates that the current Pair was restored from a store, in which case
+	// rotated is when it replaced the version before it, or zero if it is the first version
+	restored bool
+	rotated  time.Time
+
+	schedule
+	current   Pair
+	listeners []func(Pair)
+}
+
+// NewRotator creates a Rotator for a Descriptor whose current Pair has already been registered.
+// Retired pairs are removed from the Registry once retain has elapsed, which should be at least
+// as long as the lifetime of any token signed by them.  If retain is nonpositive, retired pairs
+// are never removed.
+//
+// The rotations counter is optional.  If supplied, it must have the KidLabelKey and OutcomeLabelKey labels.
+func NewRotator(r Registry, d Descriptor, current Pair, retain time.Duration, logger *zap.Logger, rotations *prometheus.CounterVec) (*Rotator, error) {
+	if d.RotationInterval <= 0 {
+		return nil, ErrNoRotationInterval
+	}
+
+	if current == nil {
+		return nil, ErrNoCurrentPair
+	}
+
+	// a generated key can never match a configured certificate
+	if len(d.CertificateFile) > 0 {
+		return nil, ErrRotateCertificate
+	}
+
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	logger = logger.With(zap.String("kid", d.Kid))
+	rotator := &Rotator{
+		registry:   r,
+		descriptor: d,
+		logger:     logger,
+		rotations:  rotations,
+		current:    current,
+		schedule: schedule{
+			now: time.Now,
+			retirement: retirement{
+				registry: r,
+				retain:   retain,
+				logger:   logger,
+			},
+		},
+	}
+
+	// versions replaced before a restart are retired as of when they were replaced
+	if rs, ok := r.(restorer); ok {
+		if restoration, ok := rs.restored(d.Kid); ok {
+			rotator.restored = true
+			rotator.rotated = restoration.rotated
+			rotator.version = restoration.rotated.Unix()
+			for _, rv := range restoration.retired {
+				rotator.retirement.retire(rv.kid, rv.retired)
+			}
+
+			rotator.retirement.purge(rotator.now())
+		}
+	}
+
+	return rotator, nil
+}
+
+// Current returns the Pair that should presently be used for signing
+func (r *Rotator) Current() Pair {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.current
+}
+
+// OnRotate registers a listener that is invoked with each new Pair after a successful rotation.
+// Listeners are invoked synchronously, under the rotator's lock, and must not call back into the Rotator.
+func (r *Rotator) OnRotate(l func(Pair)) {
+	r.lock.Lock()
+	r.listeners = append(r.listeners, l)
+	r.lock.Unlock()
+}
+
+// Rotate immediately replaces the current Pair with a newly registered one.  The previous Pair is retired.
+func (r *Rotator) Rotate() (Pair, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.rotate(r.now())
+}
+
+func (r *Rotator) rotate(now time.Time) (Pair, error) {
+	d := r.descriptor
+	d.Kid = r.versionKid(r.descriptor.Kid, now)
+	r.next = now.Add(r.descriptor.RotationInterval)
+
+	next, err := r.registry.Register(d)
+	if err != nil {
+		r.count(FailOutcome)
+		r.logger.Error("signing key rotation failed", zap.String("nextKid", d.Kid), zap.Error(err))
+		return nil, err
+	}
+
+	previous := r.current
+	r.current = next
+	r.restored = false
+	r.retirement.retire(previous.KID(), now)
+
+	for _, l := range r.listeners {
+		l(next)
+	}
+
+	r.count(SuccessOutcome)
+	r.logger.Info("rotated signing key",
+		zap.String("previousKid", previous.KID()),
+		zap.String("currentKid", next.KID()),
+		zap.Time("nextRotation", r.next),
+	)
+
+	r.retirement.purge(now)
+	return next, nil
+}
+
+func (r *Rotator) count(outcome string) {
+	if r.rotations != nil {
+		r.rotations.With(prometheus.Labels{
+			KidLabelKey:     r.descriptor.Kid,
+			OutcomeLabelKey: outcome,
+		}).Add(1)
+	}
+}
+
+// firstRotation computes when a started Rotator first rotates its current Pair.  A restored pair keeps
+// to the schedule it had before the restart, since restarts can easily come more often than rotations,
+// and an overdue pair is rotated right away.
+func (r *Rotator) firstRotation(now time.Time) time.Time {
+	switch {
+	case !r.restored:
+		return now.Add(r.descriptor.RotationInterval)
+	case r.rotated.IsZero():
+		// the first version has no rotation time, so its age is unknown
+		return now
+	default:
+		return r.rotated.Add(r.descriptor.RotationInterval)
+	}
+}
+
+// scheduledRotation is the task run whenever a rotation is due.  Failures are logged and counted,
+// and rotation is attempted again next interval.
+func (r *Rotator) scheduledRotation(now time.Time) {
+	_, _ = r.rotate(now)
+}
+
+// Start begins scheduled rotation in a background goroutine.  This method
+// may be used as an uber/fx OnStart hook.
+func (r *Rotator) Start(context.Context) error {
+	if !r.start(r.firstRotation, r.scheduledRotation) {
+		return ErrRotatorStarted
+	}
+
+	return nil
+}
+
+// Stop halts scheduled rotation, waiting for the background goroutine to exit
+// or the context to be canceled.  This method may be used as an uber/fx OnStop hook.
+func (r *Rotator) Stop(ctx context.Context) error {
+	return r.stop(ctx)
+}
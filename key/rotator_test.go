@@ -0,0 +1,286 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRotationCounter() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: RotationCounter},
+		[]string{KidLabelKey, OutcomeLabelKey},
+	)
+}
+
+func testNewRotatorInvalid(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		registry = NewRegistry(nil)
+	)
+
+	current, err := registry.Register(Descriptor{Kid: "test", Type: KeyTypeECDSA})
+	require.NoError(err)
+
+	r, err := NewRotator(registry, Descriptor{Kid: "test"}, current, time.Hour, nil, nil)
+	assert.Nil(r)
+	assert.ErrorIs(err, ErrNoRotationInterval)
+
+	r, err = NewRotator(registry, Descriptor{Kid: "test", RotationInterval: time.Hour}, nil, time.Hour, nil, nil)
+	assert.Nil(r)
+	assert.ErrorIs(err, ErrNoCurrentPair)
+}
+
+func testNewRotatorRotate(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		registry = NewRegistry(nil)
+		counter  = newTestRotationCounter()
+		d        = Descriptor{Kid: "test", Type: KeyTypeECDSA, Alg: "ES384", RotationInterval: time.Hour}
+		now      = time.Unix(1700000000, 0)
+	)
+
+	current, err := registry.Register(d)
+	require.NoError(err)
+
+	r, err := NewRotator(registry, d, current, 2*time.Hour, nil, counter)
+	require.NoError(err)
+	r.now = func() time.Time { return now }
+	assert.Equal(current, r.Current())
+
+	var notified []Pair
+	r.OnRotate(func(p Pair) {
+		notified = append(notified, p)
+	})
+
+	next, err := r.Rotate()
+	require.NoError(err)
+	require.NotNil(next)
+	assert.Equal("test-1700000000", next.KID())
+	assert.Equal("ES384", next.Alg())
+	assert.Equal(next, r.Current())
+	assert.Equal([]Pair{next}, notified)
+	assert.Equal(1.0, testutil.ToFloat64(counter.WithLabelValues("test", SuccessOutcome)))
+
+	// the retired key is still published
+	_, ok := registry.Get("test")
+	assert.True(ok)
+
+	now = now.Add(time.Hour)
+	_, err = r.Rotate()
+	require.NoError(err)
+	_, ok = registry.Get("test")
+	assert.True(ok)
+	assert.Len(registry.Pairs(), 3)
+
+	// the original key's retention has now elapsed
+	now = now.Add(time.Hour)
+	_, err = r.Rotate()
+	require.NoError(err)
+	_, ok = registry.Get("test")
+	assert.False(ok)
+	_, ok = registry.Get("test-1700000000")
+	assert.True(ok)
+	assert.Len(registry.Pairs(), 3)
+
+	// rotations within the same second still get distinct kids
+	next, err = r.Rotate()
+	require.NoError(err)
+	assert.Equal("test-1700007201", next.KID())
+
+	// a registration failure fails the rotation, leaving the current key in place
+	_, err = registry.Register(Descriptor{Kid: "test-1700007202", Type: KeyTypeECDSA})
+	require.NoError(err)
+	current = r.Current()
+	_, err = r.Rotate()
+	assert.Error(err)
+	assert.Equal(current, r.Current())
+	assert.Equal(1.0, testutil.ToFloat64(counter.WithLabelValues("test", FailOutcome)))
+}
+
+func testNewRotatorRestart(t *testing.T) {
+	var (
+		assert    = assert.New(t)
+		require   = require.New(t)
+		directory = t.TempDir()
+		d         = Descriptor{Kid: "test", Type: KeyTypeECDSA, RotationInterval: time.Hour}
+		now       = time.Now().Truncate(time.Second)
+		digest    = sha256.Sum256([]byte("signed before the restart"))
+	)
+
+	store, err := NewFileStore(directory)
+	require.NoError(err)
+
+	registry := NewRegistryWithStore(nil, store)
+	current, err := registry.Register(d)
+	require.NoError(err)
+
+	signature, err := current.Signer().Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(err)
+
+	r, err := NewRotator(registry, d, current, 2*time.Hour, nil, nil)
+	require.NoError(err)
+	r.now = func() time.Time { return now }
+
+	next, err := r.Rotate()
+	require.NoError(err)
+
+	// a new registry over the same store restores the newest version as current,
+	// and keeps publishing the version it replaced
+	restarted := NewRegistryWithStore(nil, store)
+	restored, err := restarted.Register(d)
+	require.NoError(err)
+	assert.Equal(next.KID(), restored.KID())
+	assert.Equal(next.JWK(), restored.JWK())
+
+	previous, ok := restarted.Get("test")
+	require.True(ok)
+	assert.True(ecdsa.VerifyASN1(previous.Signer().Public().(*ecdsa.PublicKey), digest[:], signature))
+
+	r, err = NewRotator(restarted, d, restored, 2*time.Hour, nil, nil)
+	require.NoError(err)
+	assert.Equal(restored, r.Current())
+	_, ok = restarted.Get("test")
+	assert.True(ok)
+
+	// the restored version is rotated on the schedule it had before the restart
+	require.NoError(r.Start(context.Background()))
+	r.lock.Lock()
+	assert.Equal(now.Add(time.Hour), r.next)
+	r.lock.Unlock()
+	require.NoError(r.Stop(context.Background()))
+
+	// once its retention elapses, the replaced version is removed from the store
+	r.retirement.purge(now.Add(3 * time.Hour))
+	_, ok = restarted.Get("test")
+	assert.False(ok)
+	assert.NoFileExists(filepath.Join(directory, "test.pem"))
+	assert.FileExists(filepath.Join(directory, next.KID()+".pem"))
+}
+
+// testNewRotatorRestoreOverdue restores keys that are older than the rotation interval,
+// which must be rotated as soon as the Rotator starts
+func testNewRotatorRestoreOverdue(t *testing.T) {
+	testData := []struct {
+		name   string
+		rotate bool
+	}{
+		{"FirstVersion", false},
+		{"RotatedVersion", true},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+				d       = Descriptor{Kid: "test", Type: KeyTypeECDSA, RotationInterval: time.Hour}
+				now     = time.Now().Truncate(time.Second)
+				rotated = make(chan Pair, 1)
+			)
+
+			store, err := NewFileStore(t.TempDir())
+			require.NoError(err)
+
+			registry := NewRegistryWithStore(nil, store)
+			current, err := registry.Register(d)
+			require.NoError(err)
+
+			if record.rotate {
+				r, err := NewRotator(registry, d, current, 0, nil, nil)
+				require.NoError(err)
+				r.now = func() time.Time { return now.Add(-3 * time.Hour) }
+				current, err = r.Rotate()
+				require.NoError(err)
+			}
+
+			restarted := NewRegistryWithStore(nil, store)
+			restored, err := restarted.Register(d)
+			require.NoError(err)
+			assert.Equal(current.KID(), restored.KID())
+
+			r, err := NewRotator(restarted, d, restored, 0, nil, nil)
+			require.NoError(err)
+			r.now = func() time.Time { return now }
+			r.OnRotate(func(p Pair) {
+				select {
+				case rotated <- p:
+				default:
+				}
+			})
+
+			require.NoError(r.Start(context.Background()))
+			defer r.Stop(context.Background())
+
+			select {
+			case p := <-rotated:
+				assert.Equal(fmt.Sprintf("test-%d", now.Unix()), p.KID())
+			case <-time.After(5 * time.Second):
+				assert.Fail("the overdue key was not rotated")
+			}
+		})
+	}
+}
+
+func testNewRotatorStartStop(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		registry = NewRegistry(nil)
+		d        = Descriptor{Kid: "test", Type: KeyTypeSecret, RotationInterval: 10 * time.Millisecond}
+		rotated  = make(chan Pair, 1)
+	)
+
+	current, err := registry.Register(d)
+	require.NoError(err)
+
+	r, err := NewRotator(registry, d, current, 0, nil, nil)
+	require.NoError(err)
+
+	r.OnRotate(func(p Pair) {
+		select {
+		case rotated <- p:
+		default:
+		}
+	})
+
+	require.NoError(r.Start(context.Background()))
+	assert.ErrorIs(r.Start(context.Background()), ErrRotatorStarted)
+
+	select {
+	case p := <-rotated:
+		assert.NotEqual("test", p.KID())
+	case <-time.After(5 * time.Second):
+		assert.Fail("no rotation occurred")
+	}
+
+	require.NoError(r.Stop(context.Background()))
+	require.NoError(r.Stop(context.Background()))
+
+	// retain is nonpositive, so nothing is ever removed
+	_, ok := registry.Get("test")
+	assert.True(ok)
+}
+
+func TestRotator(t *testing.T) {
+	t.Run("Invalid", testNewRotatorInvalid)
+	t.Run("Rotate", testNewRotatorRotate)
+	t.Run("Restart", testNewRotatorRestart)
+	t.Run("RestoreOverdue", testNewRotatorRestoreOverdue)
+	t.Run("StartStop", testNewRotatorStartStop)
+}
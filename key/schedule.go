@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// schedule is the state that Rotator and Reloader share for replacing a descriptor's current Pair
+// in a background goroutine.  The lock guards both this state and that of the embedding type.
+type schedule struct {
+	now func() time.Time
+
+	lock       sync.Mutex
+	next       time.Time
+	retirement retirement
+	version    int64
+	halt       chan struct{}
+	done       chan struct{}
+}
+
+// versionKid returns the kid for a new version of a descriptor's key, which is the descriptor's
+// kid followed by the unix time of the replacement.  Versions created within the same second
+// are given successive times, so that a new kid never collides with an earlier one.
+func (s *schedule) versionKid(kid string, now time.Time) string {
+	s.version = max(now.Unix(), s.version+1)
+	return fmt.Sprintf("%s-%d", kid, s.version)
+}
+
+// wait computes how long until the task is next due or the next retired pair expires
+func (s *schedule) wait(now time.Time) time.Duration {
+	return max(s.retirement.next(s.next).Sub(now), 0)
+}
+
+// start runs task in a background goroutine, first at the time returned by first and then
+// whenever the task is due again.  The task is invoked under the lock and must update next.
+// This method returns false if the schedule has already been started.
+func (s *schedule) start(first func(now time.Time) time.Time, task func(now time.Time)) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.halt != nil {
+		return false
+	}
+
+	s.next = first(s.now())
+	s.halt = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.halt, s.done, task)
+	return true
+}
+
+func (s *schedule) run(halt <-chan struct{}, done chan<- struct{}, task func(time.Time)) {
+	defer close(done)
+
+	s.lock.Lock()
+	timer := time.NewTimer(s.wait(s.now()))
+	s.lock.Unlock()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-halt:
+			return
+
+		case <-timer.C:
+			s.lock.Lock()
+			now := s.now()
+			if !now.Before(s.next) {
+				task(now)
+			} else {
+				s.retirement.purge(now)
+			}
+
+			timer.Reset(s.wait(now))
+			s.lock.Unlock()
+		}
+	}
+}
+
+// stop halts the background goroutine, waiting for it to exit or the context to be canceled.
+// Stopping a schedule that is not running does nothing.
+func (s *schedule) stop(ctx context.Context) error {
+	s.lock.Lock()
+	halt, done := s.halt, s.done
+	s.halt, s.done = nil, nil
+	s.lock.Unlock()
+
+	if halt == nil {
+		return nil
+	}
+
+	close(halt)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
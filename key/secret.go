@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"github.com/xmidt-org/sallust"
+	"github.com/xmidt-org/themis/v2/config"
+	"go.uber.org/zap"
+)
+
+// SecretOptions configures the opt-in route that publishes symmetric keys to trusted verifiers.
+// Symmetric keys are never served by the public key routes.
+type SecretOptions struct {
+	// Credentials are the bearer tokens that trusted verifiers must present, in an Authorization
+	// header, to fetch symmetric keys.  If empty, symmetric keys are not published at all.
+	Credentials []string
+}
+
+// SecretAccessError indicates that a request for a symmetric key was not authenticated
+type SecretAccessError struct{}
+
+func (SecretAccessError) Error() string {
+	return "Valid credentials are required to access secret keys"
+}
+
+func (SecretAccessError) StatusCode() int {
+	return http.StatusUnauthorized
+}
+
+func (SecretAccessError) Headers() http.Header {
+	return http.Header{
+		"WWW-Authenticate": []string{`Bearer realm="secrets"`},
+	}
+}
+
+// secretCredentials checks bearer tokens in constant time.  Hashes are compared so that
+// neither the number nor the lengths of credentials are revealed through timing.
+type secretCredentials [][sha256.Size]byte
+
+func newSecretCredentials(credentials []string) secretCredentials {
+	sc := make(secretCredentials, 0, len(credentials))
+	for _, c := range credentials {
+		if len(c) > 0 {
+			sc = append(sc, sha256.Sum256([]byte(c)))
+		}
+	}
+
+	return sc
+}
+
+func (sc secretCredentials) authenticate(request *http.Request) bool {
+	scheme, credential, ok := strings.Cut(request.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return false
+	}
+
+	presented := sha256.Sum256([]byte(strings.TrimSpace(credential)))
+	matched := 0
+	for _, c := range sc {
+		matched |= subtle.ConstantTimeCompare(presented[:], c[:])
+	}
+
+	return matched == 1
+}
+
+type HandlerSecret http.Handler
+
+// NewHandlerSecret creates a handler that serves symmetric keys as JWKs, typically from an endpoint
+// created with NewSecretEndpoint.  Each request must carry one of the given credentials as a bearer
+// token.  If there are no credentials, this function returns nil, as no request could ever be authorized.
+func NewHandlerSecret(e endpoint.Endpoint, credentials []string) HandlerSecret {
+	sc := newSecretCredentials(credentials)
+	if len(sc) == 0 {
+		return nil
+	}
+
+	return kithttp.NewServer(
+		e,
+		func(ctx context.Context, request *http.Request) (any, error) {
+			kid, ok := mux.Vars(request)["kid"]
+			if !ok {
+				return nil, ErrNoKidVariable
+			}
+
+			if !sc.authenticate(request) {
+				sallust.Get(ctx).Warn("unauthorized secret key request",
+					zap.String("kid", kid),
+					zap.String("remoteAddr", request.RemoteAddr),
+				)
+
+				return nil, SecretAccessError{}
+			}
+
+			sallust.Get(ctx).Info("secret key request",
+				zap.String("kid", kid),
+				zap.String("remoteAddr", request.RemoteAddr),
+			)
+
+			return kid, nil
+		},
+		func(_ context.Context, response http.ResponseWriter, value any) error {
+			response.Header().Set("Content-Type", ContentTypeJWK)
+			response.Header().Set("Cache-Control", "no-store")
+			_, err := value.(Pair).WriteJWK(response)
+			return err
+		},
+	)
+}
+
+// UnmarshalSecretOptions returns an uber/fx style provider that reads the SecretOptions at the given
+// configuration key.  If the key is not set, the zero value is returned and no secrets are published.
+func UnmarshalSecretOptions(configKey string) func(config.Unmarshaller) (SecretOptions, error) {
+	return func(u config.Unmarshaller) (SecretOptions, error) {
+		var o SecretOptions
+		if !u.IsSet(configKey) {
+			return o, nil
+		}
+
+		err := u.UnmarshalKey(configKey, &o)
+		return o, err
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/sallust"
+	"github.com/xmidt-org/themis/v2/config"
+)
+
+func testNewHandlerSecretRequest(kid, authorization string) *http.Request {
+	request := mux.SetURLVars(
+		httptest.NewRequest("GET", "/", nil).WithContext(
+			sallust.With(context.Background(), sallust.Default()),
+		),
+		map[string]string{"kid": kid},
+	)
+
+	if len(authorization) > 0 {
+		request.Header.Set("Authorization", authorization)
+	}
+
+	return request
+}
+
+func TestNewHandlerSecret(t *testing.T) {
+	var (
+		registry = NewRegistry(nil)
+		handler  = NewHandlerSecret(NewSecretEndpoint(registry), []string{"", "first", "second"})
+	)
+
+	require.NotNil(t, handler)
+	_, err := registry.Register(Descriptor{Kid: "secret", Type: KeyTypeSecret})
+	require.NoError(t, err)
+	_, err = registry.Register(Descriptor{Kid: "rsa"})
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		for _, authorization := range []string{"Bearer first", "bearer second"} {
+			var (
+				assert   = assert.New(t)
+				require  = require.New(t)
+				response = httptest.NewRecorder()
+			)
+
+			handler.ServeHTTP(response, testNewHandlerSecretRequest("secret", authorization))
+			assert.Equal(http.StatusOK, response.Code)
+			assert.Equal(ContentTypeJWK, response.Header().Get("Content-Type"))
+			assert.Equal("no-store", response.Header().Get("Cache-Control"))
+
+			k, err := jwk.ParseKey(response.Body.Bytes())
+			require.NoError(err)
+			assert.Equal(jwa.OctetSeq, k.KeyType())
+			assert.Equal("secret", k.KeyID())
+		}
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		for _, authorization := range []string{"", "Bearer", "Bearer ", "Bearer third", "Basic first"} {
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, testNewHandlerSecretRequest("secret", authorization))
+			assert.Equal(t, http.StatusUnauthorized, response.Code)
+			assert.NotEmpty(t, response.Header().Get("WWW-Authenticate"))
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		for _, kid := range []string{"rsa", "nosuch"} {
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, testNewHandlerSecretRequest(kid, "Bearer first"))
+			assert.Equal(t, http.StatusNotFound, response.Code)
+		}
+	})
+
+	t.Run("NoCredentials", func(t *testing.T) {
+		assert.Nil(t, NewHandlerSecret(NewSecretEndpoint(registry), nil))
+		assert.Nil(t, NewHandlerSecret(NewSecretEndpoint(registry), []string{""}))
+	})
+}
+
+func TestUnmarshalSecretOptions(t *testing.T) {
+	testData := []struct {
+		configuration string
+		expected      SecretOptions
+	}{
+		{`{}`, SecretOptions{}},
+		{`{"keySecrets": {"credentials": ["first", "second"]}}`, SecretOptions{Credentials: []string{"first", "second"}}},
+	}
+
+	for _, record := range testData {
+		t.Run(record.configuration, func(t *testing.T) {
+			v := viper.New()
+			require.NoError(t, config.Json(record.configuration)(config.ViperIn{}, v))
+
+			o, err := UnmarshalSecretOptions("keySecrets")(config.ViperUnmarshaller{Viper: v})
+			assert.NoError(t, err)
+			assert.Equal(t, record.expected, o)
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+const (
+	// SignerTypeFile is the external signer that reads a PEM-encoded private key from
+	// a file each time it signs.  The key is never retained in memory between signatures.
+	SignerTypeFile = "file"
+
+	// SignerTypeMemory is the external signer that holds a generated key in process.  This
+	// signer is intended for testing, as its keys do not survive restarts.
+	SignerTypeMemory = "memory"
+)
+
+var (
+	ErrNoSignerFactory      = errors.New("no signer factory is registered for that signer type")
+	ErrSignerSecretKey      = errors.New("external signers do not support secret keys")
+	ErrSignerKeyChanged     = errors.New("the signer's key no longer matches its public key")
+	ErrUnsupportedSignerKey = errors.New("the signer's public key type is not supported")
+)
+
+// SignerDescriptor describes an external signer that holds the private key for a Pair.  When
+// an external signer is used, the private key is never loaded into the Pair.  Rather, signing is
+// delegated through the crypto.Signer interface, which allows keys to be held by PKCS#11 devices,
+// cloud KMS services, signing agents, etc.
+type SignerDescriptor struct {
+	// Type is the name of a registered SignerFactory, such as "file" or "memory"
+	Type string
+
+	// Address is the signer-specific location of the private key, such as a system path,
+	// a socket, or a key URI.  The meaning of this field depends on the Type.
+	Address string
+}
+
+// SignerFactory is a strategy for creating a crypto.Signer for a Descriptor that has an external signer.
+// The supplied random source is only useful for signers that generate keys.
+type SignerFactory func(d Descriptor, random io.Reader) (crypto.Signer, error)
+
+var (
+	signerFactoriesLock sync.RWMutex
+	signerFactories     = map[string]SignerFactory{
+		SignerTypeFile: func(d Descriptor, _ io.Reader) (crypto.Signer, error) {
+			address := d.Signer.Address
+			if len(address) == 0 {
+				address = d.File
+			}
+
+			return NewFileSigner(address)
+		},
+		SignerTypeMemory: NewMemorySigner,
+	}
+)
+
+// RegisterSignerFactory makes a SignerFactory available under the given signer type, replacing any
+// existing factory with that type.  This is typically done from an init function, in the same manner
+// as database drivers.
+func RegisterSignerFactory(signerType string, f SignerFactory) {
+	signerFactoriesLock.Lock()
+	signerFactories[signerType] = f
+	signerFactoriesLock.Unlock()
+}
+
+// GetSignerFactory returns the SignerFactory registered under the given signer type
+func GetSignerFactory(signerType string) (SignerFactory, bool) {
+	signerFactoriesLock.RLock()
+	f, ok := signerFactories[signerType]
+	signerFactoriesLock.RUnlock()
+	return f, ok
+}
+
+// NewSigner uses the registered SignerFactory for a Descriptor's signer type to create a crypto.Signer
+func NewSigner(d Descriptor, random io.Reader) (crypto.Signer, error) {
+	if d.Signer == nil {
+		return nil, fmt.Errorf("%w: kid %s has no signer", ErrNoSignerFactory, d.Kid)
+	}
+
+	if d.Type == KeyTypeSecret {
+		return nil, fmt.Errorf("%w: kid %s", ErrSignerSecretKey, d.Kid)
+	}
+
+	f, ok := GetSignerFactory(d.Signer.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoSignerFactory, d.Signer.Type)
+	}
+
+	return f(d, random)
+}
+
+// NewSignerPair creates a Pair whose private key is held by a crypto.Signer.  The Pair's Sign and
+// Signer methods both return the given signer.
+func NewSignerPair(kid string, s crypto.Signer) (Pair, error) {
+	public := s.Public()
+	switch public.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedSignerKey, public)
+	}
+
+	verifyPEM, err := MarshalPKIXPublicKeyToPEM(public)
+	if err != nil {
+		return nil, err
+	}
+
+	jwkKey, err := jwk.New(public)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPair(kid, "", "", s, verifyPEM, jwkKey, nil)
+}
+
+// fileSigner is a crypto.Signer that reads its private key from disk for each signature
+type fileSigner struct {
+	path   string
+	public crypto.PublicKey
+}
+
+// NewFileSigner creates a crypto.Signer backed by a PEM-encoded private key file.  The file is read
+// again for each signature, so the private key is only in memory while signing.  If the file's key
+// is replaced with a different key, signing fails with ErrSignerKeyChanged.
+func NewFileSigner(path string) (crypto.Signer, error) {
+	s, err := readSigner(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return fileSigner{
+		path:   path,
+		public: s.Public(),
+	}, nil
+}
+
+// readSigner reads a private key file, which must contain an asymmetric key
+func readSigner(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnrecognizedKeyData, path)
+	}
+
+	key, err := parsePrivateKey(block, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, path)
+	}
+
+	s, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s contains a %T", ErrUnrecognizedKeyData, path, key)
+	}
+
+	return s, nil
+}
+
+func (fs fileSigner) Public() crypto.PublicKey {
+	return fs.public
+}
+
+func (fs fileSigner) Sign(random io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	s, err := readSigner(fs.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if pk, ok := s.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pk.Equal(fs.public) {
+		return nil, fmt.Errorf("%w: %s", ErrSignerKeyChanged, fs.path)
+	}
+
+	return s.Sign(random, digest, opts)
+}
+
+// memorySigner is an in-process crypto.Signer that hides its generated key
+type memorySigner struct {
+	signer crypto.Signer
+}
+
+// NewMemorySigner generates a key as described by a Descriptor's type and bits, and returns a
+// crypto.Signer that holds that key in memory.  Unlike a Pair created directly from a key, the
+// key itself is not exposed.  This signer is primarily useful for testing external signing.
+func NewMemorySigner(d Descriptor, random io.Reader) (crypto.Signer, error) {
+	var (
+		key any
+		err error
+	)
+
+	switch d.Type {
+	case "", KeyTypeRSA:
+		bits := d.Bits
+		if bits <= 0 {
+			bits = DefaultRSABits
+		}
+
+		key, err = rsa.GenerateKey(random, bits)
+
+	case KeyTypeECDSA:
+		curve, curveErr := curveForBits(d.Bits)
+		if curveErr != nil {
+			return nil, curveErr
+		}
+
+		key, err = ecdsa.GenerateKey(curve, random)
+
+	case KeyTypeEd25519:
+		_, key, err = ed25519.GenerateKey(random)
+
+	case KeyTypeSecret:
+		return nil, fmt.Errorf("%w: kid %s", ErrSignerSecretKey, d.Kid)
+
+	default:
+		return nil, fmt.Errorf("invalid key type: %s", d.Type)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return memorySigner{signer: key.(crypto.Signer)}, nil
+}
+
+func (ms memorySigner) Public() crypto.PublicKey {
+	return ms.signer.Public()
+}
+
+func (ms memorySigner) Sign(random io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return ms.signer.Sign(random, digest, opts)
+}
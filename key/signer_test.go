@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestSignerKey(t *testing.T, path string) *ecdsa.PrivateKey {
+	pair, err := GenerateECDSAPair("test", rand.Reader, 256)
+	require.NoError(t, err)
+
+	k := pair.Sign().(*ecdsa.PrivateKey)
+	der, err := x509.MarshalPKCS8PrivateKey(k)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: PEMTypePrivateKey, Bytes: der}), 0600))
+	return k
+}
+
+func TestNewFileSigner(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		path   = filepath.Join(t.TempDir(), "signer.pem")
+		k      = writeTestSignerKey(t, path)
+		digest = sha256.Sum256([]byte("test"))
+	)
+
+	s, err := NewFileSigner(path)
+	require.NoError(err)
+	require.NotNil(s)
+	assert.True(k.PublicKey.Equal(s.Public()))
+
+	_, isKey := s.(*ecdsa.PrivateKey)
+	assert.False(isKey)
+
+	signature, err := s.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(err)
+	assert.True(ecdsa.VerifyASN1(&k.PublicKey, digest[:], signature))
+
+	// replacing the key out from under the signer must not silently change signatures
+	writeTestSignerKey(t, path)
+	signature, err = s.Sign(rand.Reader, digest[:], crypto.SHA256)
+	assert.Nil(signature)
+	assert.ErrorIs(err, ErrSignerKeyChanged)
+
+	_, err = NewFileSigner(filepath.Join(t.TempDir(), "nosuch.pem"))
+	assert.ErrorIs(err, os.ErrNotExist)
+
+	garbage := filepath.Join(t.TempDir(), "garbage.pem")
+	require.NoError(os.WriteFile(garbage, []byte("garbage"), 0600))
+	_, err = NewFileSigner(garbage)
+	assert.ErrorIs(err, ErrUnrecognizedKeyData)
+
+	// PKCS#1 keys are supported as well
+	s, err = NewFileSigner("test.pkcs1.pem")
+	require.NoError(err)
+	assert.NotNil(s)
+}
+
+func TestNewMemorySigner(t *testing.T) {
+	testData := []Descriptor{
+		{Kid: "rsa"},
+		{Kid: "ecdsa", Type: KeyTypeECDSA},
+		{Kid: "ed25519", Type: KeyTypeEd25519},
+	}
+
+	for _, d := range testData {
+		t.Run(d.Kid, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+			)
+
+			s, err := NewMemorySigner(d, rand.Reader)
+			require.NoError(err)
+			require.NotNil(s)
+
+			p, err := NewSignerPair(d.Kid, s)
+			require.NoError(err)
+			assert.Equal(s, p.Signer())
+			assert.Equal(s, p.Sign())
+			assert.Equal(d.Kid, p.JWK().KeyID())
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := NewMemorySigner(Descriptor{Type: KeyTypeSecret}, rand.Reader)
+		assert.ErrorIs(t, err, ErrSignerSecretKey)
+
+		_, err = NewMemorySigner(Descriptor{Type: KeyTypeECDSA, Bits: 111}, rand.Reader)
+		assert.Error(t, err)
+
+		_, err = NewMemorySigner(Descriptor{Type: "nosuch"}, rand.Reader)
+		assert.Error(t, err)
+	})
+}
+
+type testUnsupportedSigner struct{}
+
+func (testUnsupportedSigner) Public() crypto.PublicKey {
+	return "unsupported"
+}
+
+func (testUnsupportedSigner) Sign(io.Reader, []byte, crypto.SignerOpts) ([]byte, error) {
+	return nil, nil
+}
+
+func TestRegistrySigner(t *testing.T) {
+	t.Run("File", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			require  = require.New(t)
+			path     = filepath.Join(t.TempDir(), "signer.pem")
+			k        = writeTestSignerKey(t, path)
+			registry = NewRegistry(nil)
+		)
+
+		p, err := registry.Register(Descriptor{Kid: "test", File: path, Alg: "ES256", Signer: &SignerDescriptor{Type: SignerTypeFile}})
+		require.NoError(err)
+		require.NotNil(p.Signer())
+		assert.True(k.PublicKey.Equal(p.Signer().Public()))
+		assert.Equal("ES256", p.Alg())
+
+		_, isKey := p.Sign().(*ecdsa.PrivateKey)
+		assert.False(isKey)
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			require  = require.New(t)
+			registry = NewRegistry(nil)
+		)
+
+		RegisterSignerFactory("test", func(d Descriptor, random io.Reader) (crypto.Signer, error) {
+			assert.Equal("test", d.Kid)
+			assert.Equal("agent.sock", d.Signer.Address)
+			return NewMemorySigner(Descriptor{Type: KeyTypeEd25519}, random)
+		})
+
+		f, ok := GetSignerFactory("test")
+		assert.True(ok)
+		assert.NotNil(f)
+
+		p, err := registry.Register(Descriptor{Kid: "test", Signer: &SignerDescriptor{Type: "test", Address: "agent.sock"}})
+		require.NoError(err)
+		assert.NotNil(p.Signer())
+
+		RegisterSignerFactory("unsupported", func(Descriptor, io.Reader) (crypto.Signer, error) {
+			return testUnsupportedSigner{}, nil
+		})
+
+		_, err = registry.Register(Descriptor{Kid: "unsupported", Signer: &SignerDescriptor{Type: "unsupported"}})
+		assert.ErrorIs(err, ErrUnsupportedSignerKey)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			registry = NewRegistry(nil)
+		)
+
+		_, err := registry.Register(Descriptor{Kid: "test", Signer: &SignerDescriptor{Type: "nosuch"}})
+		assert.ErrorIs(err, ErrNoSignerFactory)
+
+		_, err = registry.Register(Descriptor{Kid: "test", Type: KeyTypeSecret, Signer: &SignerDescriptor{Type: SignerTypeMemory}})
+		assert.ErrorIs(err, ErrSignerSecretKey)
+
+		_, err = NewSigner(Descriptor{Kid: "test"}, rand.Reader)
+		assert.ErrorIs(err, ErrNoSignerFactory)
+	})
+}
+
+func TestPairSigner(t *testing.T) {
+	secret, err := GenerateSecretPair("secret", rand.Reader, 32)
+	require.NoError(t, err)
+	assert.Nil(t, secret.Signer())
+
+	ecdsaPair, err := GenerateECDSAPair("ecdsa", rand.Reader, 256)
+	require.NoError(t, err)
+	assert.Equal(t, ecdsaPair.Sign(), ecdsaPair.Signer())
+}
@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xmidt-org/themis/v2/config"
+)
+
+const (
+	// PEMTypePrivateKey is the PEM block type for PKCS#8 private keys
+	PEMTypePrivateKey = "PRIVATE KEY"
+
+	// PEMTypeSecretKey is the PEM block type used to persist raw secret keys
+	PEMTypeSecretKey = "SECRET KEY"
+)
+
+var (
+	ErrKeyNotStored      = errors.New("no key has been stored for that kid")
+	ErrInvalidStoredKid  = errors.New("kid cannot be used as a stored key name")
+	ErrStoredKeyMismatch = errors.New("stored key does not match the key descriptor")
+)
+
+// Store is a strategy for persisting generated key material, so that keys survive restarts.
+// Keys are stored by kid.
+type Store interface {
+	// Load returns the private key stored under the given kid.  If no such key has been
+	// stored, this method returns ErrKeyNotStored.
+	Load(kid string) (any, error)
+
+	// Save stores a private key under the given kid, replacing any existing key.
+	Save(kid string, key any) error
+
+	// Delete removes any key stored under the given kid.  Deleting a kid that
+	// has no stored key is not an error.
+	Delete(kid string) error
+
+	// List returns the kids of every stored key, in no particular order
+	List() ([]string, error)
+}
+
+// StoreOptions is the configuration for a key Store
+type StoreOptions struct {
+	// Directory is the system path where keys are stored, one file per kid.  If unset,
+	// no store is used and generated keys live only in memory.
+	Directory string
+}
+
+// fileStore is a Store that writes each key as a PEM file in a directory
+type fileStore struct {
+	directory string
+}
+
+// NewFileStore creates a Store backed by a directory, creating that directory if necessary.
+// Asymmetric keys are written as PKCS#8 PEM blocks.  Secret keys are written as PEM blocks
+// of type PEMTypeSecretKey.  All files are only readable by the owner.
+func NewFileStore(directory string) (Store, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, err
+	}
+
+	return fileStore{directory: directory}, nil
+}
+
+func (fs fileStore) path(kid string) (string, error) {
+	if len(kid) == 0 || kid == "." || kid == ".." || filepath.Base(kid) != kid {
+		return "", fmt.Errorf("%w: %q", ErrInvalidStoredKid, kid)
+	}
+
+	return filepath.Join(fs.directory, kid+".pem"), nil
+}
+
+func (fs fileStore) Load(kid string) (any, error) {
+	path, err := fs.path(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrKeyNotStored
+	} else if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnrecognizedKeyData, path)
+	}
+
+	switch block.Type {
+	case PEMTypePrivateKey:
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+
+	case PEMTypeSecretKey:
+		return block.Bytes, nil
+
+	default:
+		return nil, fmt.Errorf("%w: %s has PEM type %s", ErrUnrecognizedKeyData, path, block.Type)
+	}
+}
+
+func (fs fileStore) Save(kid string, key any) error {
+	path, err := fs.path(kid)
+	if err != nil {
+		return err
+	}
+
+	block := &pem.Block{Type: PEMTypePrivateKey}
+	if secret, ok := key.([]byte); ok {
+		block.Type = PEMTypeSecretKey
+		block.Bytes = secret
+	} else if block.Bytes, err = x509.MarshalPKCS8PrivateKey(key); err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that a partially written key is never loaded
+	f, err := os.CreateTemp(fs.directory, "."+kid+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name()) // nolint: errcheck
+	err = f.Chmod(0600)
+	if err == nil {
+		err = pem.Encode(f, block)
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func (fs fileStore) Delete(kid string) error {
+	path, err := fs.path(kid)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (fs fileStore) List() ([]string, error) {
+	entries, err := os.ReadDir(fs.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var kids []string
+	for _, e := range entries {
+		name := e.Name()
+
+		// temporary files from incomplete saves start with a dot
+		if e.Type().IsRegular() && !strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".pem") {
+			kids = append(kids, strings.TrimSuffix(name, ".pem"))
+		}
+	}
+
+	return kids, nil
+}
+
+// CheckKey verifies that a private key is consistent with a Descriptor's type and bits.
+// This is used to refuse stored keys whose configuration has since changed.
+func CheckKey(d Descriptor, key any) error {
+	switch d.Type {
+	case "", KeyTypeRSA:
+		bits := d.Bits
+		if bits <= 0 {
+			bits = DefaultRSABits
+		}
+
+		k, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return fmt.Errorf("%w: kid %s: expected an rsa key, got %T", ErrStoredKeyMismatch, d.Kid, key)
+		} else if k.N.BitLen() != bits {
+			return fmt.Errorf("%w: kid %s: expected a %d-bit rsa key, got %d bits", ErrStoredKeyMismatch, d.Kid, bits, k.N.BitLen())
+		}
+
+	case KeyTypeECDSA:
+		curve, err := curveForBits(d.Bits)
+		if err != nil {
+			return err
+		}
+
+		k, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return fmt.Errorf("%w: kid %s: expected an ecdsa key, got %T", ErrStoredKeyMismatch, d.Kid, key)
+		} else if k.Curve != curve {
+			return fmt.Errorf("%w: kid %s: expected curve %s, got %s", ErrStoredKeyMismatch, d.Kid, curve.Params().Name, k.Curve.Params().Name)
+		}
+
+	case KeyTypeEd25519:
+		if _, ok := key.(ed25519.PrivateKey); !ok {
+			return fmt.Errorf("%w: kid %s: expected an ed25519 key, got %T", ErrStoredKeyMismatch, d.Kid, key)
+		}
+
+	case KeyTypeSecret:
+		bits := d.Bits
+		if bits <= 0 {
+			bits = DefaultSecretBits
+		}
+
+		k, ok := key.([]byte)
+		if !ok {
+			return fmt.Errorf("%w: kid %s: expected a secret key, got %T", ErrStoredKeyMismatch, d.Kid, key)
+		} else if len(k) != bits {
+			return fmt.Errorf("%w: kid %s: expected a secret of length %d, got %d", ErrStoredKeyMismatch, d.Kid, bits, len(k))
+		}
+
+	default:
+		return fmt.Errorf("invalid key type: %s", d.Type)
+	}
+
+	return nil
+}
+
+// UnmarshalStore returns an uber/fx style provider that creates a file-based Store from the
+// StoreOptions at the given configuration key.  If the key is not set or has no directory,
+// a nil Store is returned and keys are kept only in memory.
+func UnmarshalStore(configKey string) func(config.Unmarshaller) (Store, error) {
+	return func(u config.Unmarshaller) (Store, error) {
+		if !u.IsSet(configKey) {
+			return nil, nil
+		}
+
+		var o StoreOptions
+		if err := u.UnmarshalKey(configKey, &o); err != nil {
+			return nil, err
+		}
+
+		if len(o.Directory) == 0 {
+			return nil, nil
+		}
+
+		return NewFileStore(o.Directory)
+	}
+}
@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package key
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/config"
+)
+
+func testFileStoreRoundTrip(t *testing.T, d Descriptor) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		directory = filepath.Join(t.TempDir(), "keys")
+	)
+
+	store, err := NewFileStore(directory)
+	require.NoError(err)
+
+	first, err := NewRegistryWithStore(nil, store).Register(d)
+	require.NoError(err)
+
+	info, err := os.Stat(filepath.Join(directory, d.Kid+".pem"))
+	require.NoError(err)
+	assert.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	// a new registry, as after a restart, must load the same key
+	second, err := NewRegistryWithStore(nil, store).Register(d)
+	require.NoError(err)
+
+	// private keys carry precomputed values that needn't survive a round trip, so compare with Equal
+	if private, ok := first.Sign().(interface{ Equal(crypto.PrivateKey) bool }); ok {
+		assert.True(private.Equal(second.Sign()))
+	} else {
+		assert.Equal(first.Sign(), second.Sign())
+	}
+}
+
+func testFileStoreMismatch(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+	)
+
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(err)
+
+	_, err = NewRegistryWithStore(nil, store).Register(Descriptor{Kid: "test", Type: KeyTypeECDSA, Bits: 256})
+	require.NoError(err)
+
+	testData := []Descriptor{
+		{Kid: "test", Type: KeyTypeECDSA, Bits: 384},
+		{Kid: "test", Type: KeyTypeRSA},
+		{Kid: "test", Type: KeyTypeSecret},
+	}
+
+	for i, d := range testData {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			p, err := NewRegistryWithStore(nil, store).Register(d)
+			assert.Nil(p)
+			assert.ErrorIs(err, ErrStoredKeyMismatch)
+		})
+	}
+}
+
+func testFileStoreLoad(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		directory = t.TempDir()
+	)
+
+	store, err := NewFileStore(directory)
+	require.NoError(err)
+
+	k, err := store.Load("nosuch")
+	assert.Nil(k)
+	assert.ErrorIs(err, ErrKeyNotStored)
+
+	for _, kid := range []string{"", ".", "..", "../escape", "a/b"} {
+		assert.ErrorIs(store.Save(kid, []byte("secret")), ErrInvalidStoredKid)
+	}
+
+	require.NoError(os.WriteFile(filepath.Join(directory, "garbage.pem"), []byte("not pem"), 0600))
+	k, err = store.Load("garbage")
+	assert.Nil(k)
+	assert.ErrorIs(err, ErrUnrecognizedKeyData)
+
+	data, err := os.ReadFile("test.pkcs1.pem")
+	require.NoError(err)
+	require.NoError(os.WriteFile(filepath.Join(directory, "pkcs1.pem"), data, 0600))
+	k, err = store.Load("pkcs1")
+	assert.Nil(k)
+	assert.ErrorIs(err, ErrUnrecognizedKeyData)
+}
+
+func testFileStoreRemove(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		directory = t.TempDir()
+	)
+
+	store, err := NewFileStore(directory)
+	require.NoError(err)
+
+	registry := NewRegistryWithStore(nil, store)
+	_, err = registry.Register(Descriptor{Kid: "test", Type: KeyTypeSecret})
+	require.NoError(err)
+	require.FileExists(filepath.Join(directory, "test.pem"))
+
+	assert.True(registry.Remove("test"))
+	assert.NoFileExists(filepath.Join(directory, "test.pem"))
+	assert.False(registry.Remove("test"))
+	assert.NoError(store.Delete("test"))
+
+	// stored keys are named by the configured kid, even when the registered kid is derived
+	p, err := registry.Register(Descriptor{Kid: "derived", Type: KeyTypeSecret, ThumbprintKid: true})
+	require.NoError(err)
+	require.FileExists(filepath.Join(directory, "derived.pem"))
+	assert.NotEqual("derived", p.KID())
+	assert.True(registry.Remove(p.KID()))
+	assert.NoFileExists(filepath.Join(directory, "derived.pem"))
+}
+
+func TestFileStore(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		testData := []Descriptor{
+			{Kid: "rsa"},
+			{Kid: "ecdsa", Type: KeyTypeECDSA},
+			{Kid: "ecdsa256", Type: KeyTypeECDSA, Bits: 256},
+			{Kid: "ed25519", Type: KeyTypeEd25519},
+			{Kid: "secret", Type: KeyTypeSecret, Bits: 64},
+		}
+
+		for _, d := range testData {
+			t.Run(d.Kid, func(t *testing.T) {
+				testFileStoreRoundTrip(t, d)
+			})
+		}
+	})
+
+	t.Run("Mismatch", testFileStoreMismatch)
+	t.Run("Load", testFileStoreLoad)
+	t.Run("Remove", testFileStoreRemove)
+}
+
+func TestCheckKey(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+	)
+
+	rsaPair, err := ReadPair("test", "test.pkcs1.pem")
+	require.NoError(err)
+	rsaKey := rsaPair.Sign().(*rsa.PrivateKey)
+
+	ecdsaPair, err := GenerateECDSAPair("test", rand.Reader, 256)
+	require.NoError(err)
+	ecdsaKey := ecdsaPair.Sign().(*ecdsa.PrivateKey)
+
+	assert.NoError(CheckKey(Descriptor{Bits: rsaKey.N.BitLen()}, rsaKey))
+	assert.ErrorIs(CheckKey(Descriptor{Bits: 4096}, rsaKey), ErrStoredKeyMismatch)
+	assert.NoError(CheckKey(Descriptor{Type: KeyTypeECDSA, Bits: 256}, ecdsaKey))
+	assert.ErrorIs(CheckKey(Descriptor{Type: KeyTypeECDSA}, ecdsaKey), ErrStoredKeyMismatch)
+	assert.Error(CheckKey(Descriptor{Type: KeyTypeECDSA, Bits: 111}, ecdsaKey))
+	assert.ErrorIs(CheckKey(Descriptor{Type: KeyTypeEd25519}, ecdsaKey), ErrStoredKeyMismatch)
+	assert.NoError(CheckKey(Descriptor{Type: KeyTypeSecret, Bits: 3}, []byte("abc")))
+	assert.ErrorIs(CheckKey(Descriptor{Type: KeyTypeSecret}, []byte("abc")), ErrStoredKeyMismatch)
+	assert.Error(CheckKey(Descriptor{Type: "nosuch"}, []byte("abc")))
+}
+
+func testUnmarshalStore(t *testing.T, configuration string) (Store, error) {
+	v := viper.New()
+	require.NoError(t, config.Json(configuration)(config.ViperIn{}, v))
+	return UnmarshalStore("keyStore")(config.ViperUnmarshaller{Viper: v})
+}
+
+func TestUnmarshalStore(t *testing.T) {
+	t.Run("NotSet", func(t *testing.T) {
+		store, err := testUnmarshalStore(t, `{}`)
+		assert.NoError(t, err)
+		assert.Nil(t, store)
+	})
+
+	t.Run("NoDirectory", func(t *testing.T) {
+		store, err := testUnmarshalStore(t, `{"keyStore": {"directory": ""}}`)
+		assert.NoError(t, err)
+		assert.Nil(t, store)
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		directory := filepath.Join(t.TempDir(), "keys")
+		store, err := testUnmarshalStore(t, `{"keyStore": {"directory": "`+directory+`"}}`)
+		assert.NoError(t, err)
+		assert.NotNil(t, store)
+		assert.DirExists(t, directory)
+	})
+}
@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package oauth
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/xmidt-org/themis/v2/key"
+	"github.com/xmidt-org/themis/v2/token"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// ClaimScope is the claim holding the space-delimited scopes granted to a token
+	ClaimScope = "scope"
+
+	// ClaimClientID is the claim holding the id of the client a token was issued to
+	ClaimClientID = "client_id"
+
+	// basicChallenge is the WWW-Authenticate challenge for clients that attempted HTTP Basic authentication
+	basicChallenge = `Basic realm="themis"`
+)
+
+var (
+	ErrNoClientID            = errors.New("a client id is required")
+	ErrDuplicateClientID     = errors.New("duplicate client id")
+	ErrClientAuthentication  = errors.New("exactly one of secretHash, certificateSubject, or certificateThumbprint is required")
+	ErrClientClaimNotStatic  = errors.New("client claims must be statically configured")
+	ErrClientAuthFailed      = errors.New("client authentication failed")
+	ErrUnknownClient         = errors.New("unknown client")
+	ErrNoClientCertificate   = errors.New("no client certificate was presented")
+	ErrUnverifiedCertificate = errors.New("the client certificate was not verified")
+)
+
+// Client is the configuration for a single OAuth 2.0 client.  A client authenticates in exactly one
+// way: with a secret, with a CA-issued certificate, or with a self-signed certificate.
+type Client struct {
+	// ID is the client_id
+	ID string
+
+	// SecretHash is the bcrypt hash of the client secret.  A client with a SecretHash authenticates
+	// with its secret in either the Authorization header or the request body.
+	SecretHash string
+
+	// CertificateSubject is the subject distinguished name, e.g. CN=backend,O=Example, of the
+	// certificate a client authenticates with over mutual TLS.  The certificate must have been
+	// verified against the server's client CAs.
+	CertificateSubject string
+
+	// CertificateThumbprint is the x5t#S256 thumbprint of the certificate a client authenticates
+	// with over mutual TLS.  The certificate may be self-signed.
+	CertificateThumbprint string
+
+	// Scopes are the scopes this client may request.  A request without a scope parameter is
+	// granted all of these scopes.
+	Scopes []string
+
+	// Claims are extra claims added to every token issued to this client.  Only static values
+	// are allowed.
+	Claims []token.Value
+
+	// claims are the precomputed values of Claims
+	claims map[string]any
+}
+
+// grantScope determines the scopes granted for the space-delimited scopes requested by this client
+func (c *Client) grantScope(requested string) (string, error) {
+	if len(requested) == 0 {
+		return strings.Join(c.Scopes, " "), nil
+	}
+
+	scopes := strings.Fields(requested)
+	for _, s := range scopes {
+		if !slices.Contains(c.Scopes, s) {
+			return "", Error{Code: ErrorInvalidScope, Description: fmt.Sprintf("scope %s is not allowed", s)}
+		}
+	}
+
+	return strings.Join(scopes, " "), nil
+}
+
+// authenticate verifies the credentials a client presented in a token request
+func (c *Client) authenticate(tr *TokenRequest) error {
+	if len(c.SecretHash) > 0 {
+		if len(tr.ClientSecret) == 0 {
+			return ErrClientAuthFailed
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(c.SecretHash), []byte(tr.ClientSecret)); err != nil {
+			return fmt.Errorf("%w: %w", ErrClientAuthFailed, err)
+		}
+
+		return nil
+	}
+
+	if len(tr.ClientSecret) > 0 {
+		// a client bound to a certificate must not also be able to use a secret
+		return ErrClientAuthFailed
+	}
+
+	if tr.TLS == nil || len(tr.TLS.PeerCertificates) == 0 {
+		return ErrNoClientCertificate
+	}
+
+	leaf := tr.TLS.PeerCertificates[0]
+	if len(c.CertificateThumbprint) > 0 {
+		if key.CertificateThumbprintS256(leaf) != c.CertificateThumbprint {
+			return ErrClientAuthFailed
+		}
+
+		return nil
+	}
+
+	if len(tr.TLS.VerifiedChains) == 0 {
+		return ErrUnverifiedCertificate
+	}
+
+	if leaf.Subject.String() != c.CertificateSubject {
+		return ErrClientAuthFailed
+	}
+
+	return nil
+}
+
+// Clients is the registry of OAuth 2.0 clients
+type Clients struct {
+	clients map[string]*Client
+}
+
+// NewClients validates client configurations and creates a registry for them
+func NewClients(clients []Client) (*Clients, error) {
+	cs := &Clients{
+		clients: make(map[string]*Client, len(clients)),
+	}
+
+	for _, c := range clients {
+		if len(c.ID) == 0 {
+			return nil, ErrNoClientID
+		}
+
+		if _, exists := cs.clients[c.ID]; exists {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateClientID, c.ID)
+		}
+
+		methods := 0
+		for _, method := range []string{c.SecretHash, c.CertificateSubject, c.CertificateThumbprint} {
+			if len(method) > 0 {
+				methods++
+			}
+		}
+
+		if methods != 1 {
+			return nil, fmt.Errorf("%w: client %s", ErrClientAuthentication, c.ID)
+		}
+
+		if len(c.SecretHash) > 0 {
+			if _, err := bcrypt.Cost([]byte(c.SecretHash)); err != nil {
+				return nil, fmt.Errorf("invalid secretHash for client %s: %w", c.ID, err)
+			}
+		}
+
+		c.claims = make(map[string]any, len(c.Claims))
+		for _, v := range c.Claims {
+			if err := v.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid claim for client %s: %w", c.ID, err)
+			} else if !v.IsStatic() {
+				return nil, fmt.Errorf("%w: client %s, claim %s", ErrClientClaimNotStatic, c.ID, v.Key)
+			}
+
+			raw, err := v.RawMessage()
+			if err != nil {
+				return nil, fmt.Errorf("invalid claim for client %s: %w", c.ID, err)
+			}
+
+			c.claims[v.Key] = raw
+		}
+
+		cs.clients[c.ID] = &c
+	}
+
+	return cs, nil
+}
+
+// Authenticate determines the Client that sent a token request.  If the request has no client
+// credentials, this method returns nil with no error, leaving it to each Grant to decide whether
+// client authentication is required.
+func (cs *Clients) Authenticate(tr *TokenRequest) (*Client, error) {
+	if len(tr.ClientID) == 0 {
+		return nil, nil
+	}
+
+	var challenge string
+	if tr.Basic {
+		challenge = basicChallenge
+	}
+
+	c, ok := cs.clients[tr.ClientID]
+	if !ok {
+		return nil, Error{Code: ErrorInvalidClient, Challenge: challenge, Err: fmt.Errorf("%w: %s", ErrUnknownClient, tr.ClientID)}
+	}
+
+	if err := c.authenticate(tr); err != nil {
+		return nil, Error{Code: ErrorInvalidClient, Challenge: challenge, Err: err}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package oauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/key"
+	"github.com/xmidt-org/themis/v2/token"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testSecretHash produces a bcrypt hash cheap enough for tests
+func testSecretHash(t *testing.T, secret string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.MinCost)
+	require.NoError(t, err)
+	return string(hash)
+}
+
+// testCertificate produces a self-signed client certificate
+func testCertificate(t *testing.T, commonName string) *x509.Certificate {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &private.PublicKey, private)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func testClientGrantScope(t *testing.T) {
+	c := Client{ID: "test", Scopes: []string{"read", "write"}}
+
+	testData := []struct {
+		requested string
+		expected  string
+		invalid   bool
+	}{
+		{"", "read write", false},
+		{"read", "read", false},
+		{"write  read", "write read", false},
+		{"read admin", "", true},
+	}
+
+	for _, record := range testData {
+		t.Run(record.requested, func(t *testing.T) {
+			scope, err := c.grantScope(record.requested)
+			assert.Equal(t, record.expected, scope)
+			if record.invalid {
+				var e Error
+				require.ErrorAs(t, err, &e)
+				assert.Equal(t, ErrorInvalidScope, e.Code)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func testNewClientsInvalid(t *testing.T) {
+	hash := testSecretHash(t, "secret")
+
+	testData := []struct {
+		name     string
+		clients  []Client
+		expected error
+	}{
+		{"NoID", []Client{{SecretHash: hash}}, ErrNoClientID},
+		{"Duplicate", []Client{{ID: "a", SecretHash: hash}, {ID: "a", SecretHash: hash}}, ErrDuplicateClientID},
+		{"NoAuthentication", []Client{{ID: "a"}}, ErrClientAuthentication},
+		{"TwoAuthentications", []Client{{ID: "a", SecretHash: hash, CertificateThumbprint: "abc"}}, ErrClientAuthentication},
+		{"InvalidHash", []Client{{ID: "a", SecretHash: "plaintext"}}, bcrypt.ErrHashTooShort},
+		{"InvalidClaim", []Client{{ID: "a", SecretHash: hash, Claims: []token.Value{{Value: "x"}}}}, token.ErrMissingKey},
+		{"ClaimNotStatic", []Client{{ID: "a", SecretHash: hash, Claims: []token.Value{{Key: "x", Header: "X-Test"}}}}, ErrClientClaimNotStatic},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			cs, err := NewClients(record.clients)
+			assert.Nil(t, cs)
+			assert.ErrorIs(t, err, record.expected)
+		})
+	}
+}
+
+func testClientsAuthenticate(t *testing.T) {
+	var (
+		subjectCert    = testCertificate(t, "subject")
+		thumbprintCert = testCertificate(t, "thumbprint")
+	)
+
+	cs, err := NewClients([]Client{
+		{ID: "secret", SecretHash: testSecretHash(t, "secret")},
+		{ID: "subject", CertificateSubject: "CN=subject"},
+		{ID: "thumbprint", CertificateThumbprint: key.CertificateThumbprintS256(thumbprintCert)},
+	})
+
+	require.NoError(t, err)
+
+	var (
+		verified = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{subjectCert},
+			VerifiedChains:   [][]*x509.Certificate{{subjectCert}},
+		}
+
+		unverified = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{subjectCert},
+		}
+
+		selfSigned = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{thumbprintCert},
+		}
+	)
+
+	testData := []struct {
+		name     string
+		request  TokenRequest
+		expected string
+		err      error
+	}{
+		{"NoCredentials", TokenRequest{}, "", nil},
+		{"Secret", TokenRequest{ClientID: "secret", ClientSecret: "secret"}, "secret", nil},
+		{"WrongSecret", TokenRequest{ClientID: "secret", ClientSecret: "wrong"}, "", ErrClientAuthFailed},
+		{"MissingSecret", TokenRequest{ClientID: "secret"}, "", ErrClientAuthFailed},
+		{"UnknownClient", TokenRequest{ClientID: "nosuch", ClientSecret: "secret"}, "", ErrUnknownClient},
+		{"Subject", TokenRequest{ClientID: "subject", TLS: verified}, "subject", nil},
+		{"SubjectUnverified", TokenRequest{ClientID: "subject", TLS: unverified}, "", ErrUnverifiedCertificate},
+		{"SubjectMismatch", TokenRequest{ClientID: "subject", TLS: &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{thumbprintCert},
+			VerifiedChains:   [][]*x509.Certificate{{thumbprintCert}},
+		}}, "", ErrClientAuthFailed},
+		{"SubjectWithSecret", TokenRequest{ClientID: "subject", ClientSecret: "secret", TLS: verified}, "", ErrClientAuthFailed},
+		{"NoCertificate", TokenRequest{ClientID: "subject"}, "", ErrNoClientCertificate},
+		{"Thumbprint", TokenRequest{ClientID: "thumbprint", TLS: selfSigned}, "thumbprint", nil},
+		{"ThumbprintMismatch", TokenRequest{ClientID: "thumbprint", TLS: verified}, "", ErrClientAuthFailed},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			c, err := cs.Authenticate(&record.request)
+			if record.err != nil {
+				assert.Nil(t, c)
+				assert.ErrorIs(t, err, record.err)
+
+				var e Error
+				require.ErrorAs(t, err, &e)
+				assert.Equal(t, ErrorInvalidClient, e.Code)
+				return
+			}
+
+			require.NoError(t, err)
+			if len(record.expected) == 0 {
+				assert.Nil(t, c)
+			} else {
+				require.NotNil(t, c)
+				assert.Equal(t, record.expected, c.ID)
+			}
+		})
+	}
+
+	t.Run("BasicChallenge", func(t *testing.T) {
+		_, err := cs.Authenticate(&TokenRequest{ClientID: "secret", ClientSecret: "wrong", Basic: true})
+		var e Error
+		require.ErrorAs(t, err, &e)
+		assert.Equal(t, basicChallenge, e.Challenge)
+	})
+}
+
+func TestClient(t *testing.T) {
+	t.Run("GrantScope", testClientGrantScope)
+}
+
+func TestClients(t *testing.T) {
+	t.Run("Invalid", testNewClientsInvalid)
+	t.Run("Authenticate", testClientsAuthenticate)
+}
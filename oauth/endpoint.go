@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package oauth
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/url"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/xmidt-org/sallust"
+	"github.com/xmidt-org/themis/v2/token"
+	"go.uber.org/zap"
+)
+
+const (
+	// GrantTypeClientCredentials is the RFC 6749 section 4.4 grant_type
+	GrantTypeClientCredentials = "client_credentials"
+
+	// TokenTypeBearer is the token_type of every token issued by the token endpoint
+	TokenTypeBearer = "Bearer"
+)
+
+var (
+	ErrClientRequired = errors.New("client authentication is required")
+)
+
+// TokenRequest is a decoded request to the token endpoint
+type TokenRequest struct {
+	// GrantType is the grant_type parameter
+	GrantType string
+
+	// Form holds the request's form parameters
+	Form url.Values
+
+	// ClientID is the client_id, from either the Authorization header or the form
+	ClientID string
+
+	// ClientSecret is the client_secret, from either the Authorization header or the form
+	ClientSecret string
+
+	// Basic indicates whether the client credentials were sent with HTTP Basic authentication
+	Basic bool
+
+	// TLS is the state of the underlying TLS connection, if any
+	TLS *tls.ConnectionState
+
+	// Client is the authenticated client.  This field is nil if the request carried no client credentials.
+	Client *Client
+}
+
+// TokenResponse is the RFC 6749 section 5.1 successful token response.  IssuedTokenType is only
+// set for RFC 8693 token exchange.
+type TokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in,omitempty"`
+	Scope           string `json:"scope,omitempty"`
+}
+
+// Grant produces the token Request for one grant type.  The returned Request is passed to the
+// token Factory, so the issued token goes through the same ClaimBuilders as any other token.
+type Grant interface {
+	Request(context.Context, *TokenRequest) (*token.Request, error)
+}
+
+type GrantFunc func(context.Context, *TokenRequest) (*token.Request, error)
+
+func (gf GrantFunc) Request(ctx context.Context, tr *TokenRequest) (*token.Request, error) {
+	return gf(ctx, tr)
+}
+
+// Grants maps grant_type values onto the Grant that handles them
+type Grants map[string]Grant
+
+// ClientCredentials is the RFC 6749 section 4.4 client credentials Grant.  The token is issued to the
+// authenticated client itself, with the client's configured claims along with sub, client_id, and scope.
+// The client id is also passed to any remote claims as client_id metadata.
+func ClientCredentials(_ context.Context, tr *TokenRequest) (*token.Request, error) {
+	if tr.Client == nil {
+		return nil, Error{Code: ErrorInvalidClient, Err: ErrClientRequired}
+	}
+
+	scope, err := tr.Client.grantScope(tr.Form.Get("scope"))
+	if err != nil {
+		return nil, err
+	}
+
+	r := token.NewRequest()
+	r.TLS = tr.TLS
+	maps.Copy(r.Claims, tr.Client.claims)
+	r.Claims["sub"] = tr.Client.ID
+	r.Claims[ClaimClientID] = tr.Client.ID
+	if len(scope) > 0 {
+		r.Claims[ClaimScope] = scope
+	}
+
+	r.Metadata[ClaimClientID] = tr.Client.ID
+	return r, nil
+}
+
+// NewTokenEndpoint returns a go-kit endpoint for the token endpoint.  Clients are authenticated before
+// the Grant for the request's grant_type is invoked.  If the Factory is a token.ClaimsFactory, the lifetime
+// of each token is reported to clients as expires_in.
+func NewTokenEndpoint(f token.Factory, cs *Clients, g Grants) endpoint.Endpoint {
+	return func(ctx context.Context, value any) (any, error) {
+		tr := value.(*TokenRequest)
+		grant, ok := g[tr.GrantType]
+		if !ok {
+			return nil, Error{Code: ErrorUnsupportedGrantType, Description: "unsupported grant_type " + tr.GrantType}
+		}
+
+		client, err := cs.Authenticate(tr)
+		if err != nil {
+			sallust.Get(ctx).Info("client authentication failed", zap.String("client_id", tr.ClientID), zap.Error(err))
+			return nil, err
+		}
+
+		tr.Client = client
+		r, err := grant.Request(ctx, tr)
+		if err != nil {
+			return nil, err
+		}
+
+		r.Logger = sallust.Get(ctx)
+		var (
+			accessToken string
+			claims      map[string]any
+		)
+
+		if cf, ok := f.(token.ClaimsFactory); ok {
+			accessToken, claims, err = cf.NewTokenClaims(ctx, r)
+		} else {
+			accessToken, err = f.NewToken(ctx, r)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		response := TokenResponse{
+			AccessToken: accessToken,
+			TokenType:   TokenTypeBearer,
+			ExpiresIn:   expiresIn(claims),
+		}
+
+		response.Scope, _ = r.Claims[ClaimScope].(string)
+		if tr.GrantType == GrantTypeTokenExchange {
+			response.IssuedTokenType = TokenTypeJWT
+		}
+
+		return response, nil
+	}
+}
+
+// expiresIn is the lifetime, in seconds, of a token with the given claims.  The lifetime is measured
+// from iat, when present, since the exp of a token may vary with its trust.  This function returns
+// zero for a token with no exp.
+func expiresIn(claims map[string]any) int64 {
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return 0
+	}
+
+	iat, ok := numericClaim(claims, "iat")
+	if !ok {
+		iat = time.Now().Unix()
+	}
+
+	return max(exp-iat, 0)
+}
+
+// numericClaim extracts a claim holding a number of seconds
+func numericClaim(claims map[string]any, name string) (int64, bool) {
+	switch v := claims[name].(type) {
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
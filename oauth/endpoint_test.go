@@ -0,0 +1,277 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/token"
+)
+
+// testFactory records the Request it was asked to issue a token for.  Its tokens are valid for an hour,
+// unless err is set, in which case it fails with that error.
+type testFactory struct {
+	request *token.Request
+	err     error
+}
+
+func (tf *testFactory) NewToken(ctx context.Context, r *token.Request) (string, error) {
+	issued, _, err := tf.NewTokenClaims(ctx, r)
+	return issued, err
+}
+
+func (tf *testFactory) NewTokenClaims(_ context.Context, r *token.Request) (string, map[string]any, error) {
+	tf.request = r
+	if tf.err != nil {
+		return "", nil, tf.err
+	}
+
+	iat := time.Now().Unix()
+	return "issued", map[string]any{"iat": iat, "exp": iat + 3600}, nil
+}
+
+func testTokenHandler(t *testing.T) (TokenHandler, *testFactory) {
+	cs, err := NewClients([]Client{
+		{
+			ID:         "backend",
+			SecretHash: testSecretHash(t, "s3cret:/"),
+			Scopes:     []string{"read", "write"},
+			Claims:     []token.Value{{Key: "partner-id", Value: "comcast"}},
+		},
+	})
+
+	require.NoError(t, err)
+	f := new(testFactory)
+	e := NewTokenEndpoint(f, cs, Grants{GrantTypeClientCredentials: GrantFunc(ClientCredentials)})
+	return NewTokenHandler(e), f
+}
+
+func testTokenRequest(form url.Values) *http.Request {
+	request := httptest.NewRequest("POST", "/token", strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return request
+}
+
+func testClientCredentialsSuccess(t *testing.T) {
+	testData := []struct {
+		name          string
+		form          url.Values
+		basic         bool
+		expectedScope string
+	}{
+		{"FormCredentials", url.Values{"client_id": {"backend"}, "client_secret": {"s3cret:/"}}, false, "read write"},
+		{"BasicCredentials", url.Values{"scope": {"read"}}, true, "read"},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+
+				handler, f = testTokenHandler(t)
+				response   = httptest.NewRecorder()
+			)
+
+			record.form.Set("grant_type", GrantTypeClientCredentials)
+			request := testTokenRequest(record.form)
+			if record.basic {
+				request.SetBasicAuth("backend", url.QueryEscape("s3cret:/"))
+			}
+
+			handler.ServeHTTP(response, request)
+			require.Equal(http.StatusOK, response.Code)
+			assert.Equal("no-store", response.Header().Get("Cache-Control"))
+			assert.Equal("no-cache", response.Header().Get("Pragma"))
+
+			var tr TokenResponse
+			require.NoError(json.Unmarshal(response.Body.Bytes(), &tr))
+			assert.Equal(
+				TokenResponse{AccessToken: "issued", TokenType: TokenTypeBearer, ExpiresIn: 3600, Scope: record.expectedScope},
+				tr,
+			)
+
+			require.NotNil(f.request)
+			assert.Equal("backend", f.request.Claims["sub"])
+			assert.Equal("backend", f.request.Claims[ClaimClientID])
+			assert.Equal(record.expectedScope, f.request.Claims[ClaimScope])
+			assert.Equal(json.RawMessage(`"comcast"`), f.request.Claims["partner-id"])
+			assert.Equal("backend", f.request.Metadata[ClaimClientID])
+		})
+	}
+}
+
+func testClientCredentialsError(t *testing.T) {
+	testData := []struct {
+		name              string
+		form              url.Values
+		basicID           string
+		basicSecret       string
+		expectedStatus    int
+		expectedError     string
+		expectedChallenge string
+	}{
+		{
+			name:           "NoGrantType",
+			form:           url.Values{"client_id": {"backend"}, "client_secret": {"s3cret:/"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  ErrorInvalidRequest,
+		},
+		{
+			name:           "UnsupportedGrantType",
+			form:           url.Values{"grant_type": {"password"}, "client_id": {"backend"}, "client_secret": {"s3cret:/"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  ErrorUnsupportedGrantType,
+		},
+		{
+			name:           "NoClient",
+			form:           url.Values{"grant_type": {GrantTypeClientCredentials}},
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  ErrorInvalidClient,
+		},
+		{
+			name:           "WrongSecret",
+			form:           url.Values{"grant_type": {GrantTypeClientCredentials}, "client_id": {"backend"}, "client_secret": {"wrong"}},
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  ErrorInvalidClient,
+		},
+		{
+			name:              "WrongBasicSecret",
+			form:              url.Values{"grant_type": {GrantTypeClientCredentials}},
+			basicID:           "backend",
+			basicSecret:       "wrong",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedError:     ErrorInvalidClient,
+			expectedChallenge: basicChallenge,
+		},
+		{
+			name:           "TwoAuthenticationMethods",
+			form:           url.Values{"grant_type": {GrantTypeClientCredentials}, "client_secret": {"s3cret:/"}},
+			basicID:        "backend",
+			basicSecret:    url.QueryEscape("s3cret:/"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  ErrorInvalidRequest,
+		},
+		{
+			name:              "ClientIDMismatch",
+			form:              url.Values{"grant_type": {GrantTypeClientCredentials}, "client_id": {"other"}},
+			basicID:           "backend",
+			basicSecret:       url.QueryEscape("s3cret:/"),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedError:     ErrorInvalidClient,
+			expectedChallenge: basicChallenge,
+		},
+		{
+			name:           "InvalidScope",
+			form:           url.Values{"grant_type": {GrantTypeClientCredentials}, "client_id": {"backend"}, "client_secret": {"s3cret:/"}, "scope": {"admin"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  ErrorInvalidScope,
+		},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+
+				handler, f = testTokenHandler(t)
+				response   = httptest.NewRecorder()
+				request    = testTokenRequest(record.form)
+			)
+
+			if len(record.basicID) > 0 {
+				request.SetBasicAuth(record.basicID, record.basicSecret)
+			}
+
+			handler.ServeHTTP(response, request)
+			assert.Equal(record.expectedStatus, response.Code)
+			assert.Equal("no-store", response.Header().Get("Cache-Control"))
+			assert.Equal(record.expectedChallenge, response.Header().Get("WWW-Authenticate"))
+			assert.Nil(f.request)
+
+			var body map[string]any
+			require.NoError(json.Unmarshal(response.Body.Bytes(), &body))
+			assert.Equal(record.expectedError, body["error"])
+		})
+	}
+}
+
+func testClientCredentialsFactoryError(t *testing.T) {
+	testData := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedError  string
+	}{
+		{"BadRequest", testStatusError(http.StatusBadRequest), http.StatusBadRequest, ErrorInvalidRequest},
+		{"Forbidden", testStatusError(http.StatusForbidden), http.StatusForbidden, ErrorAccessDenied},
+		{"InternalServerError", testStatusError(http.StatusInternalServerError), http.StatusInternalServerError, ErrorServerError},
+		{"NoStatus", errors.New("remote claims failed"), http.StatusInternalServerError, ErrorServerError},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+
+				handler, f = testTokenHandler(t)
+				response   = httptest.NewRecorder()
+				request    = testTokenRequest(url.Values{"grant_type": {GrantTypeClientCredentials}, "client_id": {"backend"}, "client_secret": {"s3cret:/"}})
+			)
+
+			f.err = record.err
+			handler.ServeHTTP(response, request)
+			assert.Equal(record.expectedStatus, response.Code)
+			assert.Equal("no-store", response.Header().Get("Cache-Control"))
+			assert.Equal("application/json; charset=utf-8", response.Header().Get("Content-Type"))
+
+			var body map[string]any
+			require.NoError(json.Unmarshal(response.Body.Bytes(), &body))
+			assert.Equal(record.expectedError, body["error"])
+			if record.expectedError == ErrorServerError {
+				assert.NotContains(body, "error_description")
+			}
+		})
+	}
+}
+
+func TestClientCredentials(t *testing.T) {
+	t.Run("Success", testClientCredentialsSuccess)
+	t.Run("Error", testClientCredentialsError)
+	t.Run("FactoryError", testClientCredentialsFactoryError)
+}
+
+func TestExpiresIn(t *testing.T) {
+	testData := []struct {
+		name     string
+		claims   map[string]any
+		expected int64
+	}{
+		{"NoClaims", nil, 0},
+		{"NoExp", map[string]any{"iat": int64(1700000000)}, 0},
+		{"Duration", map[string]any{"iat": int64(1700000000), "exp": int64(1700003600)}, 3600},
+		{"TrustRule", map[string]any{"iat": int64(1700000000), "exp": int64(1700000300)}, 300},
+		{"Float", map[string]any{"iat": 1700000000.0, "exp": 1700000060.0}, 60},
+		{"Number", map[string]any{"iat": json.Number("1700000000"), "exp": json.Number("1700000060")}, 60},
+		{"Expired", map[string]any{"iat": int64(1700000000), "exp": int64(1600000000)}, 0},
+		{"NoIat", map[string]any{"exp": time.Now().Add(time.Hour).Unix()}, 3600},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			assert.InDelta(t, record.expected, expiresIn(record.claims), 1)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+)
+
+// The error codes defined by RFC 6749 section 5.2
+const (
+	ErrorInvalidRequest       = "invalid_request"
+	ErrorInvalidClient        = "invalid_client"
+	ErrorInvalidGrant         = "invalid_grant"
+	ErrorUnauthorizedClient   = "unauthorized_client"
+	ErrorUnsupportedGrantType = "unsupported_grant_type"
+	ErrorInvalidScope         = "invalid_scope"
+)
+
+// The error codes defined by RFC 6749 section 4.1.2.1, which are also used for token
+// endpoint failures that section 5.2 has no code for
+const (
+	ErrorAccessDenied = "access_denied"
+	ErrorServerError  = "server_error"
+)
+
+// Error is an RFC 6749 error response.  Only the Code and Description are disclosed to clients.
+type Error struct {
+	// Code is the error parameter, e.g. ErrorInvalidRequest
+	Code string
+
+	// Description is the optional, human-readable error_description parameter
+	Description string
+
+	// Challenge is the optional WWW-Authenticate challenge for ErrorInvalidClient responses.
+	// RFC 6749 requires a challenge when the client attempted HTTP Basic authentication.
+	Challenge string
+
+	// Err is the optional underlying cause of this error
+	Err error
+}
+
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+func (e Error) Error() string {
+	switch {
+	case e.Err != nil:
+		return e.Code + ": " + e.Err.Error()
+	case len(e.Description) > 0:
+		return e.Code + ": " + e.Description
+	default:
+		return e.Code
+	}
+}
+
+// StatusCode returns 401 for ErrorInvalidClient, 403 for ErrorAccessDenied, 500 for ErrorServerError,
+// and 400 for every other error
+func (e Error) StatusCode() int {
+	switch e.Code {
+	case ErrorInvalidClient:
+		return http.StatusUnauthorized
+	case ErrorAccessDenied:
+		return http.StatusForbidden
+	case ErrorServerError:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Headers supplies the headers RFC 6749 requires on error responses
+func (e Error) Headers() http.Header {
+	h := http.Header{
+		"Cache-Control": {"no-store"},
+		"Pragma":        {"no-cache"},
+	}
+
+	if len(e.Challenge) > 0 {
+		h.Set("WWW-Authenticate", e.Challenge)
+	}
+
+	return h
+}
+
+// asError converts any error into an Error.  Errors from a token Factory carry an HTTP status
+// code rather than an RFC 6749 error code, so a 400 becomes ErrorInvalidRequest and a 401 or 403
+// becomes ErrorAccessDenied, each described by the error's message.  Anything else becomes an
+// ErrorServerError, which discloses nothing about the failure.
+func asError(err error) Error {
+	var e Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	var sc kithttp.StatusCoder
+	code := http.StatusInternalServerError
+	if errors.As(err, &sc) {
+		code = sc.StatusCode()
+	}
+
+	switch code {
+	case http.StatusBadRequest:
+		return Error{Code: ErrorInvalidRequest, Description: err.Error(), Err: err}
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return Error{Code: ErrorAccessDenied, Description: err.Error(), Err: err}
+	default:
+		return Error{Code: ErrorServerError, Err: err}
+	}
+}
+
+func (e Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Error       string `json:"error"`
+		Description string `json:"error_description,omitempty"`
+	}{
+		Error:       e.Code,
+		Description: e.Description,
+	})
+}
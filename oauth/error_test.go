@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError(t *testing.T) {
+	t.Run("InvalidRequest", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+			e       = Error{Code: ErrorInvalidRequest, Description: "grant_type is required"}
+		)
+
+		assert.Equal("invalid_request: grant_type is required", e.Error())
+		assert.Equal(http.StatusBadRequest, e.StatusCode())
+		assert.Equal("no-store", e.Headers().Get("Cache-Control"))
+		assert.Equal("no-cache", e.Headers().Get("Pragma"))
+		assert.Empty(e.Headers().Get("WWW-Authenticate"))
+
+		data, err := json.Marshal(e)
+		require.NoError(err)
+		assert.JSONEq(`{"error": "invalid_request", "error_description": "grant_type is required"}`, string(data))
+	})
+
+	t.Run("InvalidClient", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+			cause   = errors.New("expected")
+			e       = Error{Code: ErrorInvalidClient, Challenge: basicChallenge, Err: cause}
+		)
+
+		assert.Equal("invalid_client: expected", e.Error())
+		assert.ErrorIs(e, cause)
+		assert.Equal(http.StatusUnauthorized, e.StatusCode())
+		assert.Equal(basicChallenge, e.Headers().Get("WWW-Authenticate"))
+
+		// the underlying cause is never disclosed
+		data, err := json.Marshal(e)
+		require.NoError(err)
+		assert.JSONEq(`{"error": "invalid_client"}`, string(data))
+	})
+}
+
+// testStatusError is an error with an HTTP status code, like those returned by a token Factory
+type testStatusError int
+
+func (e testStatusError) Error() string {
+	return "status " + strconv.Itoa(int(e))
+}
+
+func (e testStatusError) StatusCode() int {
+	return int(e)
+}
+
+func TestAsError(t *testing.T) {
+	testData := []struct {
+		name                string
+		err                 error
+		expectedCode        string
+		expectedStatus      int
+		expectedDescription string
+	}{
+		{
+			name:           "Error",
+			err:            fmt.Errorf("wrapped: %w", Error{Code: ErrorInvalidScope}),
+			expectedCode:   ErrorInvalidScope,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:                "BadRequest",
+			err:                 fmt.Errorf("wrapped: %w", testStatusError(http.StatusBadRequest)),
+			expectedCode:        ErrorInvalidRequest,
+			expectedStatus:      http.StatusBadRequest,
+			expectedDescription: "wrapped: status 400",
+		},
+		{
+			name:                "Unauthorized",
+			err:                 testStatusError(http.StatusUnauthorized),
+			expectedCode:        ErrorAccessDenied,
+			expectedStatus:      http.StatusForbidden,
+			expectedDescription: "status 401",
+		},
+		{
+			name:                "Forbidden",
+			err:                 testStatusError(http.StatusForbidden),
+			expectedCode:        ErrorAccessDenied,
+			expectedStatus:      http.StatusForbidden,
+			expectedDescription: "status 403",
+		},
+		{
+			name:           "InternalServerError",
+			err:            testStatusError(http.StatusInternalServerError),
+			expectedCode:   ErrorServerError,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "NoStatus",
+			err:            errors.New("remote claims failed"),
+			expectedCode:   ErrorServerError,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert = assert.New(t)
+				e      = asError(record.err)
+			)
+
+			assert.Equal(record.expectedCode, e.Code)
+			assert.Equal(record.expectedStatus, e.StatusCode())
+			assert.Equal(record.expectedDescription, e.Description)
+			assert.Equal("no-store", e.Headers().Get("Cache-Control"))
+		})
+	}
+}
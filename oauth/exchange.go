@@ -0,0 +1,452 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/xmidt-org/themis/v2/token"
+)
+
+const (
+	// GrantTypeTokenExchange is the RFC 8693 grant_type
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+	// TokenTypeJWT is the RFC 8693 token type identifier for JWTs.  It is the issued_token_type
+	// of every exchanged token.
+	TokenTypeJWT = "urn:ietf:params:oauth:token-type:jwt"
+
+	// TokenTypeAccessToken is the RFC 8693 token type identifier for OAuth 2.0 access tokens.  Only
+	// access tokens that are JWTs can be exchanged.
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+	// DefaultJWKSRefreshInterval is how often a JWK Set fetched from a URL is refreshed when no
+	// refresh interval is configured
+	DefaultJWKSRefreshInterval = time.Hour
+
+	// DefaultJWKSFetchTimeout bounds each fetch of a JWK Set from a URL when no fetch timeout is configured
+	DefaultJWKSFetchTimeout = 10 * time.Second
+
+	// jwksMinRefetch is the minimum time between fetches of a JWK Set caused by tokens with unknown key ids
+	jwksMinRefetch = time.Minute
+)
+
+var (
+	ErrNoTrustedIssuers    = errors.New("at least one trusted issuer is required for token exchange")
+	ErrNoIssuer            = errors.New("a trusted issuer requires an issuer")
+	ErrDuplicateIssuer     = errors.New("duplicate trusted issuer")
+	ErrNoJWKS              = errors.New("exactly one of jwksFile or jwksURL is required for a trusted issuer")
+	ErrInvalidClaimMapping = errors.New("a claim mapping requires both from and to")
+	ErrUnsupportedAlg      = errors.New("unsupported signing algorithm")
+	ErrUntrustedIssuer     = errors.New("the subject token issuer is not trusted")
+	ErrUnknownSubjectKey   = errors.New("the subject token was not signed by a key of its issuer")
+	ErrNoExpiration        = errors.New("the subject token has no exp claim")
+	ErrAudience            = errors.New("the subject token was not issued for this audience")
+	ErrMissingClaim        = errors.New("the subject token is missing a required claim")
+)
+
+// defaultSubjectAlgs are the asymmetric algorithms accepted for subject tokens when a trusted
+// issuer doesn't configure its own.  Symmetric algorithms are never accepted, since a JWK Set
+// only publishes public keys.
+var defaultSubjectAlgs = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// ClaimMapping copies a claim from a subject token into the exchanged token
+type ClaimMapping struct {
+	// From is the name of the claim in the subject token
+	From string
+
+	// To is the name of the claim in the exchanged token
+	To string
+
+	// Required indicates that subject tokens without the From claim are rejected
+	Required bool
+}
+
+// TrustedIssuer describes an external identity provider whose tokens may be exchanged
+type TrustedIssuer struct {
+	// Issuer is the iss claim of this provider's tokens
+	Issuer string
+
+	// Audience is the optional set of aud values accepted from this provider.  If set, a subject
+	// token must have been issued for at least one of them.
+	Audience []string
+
+	// Algs are the signing algorithms accepted from this provider.  If unset, any asymmetric
+	// algorithm is accepted.
+	Algs []string
+
+	// JWKSFile is the system path of a JWK Set holding this provider's public keys
+	JWKSFile string
+
+	// JWKSURL is the URL of this provider's JWK Set
+	JWKSURL string
+
+	// RefreshInterval is how often the JWK Set at JWKSURL is fetched again.  If unset,
+	// DefaultJWKSRefreshInterval is used.
+	RefreshInterval time.Duration
+
+	// FetchTimeout bounds each fetch of the JWK Set at JWKSURL, regardless of the request that
+	// caused it.  If unset, DefaultJWKSFetchTimeout is used.
+	FetchTimeout time.Duration
+
+	// Claims are the claims copied from this provider's tokens.  No other claims are copied.
+	Claims []ClaimMapping
+}
+
+// Exchange is the configuration for the RFC 8693 token exchange grant
+type Exchange struct {
+	// RequireClient indicates whether token exchange requests must be sent by an authenticated client
+	RequireClient bool
+
+	// Issuers are the trusted identity providers
+	Issuers []TrustedIssuer
+}
+
+// jwks is a JWK Set, either loaded once from a file or periodically fetched from a URL
+type jwks struct {
+	url      string
+	client   jwk.HTTPClient
+	interval time.Duration
+	timeout  time.Duration
+	now      func() time.Time
+
+	lock    sync.Mutex
+	set     jwk.Set
+	fetched time.Time
+
+	// fetching is the fetch in progress, if any.  Concurrent lookups share it rather than
+	// each contacting the provider.
+	fetching *jwksFetch
+}
+
+// jwksFetch is a single fetch of a JWK Set, which is complete when done is closed
+type jwksFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// lookup returns the JWK with the given key id.  A token without a kid may only be verified
+// against a set with exactly one key.
+func (j *jwks) lookup(ctx context.Context, kid string) (jwk.Key, error) {
+	if len(j.url) > 0 {
+		if set, fetched := j.current(); set == nil || j.now().Sub(fetched) >= j.interval {
+			// a previously fetched set continues to be used if the provider is unavailable
+			if err := j.refresh(ctx); err != nil && set == nil {
+				return nil, err
+			}
+		}
+
+		// the provider may have rotated its keys since the last fetch
+		if set, fetched := j.current(); !hasKey(set, kid) && j.now().Sub(fetched) >= jwksMinRefetch {
+			if err := j.refresh(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	set, _ := j.current()
+	if k, ok := findKey(set, kid); ok {
+		return k, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownSubjectKey, kid)
+}
+
+// current returns the JWK Set along with when it was last fetched
+func (j *jwks) current() (jwk.Set, time.Time) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.set, j.fetched
+}
+
+func hasKey(set jwk.Set, kid string) bool {
+	_, ok := findKey(set, kid)
+	return ok
+}
+
+func findKey(set jwk.Set, kid string) (jwk.Key, bool) {
+	switch {
+	case set == nil:
+		return nil, false
+	case len(kid) > 0:
+		return set.LookupKeyID(kid)
+	case set.Len() == 1:
+		return set.Get(0)
+	default:
+		return nil, false
+	}
+}
+
+// refresh fetches the JWK Set from its URL, or waits for a fetch already in progress.  The lock is
+// not held while fetching, so lookups that need no fetch are never blocked by a slow provider.
+// Failed attempts count as fetches, so that an unavailable provider isn't contacted on every request.
+func (j *jwks) refresh(ctx context.Context) error {
+	j.lock.Lock()
+	if f := j.fetching; f != nil {
+		j.lock.Unlock()
+		select {
+		case <-f.done:
+			return f.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	f := &jwksFetch{done: make(chan struct{})}
+	j.fetching = f
+	j.fetched = j.now()
+	j.lock.Unlock()
+
+	// the fetch is shared, so it is bounded by its own timeout rather than by this request
+	fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), j.timeout)
+	defer cancel()
+
+	set, err := jwk.Fetch(fetchCtx, j.url, jwk.WithHTTPClient(j.client))
+	if err != nil {
+		f.err = fmt.Errorf("unable to fetch JWK Set from %s: %w", j.url, err)
+	}
+
+	j.lock.Lock()
+	if err == nil {
+		j.set = set
+	}
+
+	j.fetching = nil
+	close(f.done)
+	j.lock.Unlock()
+	return f.err
+}
+
+type trustedIssuer struct {
+	TrustedIssuer
+	keys *jwks
+}
+
+// TokenExchange is the RFC 8693 token exchange Grant.  A JWT issued by a trusted identity provider
+// is exchanged for a themis token holding the provider's claims, as selected by claim mappings.
+type TokenExchange struct {
+	requireClient bool
+	issuers       map[string]*trustedIssuer
+	parser        *jwt.Parser
+}
+
+// NewTokenExchange creates the token exchange Grant.  JWK Sets in files are loaded immediately,
+// while JWK Sets at URLs are fetched with the given client when first needed.
+func NewTokenExchange(e Exchange, client jwk.HTTPClient) (*TokenExchange, error) {
+	if len(e.Issuers) == 0 {
+		return nil, ErrNoTrustedIssuers
+	}
+
+	te := &TokenExchange{
+		requireClient: e.RequireClient,
+		issuers:       make(map[string]*trustedIssuer, len(e.Issuers)),
+		parser:        &jwt.Parser{UseJSONNumber: true},
+	}
+
+	for _, ti := range e.Issuers {
+		switch {
+		case len(ti.Issuer) == 0:
+			return nil, ErrNoIssuer
+		case te.issuers[ti.Issuer] != nil:
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateIssuer, ti.Issuer)
+		case (len(ti.JWKSFile) > 0) == (len(ti.JWKSURL) > 0):
+			return nil, fmt.Errorf("%w: %s", ErrNoJWKS, ti.Issuer)
+		}
+
+		for _, alg := range ti.Algs {
+			if !slices.Contains(defaultSubjectAlgs, alg) {
+				return nil, fmt.Errorf("%w: %s for issuer %s", ErrUnsupportedAlg, alg, ti.Issuer)
+			}
+		}
+
+		if len(ti.Algs) == 0 {
+			ti.Algs = defaultSubjectAlgs
+		}
+
+		for _, m := range ti.Claims {
+			if len(m.From) == 0 || len(m.To) == 0 {
+				return nil, fmt.Errorf("%w: issuer %s", ErrInvalidClaimMapping, ti.Issuer)
+			}
+		}
+
+		keys := &jwks{
+			url:      ti.JWKSURL,
+			client:   client,
+			interval: ti.RefreshInterval,
+			timeout:  ti.FetchTimeout,
+			now:      time.Now,
+		}
+
+		if keys.interval <= 0 {
+			keys.interval = DefaultJWKSRefreshInterval
+		}
+
+		if keys.timeout <= 0 {
+			keys.timeout = DefaultJWKSFetchTimeout
+		}
+
+		if len(ti.JWKSFile) > 0 {
+			set, err := jwk.ReadFile(ti.JWKSFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read JWK Set for issuer %s: %w", ti.Issuer, err)
+			}
+
+			keys.set = set
+		}
+
+		te.issuers[ti.Issuer] = &trustedIssuer{
+			TrustedIssuer: ti,
+			keys:          keys,
+		}
+	}
+
+	return te, nil
+}
+
+// invalidSubjectToken produces the RFC 8693 error for a subject token that was rejected
+func invalidSubjectToken(description string, err error) error {
+	return Error{Code: ErrorInvalidRequest, Description: description, Err: err}
+}
+
+func (te *TokenExchange) Request(ctx context.Context, tr *TokenRequest) (*token.Request, error) {
+	if te.requireClient && tr.Client == nil {
+		return nil, Error{Code: ErrorInvalidClient, Err: ErrClientRequired}
+	}
+
+	subjectToken := tr.Form.Get("subject_token")
+	if len(subjectToken) == 0 {
+		return nil, Error{Code: ErrorInvalidRequest, Description: "subject_token is required"}
+	}
+
+	switch tr.Form.Get("subject_token_type") {
+	case TokenTypeJWT, TokenTypeAccessToken:
+	case "":
+		return nil, Error{Code: ErrorInvalidRequest, Description: "subject_token_type is required"}
+	default:
+		return nil, Error{Code: ErrorInvalidRequest, Description: "unsupported subject_token_type"}
+	}
+
+	if len(tr.Form.Get("actor_token")) > 0 {
+		return nil, Error{Code: ErrorInvalidRequest, Description: "actor_token is not supported"}
+	}
+
+	if rtt := tr.Form.Get("requested_token_type"); len(rtt) > 0 && rtt != TokenTypeJWT && rtt != TokenTypeAccessToken {
+		return nil, Error{Code: ErrorInvalidRequest, Description: "unsupported requested_token_type"}
+	}
+
+	ti, claims, err := te.verify(ctx, subjectToken)
+	if err != nil {
+		return nil, err
+	}
+
+	r := token.NewRequest()
+	r.TLS = tr.TLS
+	if tr.Client != nil {
+		maps.Copy(r.Claims, tr.Client.claims)
+	}
+
+	for _, m := range ti.Claims {
+		value, ok := claims[m.From]
+		if !ok {
+			if m.Required {
+				return nil, invalidSubjectToken("the subject_token is missing the "+m.From+" claim", fmt.Errorf("%w: %s", ErrMissingClaim, m.From))
+			}
+
+			continue
+		}
+
+		r.Claims[m.To] = value
+	}
+
+	if tr.Client != nil {
+		scope, err := tr.Client.grantScope(tr.Form.Get("scope"))
+		if err != nil {
+			return nil, err
+		}
+
+		r.Claims[ClaimClientID] = tr.Client.ID
+		r.Metadata[ClaimClientID] = tr.Client.ID
+		if len(scope) > 0 {
+			r.Claims[ClaimScope] = scope
+		}
+	} else if len(tr.Form.Get("scope")) > 0 {
+		return nil, Error{Code: ErrorInvalidScope, Description: "scopes can only be granted to clients"}
+	}
+
+	return r, nil
+}
+
+// verify checks a subject token's signature against its issuer's keys, along with its time-based
+// claims and audience
+func (te *TokenExchange) verify(ctx context.Context, subjectToken string) (*trustedIssuer, jwt.MapClaims, error) {
+	var (
+		ti     *trustedIssuer
+		claims = jwt.MapClaims{}
+	)
+
+	_, err := te.parser.ParseWithClaims(subjectToken, claims, func(t *jwt.Token) (any, error) {
+		iss, _ := claims["iss"].(string)
+		if ti = te.issuers[iss]; ti == nil {
+			return nil, fmt.Errorf("%w: %q", ErrUntrustedIssuer, iss)
+		}
+
+		if !slices.Contains(ti.Algs, t.Method.Alg()) {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlg, t.Method.Alg())
+		}
+
+		kid, _ := t.Header["kid"].(string)
+		k, err := ti.keys.lookup(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		// a key that declares its algorithm may only verify tokens signed with it
+		if alg := k.Algorithm(); len(alg) > 0 && alg != t.Method.Alg() {
+			return nil, fmt.Errorf("%w: key %q requires %s", ErrUnsupportedAlg, kid, alg)
+		}
+
+		var raw any
+		if err := k.Raw(&raw); err != nil {
+			return nil, err
+		}
+
+		return raw, nil
+	})
+
+	if err != nil {
+		// jwt.ValidationError does not support errors.Is, so its inner error is examined directly
+		var ve *jwt.ValidationError
+		if errors.As(err, &ve) && ve.Inner != nil {
+			err = ve.Inner
+		}
+
+		if errors.Is(err, ErrUntrustedIssuer) {
+			return nil, nil, invalidSubjectToken("the subject_token issuer is not trusted", err)
+		}
+
+		return nil, nil, invalidSubjectToken("the subject_token is not valid", err)
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return nil, nil, invalidSubjectToken("the subject_token has no expiration", ErrNoExpiration)
+	}
+
+	if len(ti.Audience) > 0 && !slices.ContainsFunc(ti.Audience, func(aud string) bool { return claims.VerifyAudience(aud, true) }) {
+		return nil, nil, invalidSubjectToken("the subject_token was not issued for this audience", ErrAudience)
+	}
+
+	return ti, claims, nil
+}
@@ -0,0 +1,485 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIssuer = "https://idp.example.com"
+
+// testSubjectKey is an external identity provider's signing key
+type testSubjectKey struct {
+	kid     string
+	private *ecdsa.PrivateKey
+}
+
+func newTestSubjectKey(t *testing.T, kid string) testSubjectKey {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return testSubjectKey{kid: kid, private: private}
+}
+
+func (k testSubjectKey) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = k.kid
+	value, err := token.SignedString(k.private)
+	require.NoError(t, err)
+	return value
+}
+
+// testJWKS marshals the public portions of keys as a JWK Set
+func testJWKS(t *testing.T, keys ...testSubjectKey) []byte {
+	set := jwk.NewSet()
+	for _, k := range keys {
+		public, err := jwk.New(crypto.PublicKey(&k.private.PublicKey))
+		require.NoError(t, err)
+		require.NoError(t, public.Set(jwk.KeyIDKey, k.kid))
+		set.Add(public)
+	}
+
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	return data
+}
+
+func testJWKSFile(t *testing.T, keys ...testSubjectKey) string {
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(file, testJWKS(t, keys...), 0600))
+	return file
+}
+
+func testSubjectClaims(overrides jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss":     testIssuer,
+		"sub":     "user@example.com",
+		"aud":     "themis",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+		"iat":     time.Now().Unix(),
+		"partner": "comcast",
+	}
+
+	for k, v := range overrides {
+		if v == nil {
+			delete(claims, k)
+		} else {
+			claims[k] = v
+		}
+	}
+
+	return claims
+}
+
+func testExchangeForm(subjectToken string) url.Values {
+	return url.Values{
+		"grant_type":         {GrantTypeTokenExchange},
+		"subject_token":      {subjectToken},
+		"subject_token_type": {TokenTypeJWT},
+	}
+}
+
+func testTokenExchangeSuccess(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		signingKey = newTestSubjectKey(t, "idp-1")
+		f          = new(testFactory)
+	)
+
+	cs, err := NewClients([]Client{
+		{ID: "backend", SecretHash: testSecretHash(t, "secret"), Scopes: []string{"read"}},
+	})
+
+	require.NoError(err)
+	te, err := NewTokenExchange(
+		Exchange{
+			Issuers: []TrustedIssuer{
+				{
+					Issuer:   testIssuer,
+					Audience: []string{"other", "themis"},
+					JWKSFile: testJWKSFile(t, newTestSubjectKey(t, "idp-0"), signingKey),
+					Claims: []ClaimMapping{
+						{From: "sub", To: "sub", Required: true},
+						{From: "partner", To: "partner-id"},
+						{From: "missing", To: "optional"},
+					},
+				},
+			},
+		},
+		http.DefaultClient,
+	)
+
+	require.NoError(err)
+	handler := NewTokenHandler(NewTokenEndpoint(f, cs, Grants{GrantTypeTokenExchange: te}))
+
+	t.Run("NoClient", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, testTokenRequest(testExchangeForm(signingKey.sign(t, testSubjectClaims(nil)))))
+		require.Equal(http.StatusOK, response.Code)
+
+		var tr TokenResponse
+		require.NoError(json.Unmarshal(response.Body.Bytes(), &tr))
+		assert.Equal(TokenResponse{AccessToken: "issued", IssuedTokenType: TokenTypeJWT, TokenType: TokenTypeBearer, ExpiresIn: 3600}, tr)
+
+		require.NotNil(f.request)
+		assert.Equal("user@example.com", f.request.Claims["sub"])
+		assert.Equal("comcast", f.request.Claims["partner-id"])
+		assert.NotContains(f.request.Claims, "optional")
+		assert.NotContains(f.request.Claims, "iss")
+		assert.NotContains(f.request.Claims, ClaimClientID)
+	})
+
+	t.Run("Client", func(t *testing.T) {
+		form := testExchangeForm(signingKey.sign(t, testSubjectClaims(jwt.MapClaims{"aud": []string{"themis"}})))
+		form.Set("subject_token_type", TokenTypeAccessToken)
+		form.Set("client_id", "backend")
+		form.Set("client_secret", "secret")
+
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, testTokenRequest(form))
+		require.Equal(http.StatusOK, response.Code)
+
+		var tr TokenResponse
+		require.NoError(json.Unmarshal(response.Body.Bytes(), &tr))
+		assert.Equal("read", tr.Scope)
+		assert.Equal("user@example.com", f.request.Claims["sub"])
+		assert.Equal("backend", f.request.Claims[ClaimClientID])
+	})
+}
+
+func testTokenExchangeRejected(t *testing.T) {
+	var (
+		signingKey = newTestSubjectKey(t, "idp-1")
+		otherKey   = newTestSubjectKey(t, "idp-1")
+		jwksFile   = testJWKSFile(t, signingKey)
+	)
+
+	testData := []struct {
+		name          string
+		exchange      Exchange
+		form          url.Values
+		expectedError string
+		expectedErr   error
+	}{
+		{
+			name:          "NoSubjectToken",
+			form:          url.Values{"subject_token_type": {TokenTypeJWT}},
+			expectedError: ErrorInvalidRequest,
+		},
+		{
+			name:          "NoSubjectTokenType",
+			form:          url.Values{"subject_token": {signingKey.sign(t, testSubjectClaims(nil))}},
+			expectedError: ErrorInvalidRequest,
+		},
+		{
+			name:          "UnsupportedSubjectTokenType",
+			form:          url.Values{"subject_token": {signingKey.sign(t, testSubjectClaims(nil))}, "subject_token_type": {"urn:ietf:params:oauth:token-type:saml2"}},
+			expectedError: ErrorInvalidRequest,
+		},
+		{
+			name:          "UntrustedIssuer",
+			form:          testExchangeForm(signingKey.sign(t, testSubjectClaims(jwt.MapClaims{"iss": "https://evil.example.com"}))),
+			expectedError: ErrorInvalidRequest,
+			expectedErr:   ErrUntrustedIssuer,
+		},
+		{
+			name:          "BadSignature",
+			form:          testExchangeForm(otherKey.sign(t, testSubjectClaims(nil))),
+			expectedError: ErrorInvalidRequest,
+		},
+		{
+			name:          "Expired",
+			form:          testExchangeForm(signingKey.sign(t, testSubjectClaims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}))),
+			expectedError: ErrorInvalidRequest,
+		},
+		{
+			name:          "NoExpiration",
+			form:          testExchangeForm(signingKey.sign(t, testSubjectClaims(jwt.MapClaims{"exp": nil}))),
+			expectedError: ErrorInvalidRequest,
+			expectedErr:   ErrNoExpiration,
+		},
+		{
+			name:          "WrongAudience",
+			form:          testExchangeForm(signingKey.sign(t, testSubjectClaims(jwt.MapClaims{"aud": "elsewhere"}))),
+			expectedError: ErrorInvalidRequest,
+			expectedErr:   ErrAudience,
+		},
+		{
+			name:          "MissingRequiredClaim",
+			form:          testExchangeForm(signingKey.sign(t, testSubjectClaims(jwt.MapClaims{"partner": nil}))),
+			expectedError: ErrorInvalidRequest,
+			expectedErr:   ErrMissingClaim,
+		},
+		{
+			name: "AlgNotAllowed",
+			exchange: Exchange{
+				Issuers: []TrustedIssuer{{Issuer: testIssuer, JWKSFile: jwksFile, Algs: []string{"RS256"}}},
+			},
+			form:          testExchangeForm(signingKey.sign(t, testSubjectClaims(nil))),
+			expectedError: ErrorInvalidRequest,
+		},
+		{
+			name: "ClientRequired",
+			exchange: Exchange{
+				RequireClient: true,
+				Issuers:       []TrustedIssuer{{Issuer: testIssuer, JWKSFile: jwksFile}},
+			},
+			form:          testExchangeForm(signingKey.sign(t, testSubjectClaims(nil))),
+			expectedError: ErrorInvalidClient,
+			expectedErr:   ErrClientRequired,
+		},
+		{
+			name: "ScopeWithoutClient",
+			exchange: Exchange{
+				Issuers: []TrustedIssuer{{Issuer: testIssuer, JWKSFile: jwksFile}},
+			},
+			form: url.Values{
+				"subject_token":      {signingKey.sign(t, testSubjectClaims(nil))},
+				"subject_token_type": {TokenTypeJWT},
+				"scope":              {"read"},
+			},
+			expectedError: ErrorInvalidScope,
+		},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+			)
+
+			if len(record.exchange.Issuers) == 0 {
+				record.exchange.Issuers = []TrustedIssuer{
+					{
+						Issuer:   testIssuer,
+						Audience: []string{"themis"},
+						JWKSFile: jwksFile,
+						Claims:   []ClaimMapping{{From: "partner", To: "partner-id", Required: true}},
+					},
+				}
+			}
+
+			te, err := NewTokenExchange(record.exchange, http.DefaultClient)
+			require.NoError(err)
+
+			r, err := te.Request(context.Background(), &TokenRequest{GrantType: GrantTypeTokenExchange, Form: record.form})
+			assert.Nil(r)
+
+			var e Error
+			require.ErrorAs(err, &e)
+			assert.Equal(record.expectedError, e.Code)
+			if record.expectedErr != nil {
+				assert.ErrorIs(err, record.expectedErr)
+			}
+		})
+	}
+}
+
+func testTokenExchangeJWKSURL(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		initial = newTestSubjectKey(t, "idp-1")
+		rotated = newTestSubjectKey(t, "idp-2")
+
+		fetches atomic.Int32
+		current atomic.Value
+	)
+
+	current.Store(testJWKS(t, initial))
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		response.Header().Set("Content-Type", "application/json")
+		response.Write(current.Load().([]byte))
+	}))
+
+	defer server.Close()
+	te, err := NewTokenExchange(
+		Exchange{Issuers: []TrustedIssuer{{Issuer: testIssuer, JWKSURL: server.URL}}},
+		server.Client(),
+	)
+
+	require.NoError(err)
+	now := time.Now()
+	te.issuers[testIssuer].keys.now = func() time.Time { return now }
+
+	// the JWK Set is fetched on first use, then cached
+	for range 2 {
+		_, err = te.Request(context.Background(), &TokenRequest{Form: testExchangeForm(initial.sign(t, testSubjectClaims(nil)))})
+		require.NoError(err)
+	}
+
+	assert.Equal(int32(1), fetches.Load())
+
+	// an unknown kid doesn't trigger a fetch until jwksMinRefetch has elapsed
+	current.Store(testJWKS(t, initial, rotated))
+	_, err = te.Request(context.Background(), &TokenRequest{Form: testExchangeForm(rotated.sign(t, testSubjectClaims(nil)))})
+	assert.ErrorIs(err, ErrUnknownSubjectKey)
+	assert.Equal(int32(1), fetches.Load())
+
+	now = now.Add(jwksMinRefetch)
+	_, err = te.Request(context.Background(), &TokenRequest{Form: testExchangeForm(rotated.sign(t, testSubjectClaims(nil)))})
+	require.NoError(err)
+	assert.Equal(int32(2), fetches.Load())
+}
+
+func testTokenExchangeJWKSFetch(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		initial = newTestSubjectKey(t, "idp-1")
+
+		fetches atomic.Int32
+		blocked atomic.Bool
+		release = make(chan struct{})
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		fetches.Add(1)
+		if blocked.Load() {
+			select {
+			case <-release:
+			case <-request.Context().Done():
+				return
+			}
+		}
+
+		response.Header().Set("Content-Type", "application/json")
+		response.Write(testJWKS(t, initial))
+	}))
+
+	defer server.Close()
+	defer close(release)
+	te, err := NewTokenExchange(
+		Exchange{Issuers: []TrustedIssuer{{Issuer: testIssuer, JWKSURL: server.URL}}},
+		server.Client(),
+	)
+
+	require.NoError(err)
+	keys := te.issuers[testIssuer].keys
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+
+	_, err = keys.lookup(context.Background(), "idp-1")
+	require.NoError(err)
+	assert.Equal(int32(1), fetches.Load())
+
+	// concurrent lookups of an unknown kid share a single fetch
+	blocked.Store(true)
+	now = now.Add(jwksMinRefetch)
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, 3)
+	)
+
+	for range 3 {
+		wg.Go(func() {
+			_, err := keys.lookup(context.Background(), "idp-2")
+			errs <- err
+		})
+	}
+
+	require.Eventually(func() bool { return fetches.Load() == 2 }, time.Second, time.Millisecond)
+
+	// the fetch in progress doesn't block lookups of known keys
+	k, err := keys.lookup(context.Background(), "idp-1")
+	require.NoError(err)
+	assert.Equal("idp-1", k.KeyID())
+
+	release <- struct{}{}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.ErrorIs(err, ErrUnknownSubjectKey)
+	}
+
+	assert.Equal(int32(2), fetches.Load())
+}
+
+func testTokenExchangeJWKSFetchTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+		<-request.Context().Done()
+	}))
+
+	defer server.Close()
+	te, err := NewTokenExchange(
+		Exchange{Issuers: []TrustedIssuer{{Issuer: testIssuer, JWKSURL: server.URL, FetchTimeout: 50 * time.Millisecond}}},
+		server.Client(),
+	)
+
+	require.NoError(t, err)
+
+	// the fetch is bounded even though the request's context never ends
+	start := time.Now()
+	k, err := te.issuers[testIssuer].keys.lookup(context.Background(), "idp-1")
+	assert.Nil(t, k)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func testNewTokenExchangeInvalid(t *testing.T) {
+	jwksFile := testJWKSFile(t, newTestSubjectKey(t, "idp-1"))
+
+	testData := []struct {
+		name     string
+		exchange Exchange
+		expected error
+	}{
+		{"NoIssuers", Exchange{}, ErrNoTrustedIssuers},
+		{"NoIssuer", Exchange{Issuers: []TrustedIssuer{{JWKSFile: jwksFile}}}, ErrNoIssuer},
+		{"Duplicate", Exchange{Issuers: []TrustedIssuer{{Issuer: testIssuer, JWKSFile: jwksFile}, {Issuer: testIssuer, JWKSFile: jwksFile}}}, ErrDuplicateIssuer},
+		{"NoJWKS", Exchange{Issuers: []TrustedIssuer{{Issuer: testIssuer}}}, ErrNoJWKS},
+		{"TwoJWKS", Exchange{Issuers: []TrustedIssuer{{Issuer: testIssuer, JWKSFile: jwksFile, JWKSURL: "https://idp.example.com/jwks"}}}, ErrNoJWKS},
+		{"SymmetricAlg", Exchange{Issuers: []TrustedIssuer{{Issuer: testIssuer, JWKSFile: jwksFile, Algs: []string{"HS256"}}}}, ErrUnsupportedAlg},
+		{"InvalidMapping", Exchange{Issuers: []TrustedIssuer{{Issuer: testIssuer, JWKSFile: jwksFile, Claims: []ClaimMapping{{From: "sub"}}}}}, ErrInvalidClaimMapping},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			te, err := NewTokenExchange(record.exchange, http.DefaultClient)
+			assert.Nil(t, te)
+			assert.ErrorIs(t, err, record.expected)
+		})
+	}
+
+	t.Run("MissingFile", func(t *testing.T) {
+		te, err := NewTokenExchange(
+			Exchange{Issuers: []TrustedIssuer{{Issuer: testIssuer, JWKSFile: filepath.Join(t.TempDir(), "missing.json")}}},
+			http.DefaultClient,
+		)
+
+		assert.Nil(t, te)
+		assert.Error(t, err)
+	})
+}
+
+func TestTokenExchange(t *testing.T) {
+	t.Run("Success", testTokenExchangeSuccess)
+	t.Run("Rejected", testTokenExchangeRejected)
+	t.Run("JWKSURL", testTokenExchangeJWKSURL)
+	t.Run("JWKSFetch", testTokenExchangeJWKSFetch)
+	t.Run("JWKSFetchTimeout", testTokenExchangeJWKSFetchTimeout)
+	t.Run("Invalid", testNewTokenExchangeInvalid)
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package oauth
+
+import (
+	"net/http"
+
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/xmidt-org/themis/v2/config"
+	"github.com/xmidt-org/themis/v2/token"
+	"github.com/xmidt-org/themis/v2/xhttp/xhttpclient"
+	"go.uber.org/fx"
+)
+
+// Options is the configuration for the OAuth 2.0 token endpoint
+type Options struct {
+	// Clients is the registry of clients permitted to use the token endpoint
+	Clients []Client
+
+	// Exchange enables the RFC 8693 token exchange grant, if set
+	Exchange *Exchange
+}
+
+// OAuthIn is the set of dependencies for this package's components
+type OAuthIn struct {
+	fx.In
+
+	Unmarshaller config.Unmarshaller
+
+	// Factory creates the tokens issued by the token endpoint
+	Factory token.Factory
+
+	// Client is the optional HTTP client used to fetch the JWK Sets of trusted issuers
+	Client xhttpclient.Interface `optional:"true"`
+}
+
+// OAuthOut is the set of components emitted by this package.  If the token endpoint is not
+// configured, every component is nil.
+type OAuthOut struct {
+	fx.Out
+
+	// Clients is the registry of OAuth 2.0 clients
+	Clients *Clients
+
+	// TokenHandler is the http.Handler for the token endpoint
+	TokenHandler TokenHandler
+}
+
+// Unmarshal returns an uber/fx style provider that creates this package's components from the
+// Options at the given configuration key.  If the key is not set, the token endpoint is disabled.
+func Unmarshal(configKey string) func(OAuthIn) (OAuthOut, error) {
+	return func(in OAuthIn) (OAuthOut, error) {
+		if !in.Unmarshaller.IsSet(configKey) {
+			return OAuthOut{}, nil
+		}
+
+		var o Options
+		if err := in.Unmarshaller.UnmarshalKey(configKey, &o); err != nil {
+			return OAuthOut{}, err
+		}
+
+		cs, err := NewClients(o.Clients)
+		if err != nil {
+			return OAuthOut{}, err
+		}
+
+		grants := Grants{
+			GrantTypeClientCredentials: GrantFunc(ClientCredentials),
+		}
+
+		if o.Exchange != nil {
+			var client jwk.HTTPClient = http.DefaultClient
+			if in.Client != nil {
+				client = in.Client
+			}
+
+			te, err := NewTokenExchange(*o.Exchange, client)
+			if err != nil {
+				return OAuthOut{}, err
+			}
+
+			grants[GrantTypeTokenExchange] = te
+		}
+
+		return OAuthOut{
+			Clients:      cs,
+			TokenHandler: NewTokenHandler(NewTokenEndpoint(in.Factory, cs, grants)),
+		}, nil
+	}
+}
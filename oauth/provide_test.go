@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package oauth
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/config"
+)
+
+func testUnmarshal(t *testing.T, configuration string) (OAuthOut, error) {
+	v := viper.New()
+	require.NoError(t, config.Json(configuration)(config.ViperIn{}, v))
+	return Unmarshal("oauth")(OAuthIn{
+		Unmarshaller: config.ViperUnmarshaller{Viper: v},
+		Factory:      new(testFactory),
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("NotSet", func(t *testing.T) {
+		out, err := testUnmarshal(t, `{}`)
+		assert.NoError(t, err)
+		assert.Nil(t, out.Clients)
+		assert.Nil(t, out.TokenHandler)
+	})
+
+	t.Run("Clients", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+		)
+
+		out, err := testUnmarshal(t, `{"oauth": {"clients": [{"id": "backend", "certificateSubject": "CN=backend", "scopes": ["read"]}]}}`)
+		require.NoError(err)
+		require.NotNil(out.Clients)
+		assert.NotNil(out.TokenHandler)
+		assert.Equal([]string{"read"}, out.Clients.clients["backend"].Scopes)
+	})
+
+	t.Run("Exchange", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			require  = require.New(t)
+			jwksFile = testJWKSFile(t, newTestSubjectKey(t, "idp-1"))
+		)
+
+		out, err := testUnmarshal(t, `{"oauth": {"exchange": {"issuers": [{"issuer": "`+testIssuer+`", "jwksFile": "`+jwksFile+`"}]}}}`)
+		require.NoError(err)
+		assert.NotNil(out.Clients)
+		assert.NotNil(out.TokenHandler)
+	})
+
+	t.Run("InvalidExchange", func(t *testing.T) {
+		out, err := testUnmarshal(t, `{"oauth": {"exchange": {"requireClient": true}}}`)
+		assert.ErrorIs(t, err, ErrNoTrustedIssuers)
+		assert.Nil(t, out.TokenHandler)
+	})
+
+	t.Run("InvalidClients", func(t *testing.T) {
+		out, err := testUnmarshal(t, `{"oauth": {"clients": [{"id": "backend"}]}}`)
+		assert.ErrorIs(t, err, ErrClientAuthentication)
+		assert.Nil(t, out.Clients)
+		assert.Nil(t, out.TokenHandler)
+	})
+}
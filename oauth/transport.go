@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/xmidt-org/sallust"
+	"github.com/xmidt-org/themis/v2/token"
+	"go.uber.org/zap"
+)
+
+// DecodeTokenRequest decodes an RFC 6749 token request, which is a form post.  Client credentials
+// are accepted either with HTTP Basic authentication or in the form, but not both.
+func DecodeTokenRequest(_ context.Context, request *http.Request) (any, error) {
+	if err := request.ParseForm(); err != nil {
+		return nil, Error{Code: ErrorInvalidRequest, Description: "the request body is not a valid form", Err: err}
+	}
+
+	tr := &TokenRequest{
+		GrantType:    request.PostForm.Get("grant_type"),
+		Form:         request.PostForm,
+		ClientID:     request.PostForm.Get("client_id"),
+		ClientSecret: request.PostForm.Get("client_secret"),
+		TLS:          request.TLS,
+	}
+
+	if len(tr.GrantType) == 0 {
+		return nil, Error{Code: ErrorInvalidRequest, Description: "grant_type is required"}
+	}
+
+	if id, secret, ok := request.BasicAuth(); ok {
+		if len(tr.ClientSecret) > 0 {
+			return nil, Error{Code: ErrorInvalidRequest, Description: "only one client authentication method may be used"}
+		}
+
+		// RFC 6749 section 2.3.1 form-encodes the credentials before they are base64 encoded
+		var idErr, secretErr error
+		id, idErr = url.QueryUnescape(id)
+		secret, secretErr = url.QueryUnescape(secret)
+		if idErr != nil || secretErr != nil || (len(tr.ClientID) > 0 && tr.ClientID != id) {
+			return nil, Error{Code: ErrorInvalidClient, Challenge: basicChallenge, Description: "invalid client credentials"}
+		}
+
+		tr.ClientID = id
+		tr.ClientSecret = secret
+		tr.Basic = true
+	}
+
+	return tr, nil
+}
+
+// EncodeTokenResponse writes a TokenResponse, which must never be cached
+func EncodeTokenResponse(ctx context.Context, response http.ResponseWriter, value any) error {
+	response.Header().Set("Cache-Control", "no-store")
+	response.Header().Set("Pragma", "no-cache")
+	return kithttp.EncodeJSONResponse(ctx, response, value)
+}
+
+// EncodeTokenError writes any error as an RFC 6749 error response.  Errors that are not already
+// an Error, such as those from the token Factory, are mapped onto the closest RFC 6749 error code.
+// The causes of server errors are logged, since they are not disclosed to clients.
+func EncodeTokenError(ctx context.Context, err error, response http.ResponseWriter) {
+	e := asError(err)
+	if e.Code == ErrorServerError {
+		sallust.Get(ctx).Error("unable to issue a token", zap.Error(err))
+	}
+
+	kithttp.DefaultErrorEncoder(ctx, e, response)
+}
+
+type TokenHandler http.Handler
+
+func NewTokenHandler(e endpoint.Endpoint) TokenHandler {
+	return kithttp.NewServer(
+		e,
+		DecodeTokenRequest,
+		EncodeTokenResponse,
+		kithttp.ServerErrorEncoder(EncodeTokenError),
+		kithttp.ServerBefore(func(ctx context.Context, r *http.Request) context.Context {
+			return token.WithTracingHeaders(ctx, r)
+		}),
+	)
+}
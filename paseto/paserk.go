@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package paseto
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// PrefixPublicKey is the prefix of a PASERK holding a v4 public key
+	PrefixPublicKey = "k4.public."
+
+	// PrefixPublicKeyID is the prefix of a PASERK identifying a v4 public key
+	PrefixPublicKeyID = "k4.pid."
+
+	// publicKeyIDSize is the size of the BLAKE2b digest in a public key id, which is 264 bits
+	publicKeyIDSize = 33
+)
+
+var (
+	ErrMalformedPASERK = errors.New("malformed PASERK")
+)
+
+// PublicKey produces the k4.public PASERK serialization of an Ed25519 public key
+func PublicKey(public ed25519.PublicKey) string {
+	return PrefixPublicKey + base64.RawURLEncoding.EncodeToString(public)
+}
+
+// PublicKeyID produces the k4.pid PASERK that identifies an Ed25519 public key.  This is the
+// value of the kid footer claim in tokens signed by the key.
+func PublicKeyID(public ed25519.PublicKey) string {
+	// the digest size is valid and there is no key, so New cannot fail
+	h, _ := blake2b.New(publicKeyIDSize, nil)
+	h.Write([]byte(PrefixPublicKeyID))
+	h.Write([]byte(PublicKey(public)))
+	return PrefixPublicKeyID + base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// ParsePublicKey parses the k4.public PASERK serialization of an Ed25519 public key
+func ParsePublicKey(paserk string) (ed25519.PublicKey, error) {
+	encoded, ok := strings.CutPrefix(paserk, PrefixPublicKey)
+	if !ok {
+		return nil, ErrMalformedPASERK
+	}
+
+	public, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(public) != ed25519.PublicKeySize {
+		return nil, ErrMalformedPASERK
+	}
+
+	return ed25519.PublicKey(public), nil
+}
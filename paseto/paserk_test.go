@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package paseto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicKey(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+	)
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+
+	paserk := PublicKey(public)
+	assert.True(strings.HasPrefix(paserk, PrefixPublicKey))
+
+	parsed, err := ParsePublicKey(paserk)
+	require.NoError(err)
+	assert.Equal(public, parsed)
+
+	for _, invalid := range []string{
+		"",
+		"k3.public." + base64.RawURLEncoding.EncodeToString(public),
+		PrefixPublicKey + "!!!",
+		PrefixPublicKey + base64.RawURLEncoding.EncodeToString(public[:16]),
+	} {
+		parsed, err := ParsePublicKey(invalid)
+		assert.Nil(parsed)
+		assert.ErrorIs(err, ErrMalformedPASERK)
+	}
+}
+
+func TestPublicKeyID(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+	)
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+
+	id := PublicKeyID(public)
+	assert.True(strings.HasPrefix(id, PrefixPublicKeyID))
+	digest, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(id, PrefixPublicKeyID))
+	require.NoError(err)
+	assert.Len(digest, publicKeyIDSize)
+
+	assert.Equal(id, PublicKeyID(public))
+	assert.NotEqual(id, PublicKeyID(other))
+}
@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package paseto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPAE(t *testing.T) {
+	// the expected encodings are from the PASETO specification
+	assert.Equal(t, "0000000000000000", hex.EncodeToString(PAE()))
+	assert.Equal(t, "01000000000000000000000000000000", hex.EncodeToString(PAE([]byte{})))
+	assert.Equal(t, "0100000000000000040000000000000074657374", hex.EncodeToString(PAE([]byte("test"))))
+}
+
+// testSign signs with an Ed25519 private key
+func testSign(private ed25519.PrivateKey) func([]byte) ([]byte, error) {
+	return func(input []byte) ([]byte, error) {
+		return ed25519.Sign(private, input), nil
+	}
+}
+
+func testSignV4Vector(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+	)
+
+	// test vector 4-S-1 from the PASETO specification
+	seed, err := hex.DecodeString("b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a3774")
+	require.NoError(err)
+	private := ed25519.NewKeyFromSeed(seed)
+	assert.Equal("1eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2", hex.EncodeToString(private.Public().(ed25519.PublicKey)))
+
+	message := []byte(`{"data":"this is a signed message","exp":"2022-01-01T00:00:00+00:00"}`)
+	token, err := SignV4(message, nil, testSign(private))
+	require.NoError(err)
+	assert.Equal(
+		"v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA",
+		token,
+	)
+}
+
+func testSignV4Footer(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+	)
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+
+	token, err := SignV4([]byte(`{"sub":"test"}`), []byte(`{"kid":"test"}`), testSign(private))
+	require.NoError(err)
+	assert.True(strings.HasPrefix(token, HeaderV4Public))
+	assert.Equal(3, strings.Count(token, "."))
+
+	message, footer, err := VerifyV4(token, public)
+	require.NoError(err)
+	assert.Equal(`{"sub":"test"}`, string(message))
+	assert.Equal(`{"kid":"test"}`, string(footer))
+}
+
+func testSignV4Error(t *testing.T) {
+	expected := errors.New("expected")
+	token, err := SignV4([]byte("{}"), nil, func([]byte) ([]byte, error) { return nil, expected })
+	assert.Empty(t, token)
+	assert.ErrorIs(t, err, expected)
+}
+
+func TestSignV4(t *testing.T) {
+	t.Run("Vector", testSignV4Vector)
+	t.Run("Footer", testSignV4Footer)
+	t.Run("Error", testSignV4Error)
+}
+
+func TestVerifyV4(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	token, err := SignV4([]byte(`{"sub":"test"}`), []byte("footer"), testSign(private))
+	require.NoError(t, err)
+	body, _, _ := strings.Cut(strings.TrimPrefix(token, HeaderV4Public), ".")
+
+	testData := []struct {
+		name     string
+		token    string
+		public   ed25519.PublicKey
+		expected error
+	}{
+		{"WrongVersion", "v3.public." + body, public, ErrMalformed},
+		{"NotBase64", HeaderV4Public + "!!!", public, ErrMalformed},
+		{"FooterNotBase64", HeaderV4Public + body + ".!!!", public, ErrMalformed},
+		{"TooShort", HeaderV4Public + "AAAA", public, ErrMalformed},
+		{"NoFooter", HeaderV4Public + body, public, ErrInvalidSignature},
+		{"OtherFooter", HeaderV4Public + body + ".b3RoZXI", public, ErrInvalidSignature},
+		{"OtherKey", token, other, ErrInvalidSignature},
+		{"NoKey", token, nil, ErrInvalidSignature},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			message, actualFooter, err := VerifyV4(record.token, record.public)
+			assert.Empty(t, message)
+			assert.Empty(t, actualFooter)
+			assert.ErrorIs(t, err, record.expected)
+		})
+	}
+}
@@ -0,0 +1,239 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package revoke
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"github.com/xmidt-org/sallust"
+	"github.com/xmidt-org/themis/v2/xhttp"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultFeedMaxAge is the default Cache-Control max-age for the revocation feed
+	DefaultFeedMaxAge = time.Minute
+)
+
+var (
+	ErrNoIDVariable = errors.New("no id variable found in the URI")
+)
+
+// InvalidRevocationError indicates that a revocation request was malformed
+type InvalidRevocationError struct {
+	Err error
+}
+
+func (ire InvalidRevocationError) Unwrap() error {
+	return ire.Err
+}
+
+func (ire InvalidRevocationError) Error() string {
+	return fmt.Sprintf("Invalid revocation: %s", ire.Err)
+}
+
+func (ire InvalidRevocationError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// RevocationNotFoundError indicates that no revocation exists with a given ID
+type RevocationNotFoundError struct {
+	ID string
+}
+
+func (rnfe RevocationNotFoundError) Error() string {
+	return fmt.Sprintf("No revocation exists with id %s", rnfe.ID)
+}
+
+func (rnfe RevocationNotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
+
+// Feed is the published form of a List.  Downstream verifiers apply each revocation to the
+// claim given for its type in Claims.
+type Feed struct {
+	Claims      map[string]string `json:"claims"`
+	Revocations []FeedRevocation  `json:"revocations"`
+}
+
+// FeedRevocation is the published form of a Revocation, which carries only what verifiers need
+// to enforce it.  The Created time and the Reason are left out, since the feed is served to anyone
+// who can reach the keys server.
+type FeedRevocation struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Value     string    `json:"value"`
+	Effective time.Time `json:"effective"`
+}
+
+// Listing is the administrative form of a List, with every field of each Revocation
+type Listing struct {
+	Claims      map[string]string `json:"claims"`
+	Revocations []Revocation      `json:"revocations"`
+}
+
+// NewRevokeEndpoint returns a go-kit endpoint that adds a Revocation to a List
+func NewRevokeEndpoint(l *List) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		r, err := l.Revoke(request.(Revocation))
+		if err != nil {
+			if errors.Is(err, ErrInvalidType) || errors.Is(err, ErrNoValue) {
+				err = InvalidRevocationError{Err: err}
+			}
+
+			return nil, err
+		}
+
+		sallust.Get(ctx).Info("token revocation added",
+			zap.String("id", r.ID),
+			zap.String("type", r.Type),
+			zap.Time("effective", r.Effective),
+			zap.String("reason", r.Reason),
+		)
+
+		return r, nil
+	}
+}
+
+// NewRemoveEndpoint returns a go-kit endpoint that removes a Revocation, by ID, from a List
+func NewRemoveEndpoint(l *List) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		id := request.(string)
+		removed, err := l.Remove(id)
+		if err != nil {
+			return nil, err
+		} else if !removed {
+			return nil, RevocationNotFoundError{ID: id}
+		}
+
+		sallust.Get(ctx).Info("token revocation removed", zap.String("id", id))
+		return nil, nil
+	}
+}
+
+// NewFeedEndpoint returns a go-kit endpoint that produces the Feed for a List
+func NewFeedEndpoint(l *List) endpoint.Endpoint {
+	return func(context.Context, any) (any, error) {
+		var (
+			revocations = l.Revocations()
+			feed        = Feed{
+				Claims:      l.Claims(),
+				Revocations: make([]FeedRevocation, 0, len(revocations)),
+			}
+		)
+
+		for _, r := range revocations {
+			feed.Revocations = append(feed.Revocations, FeedRevocation{
+				ID:        r.ID,
+				Type:      r.Type,
+				Value:     r.Value,
+				Effective: r.Effective,
+			})
+		}
+
+		return feed, nil
+	}
+}
+
+// NewListEndpoint returns a go-kit endpoint that produces the Listing for a List
+func NewListEndpoint(l *List) endpoint.Endpoint {
+	return func(context.Context, any) (any, error) {
+		return Listing{
+			Claims:      l.Claims(),
+			Revocations: l.Revocations(),
+		}, nil
+	}
+}
+
+type HandlerRevoke http.Handler
+
+// NewHandlerRevoke creates a handler that accepts a JSON Revocation and responds with the
+// Revocation as stored, including its assigned ID.
+func NewHandlerRevoke(e endpoint.Endpoint) HandlerRevoke {
+	return kithttp.NewServer(
+		e,
+		func(_ context.Context, request *http.Request) (any, error) {
+			var r Revocation
+			if err := json.NewDecoder(request.Body).Decode(&r); err != nil {
+				return nil, InvalidRevocationError{Err: err}
+			}
+
+			return r, nil
+		},
+		func(_ context.Context, response http.ResponseWriter, value any) error {
+			response.Header().Set("Content-Type", "application/json")
+			response.WriteHeader(http.StatusCreated)
+			return json.NewEncoder(response).Encode(value)
+		},
+	)
+}
+
+type HandlerRemove http.Handler
+
+// NewHandlerRemove creates a handler that removes the Revocation identified by the id path variable
+func NewHandlerRemove(e endpoint.Endpoint) HandlerRemove {
+	return kithttp.NewServer(
+		e,
+		func(_ context.Context, request *http.Request) (any, error) {
+			id, ok := mux.Vars(request)["id"]
+			if !ok {
+				return nil, ErrNoIDVariable
+			}
+
+			return id, nil
+		},
+		func(_ context.Context, response http.ResponseWriter, _ any) error {
+			response.WriteHeader(http.StatusNoContent)
+			return nil
+		},
+	)
+}
+
+type HandlerList http.Handler
+
+// NewHandlerList creates a handler that serves the Listing produced by an endpoint.  Unlike the
+// Feed, the Listing includes the reason for each revocation, so it is never cached.
+func NewHandlerList(e endpoint.Endpoint) HandlerList {
+	return kithttp.NewServer(
+		e,
+		func(context.Context, *http.Request) (any, error) {
+			return nil, nil
+		},
+		func(ctx context.Context, response http.ResponseWriter, value any) error {
+			response.Header().Set("Cache-Control", "no-store")
+			return kithttp.EncodeJSONResponse(ctx, response, value)
+		},
+	)
+}
+
+type HandlerFeed http.Handler
+
+// NewHandlerFeed creates a handler that serves the Feed produced by an endpoint.  As with the
+// JWK Set, responses carry an ETag and a Cache-Control header using maxAge, so that verifiers
+// can poll the feed cheaply.
+func NewHandlerFeed(e endpoint.Endpoint, maxAge time.Duration) HandlerFeed {
+	cacheControl := fmt.Sprintf("public, max-age=%d", int64(maxAge/time.Second))
+	return kithttp.NewServer(
+		e,
+		func(context.Context, *http.Request) (any, error) {
+			return nil, nil
+		},
+		func(ctx context.Context, response http.ResponseWriter, value any) error {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+
+			return xhttp.WriteCacheable(ctx, response, "application/json", cacheControl, data)
+		},
+		kithttp.ServerBefore(xhttp.WithIfNoneMatch),
+	)
+}
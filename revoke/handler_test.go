@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package revoke
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlers(t *testing.T) {
+	var (
+		now = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		l   = testNewList(t, NewMemoryStore(), nil, now)
+
+		revoke = NewHandlerRevoke(NewRevokeEndpoint(l))
+		remove = NewHandlerRemove(NewRemoveEndpoint(l))
+		list   = NewHandlerList(NewListEndpoint(l))
+		feed   = NewHandlerFeed(NewFeedEndpoint(l), DefaultFeedMaxAge)
+		router = mux.NewRouter()
+	)
+
+	router.Handle("/revocations/{id}", remove)
+
+	t.Run("Revoke", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			require  = require.New(t)
+			response = httptest.NewRecorder()
+			request  = httptest.NewRequest("POST", "/revocations", strings.NewReader(`{"type": "mac", "value": "112233445566", "reason": "stolen"}`))
+		)
+
+		revoke.ServeHTTP(response, request)
+		require.Equal(http.StatusCreated, response.Code)
+		assert.Equal("application/json", response.Header().Get("Content-Type"))
+
+		var r Revocation
+		require.NoError(json.Unmarshal(response.Body.Bytes(), &r))
+		assert.Equal(
+			Revocation{ID: "1", Type: TypeMAC, Value: "112233445566", Effective: now, Created: now, Reason: "stolen"},
+			r,
+		)
+	})
+
+	t.Run("RevokeInvalid", func(t *testing.T) {
+		for _, body := range []string{
+			`this is not JSON`,
+			`{"type": "nosuch", "value": "abc"}`,
+			`{"type": "jti"}`,
+		} {
+			response := httptest.NewRecorder()
+			revoke.ServeHTTP(response, httptest.NewRequest("POST", "/revocations", strings.NewReader(body)))
+			assert.Equal(t, http.StatusBadRequest, response.Code, body)
+		}
+	})
+
+	t.Run("Feed", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			require  = require.New(t)
+			response = httptest.NewRecorder()
+		)
+
+		feed.ServeHTTP(response, httptest.NewRequest("GET", "/revocations", nil))
+		require.Equal(http.StatusOK, response.Code)
+		assert.Equal("application/json", response.Header().Get("Content-Type"))
+		assert.Equal("public, max-age=60", response.Header().Get("Cache-Control"))
+
+		var f Feed
+		require.NoError(json.Unmarshal(response.Body.Bytes(), &f))
+		assert.Equal(l.Claims(), f.Claims)
+		assert.Equal(
+			[]FeedRevocation{{ID: "1", Type: TypeMAC, Value: "112233445566", Effective: now}},
+			f.Revocations,
+		)
+
+		// the feed is public, so it never discloses why or when a revocation was made
+		assert.NotContains(response.Body.String(), "stolen")
+		assert.NotContains(response.Body.String(), "reason")
+		assert.NotContains(response.Body.String(), "created")
+
+		etag := response.Header().Get("ETag")
+		require.NotEmpty(etag)
+
+		response = httptest.NewRecorder()
+		request := httptest.NewRequest("GET", "/revocations", nil)
+		request.Header.Set("If-None-Match", etag)
+		feed.ServeHTTP(response, request)
+		assert.Equal(http.StatusNotModified, response.Code)
+		assert.Empty(response.Body.Bytes())
+	})
+
+	t.Run("List", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			require  = require.New(t)
+			response = httptest.NewRecorder()
+		)
+
+		list.ServeHTTP(response, httptest.NewRequest("GET", "/revocations", nil))
+		require.Equal(http.StatusOK, response.Code)
+		assert.Equal("no-store", response.Header().Get("Cache-Control"))
+
+		var listing Listing
+		require.NoError(json.Unmarshal(response.Body.Bytes(), &listing))
+		assert.Equal(l.Claims(), listing.Claims)
+		assert.Equal(l.Revocations(), listing.Revocations)
+		require.Len(listing.Revocations, 1)
+		assert.Equal("stolen", listing.Revocations[0].Reason)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			response = httptest.NewRecorder()
+		)
+
+		router.ServeHTTP(response, httptest.NewRequest("DELETE", "/revocations/1", nil))
+		assert.Equal(http.StatusNoContent, response.Code)
+		assert.Empty(l.Revocations())
+
+		response = httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest("DELETE", "/revocations/1", nil))
+		assert.Equal(http.StatusNotFound, response.Code)
+	})
+
+	t.Run("RemoveNoID", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		remove.ServeHTTP(response, httptest.NewRequest("DELETE", "/revocations", nil))
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+}
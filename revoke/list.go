@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package revoke
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/themis/v2/random"
+)
+
+// List is the set of active revocations, backed by a Store.  A List is safe for concurrent use.
+type List struct {
+	store  Store
+	claims map[string]string
+	noncer random.Noncer
+	now    func() time.Time
+
+	// retention is how long a Revocation is kept once it takes effect.  After that, every token
+	// it applies to has expired.  If nonpositive, revocations are kept until they are removed.
+	retention time.Duration
+
+	lock        sync.RWMutex
+	revocations map[string]Revocation
+
+	// effective maps each revocation type and normalized value onto the latest Effective
+	// time of any Revocation for that value
+	effective map[string]map[string]time.Time
+}
+
+// NewList creates a List holding the revocations in a Store.  The claims map overrides the claim
+// examined for each revocation type, which by default is the claim of the same name, save for
+// TypePartner which uses DefaultPartnerClaim.  Revocations are pruned once retention has elapsed since
+// they took effect, which should be the longest lifetime of any token.  If retention is nonpositive,
+// revocations are never pruned.  The noncer generates revocation IDs.  If noncer is nil,
+// random.NewBase64Noncer is used with its defaults.
+func NewList(s Store, claims map[string]string, retention time.Duration, noncer random.Noncer) (*List, error) {
+	if noncer == nil {
+		noncer = random.NewBase64Noncer(nil, 0, nil)
+	}
+
+	l := &List{
+		store: s,
+		claims: map[string]string{
+			TypeJTI:     "jti",
+			TypeMAC:     "mac",
+			TypeSerial:  "serial",
+			TypeUUID:    "uuid",
+			TypePartner: DefaultPartnerClaim,
+		},
+		noncer:      noncer,
+		now:         time.Now,
+		retention:   retention,
+		revocations: make(map[string]Revocation),
+	}
+
+	for revocationType, claim := range claims {
+		if _, ok := l.claims[revocationType]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidType, revocationType)
+		} else if len(claim) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNoClaim, revocationType)
+		}
+
+		l.claims[revocationType] = claim
+	}
+
+	stored, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range stored {
+		l.revocations[r.ID] = r
+	}
+
+	l.reindex()
+	if err := l.prune(l.now()); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// prune removes each Revocation whose retention has elapsed, reindexing if any were removed.
+// This method must be called under the write lock.
+func (l *List) prune(now time.Time) error {
+	if l.retention <= 0 {
+		return nil
+	}
+
+	pruned := false
+	defer func() {
+		if pruned {
+			l.reindex()
+		}
+	}()
+
+	for id, r := range l.revocations {
+		if now.Before(r.Effective.Add(l.retention)) {
+			continue
+		}
+
+		if err := l.store.Delete(id); err != nil {
+			return err
+		}
+
+		delete(l.revocations, id)
+		pruned = true
+	}
+
+	return nil
+}
+
+// reindex rebuilds the effective index.  This method must be called under the write lock.
+func (l *List) reindex() {
+	l.effective = make(map[string]map[string]time.Time)
+	for _, r := range l.revocations {
+		values := l.effective[r.Type]
+		if values == nil {
+			values = make(map[string]time.Time)
+			l.effective[r.Type] = values
+		}
+
+		value := normalize(r.Type, r.Value)
+		if current, ok := values[value]; !ok || r.Effective.After(current) {
+			values[value] = r.Effective
+		}
+	}
+}
+
+// Claims returns a copy of the claim examined for each revocation type
+func (l *List) Claims() map[string]string {
+	claims := make(map[string]string, len(l.claims))
+	for k, v := range l.claims {
+		claims[k] = v
+	}
+
+	return claims
+}
+
+// Revoke validates and adds a Revocation, returning the Revocation as stored with its ID
+// and times assigned.
+func (l *List) Revoke(r Revocation) (Revocation, error) {
+	if err := r.Validate(); err != nil {
+		return Revocation{}, err
+	}
+
+	id, err := l.noncer.Nonce()
+	if err != nil {
+		return Revocation{}, err
+	}
+
+	r.ID = id
+	r.Created = l.now().UTC()
+	if r.Effective.IsZero() {
+		r.Effective = r.Created
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if err := l.prune(r.Created); err != nil {
+		return Revocation{}, err
+	}
+
+	if err := l.store.Save(r); err != nil {
+		return Revocation{}, err
+	}
+
+	l.revocations[r.ID] = r
+	l.reindex()
+	return r, nil
+}
+
+// Remove deletes a Revocation, returning false if there was no Revocation with the given ID
+func (l *List) Remove(id string) (bool, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if _, ok := l.revocations[id]; !ok {
+		return false, nil
+	}
+
+	if err := l.store.Delete(id); err != nil {
+		return false, err
+	}
+
+	delete(l.revocations, id)
+	l.reindex()
+	return true, nil
+}
+
+// Revocations returns a snapshot of every Revocation in this List, ordered by creation time
+func (l *List) Revocations() []Revocation {
+	l.lock.Lock()
+
+	// a failure leaves the remaining revocations in place, to be pruned by a later call
+	_ = l.prune(l.now())
+
+	revocations := make([]Revocation, 0, len(l.revocations))
+	for _, r := range l.revocations {
+		revocations = append(revocations, r)
+	}
+
+	l.lock.Unlock()
+	sort.Slice(revocations, func(i, j int) bool {
+		if revocations[i].Created.Equal(revocations[j].Created) {
+			return revocations[i].ID < revocations[j].ID
+		}
+
+		return revocations[i].Created.Before(revocations[j].Created)
+	})
+
+	return revocations
+}
+
+// Revoked tests if a token with the given claims has been revoked
+func (l *List) Revoked(claims map[string]any) bool {
+	iat, hasIAT := issuedAt(claims)
+
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	for revocationType, values := range l.effective {
+		value, ok := claims[l.claims[revocationType]].(string)
+		if !ok {
+			continue
+		}
+
+		effective, ok := values[normalize(revocationType, value)]
+		if ok && (!hasIAT || iat.Before(effective)) {
+			return true
+		}
+	}
+
+	return false
+}
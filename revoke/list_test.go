@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package revoke
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNoncer produces predictable revocation IDs
+type testNoncer struct {
+	next int
+	err  error
+}
+
+func (tn *testNoncer) Nonce() (string, error) {
+	tn.next++
+	return strconv.Itoa(tn.next), tn.err
+}
+
+// failingStore is a Store whose writes always fail
+type failingStore struct {
+	Store
+	err error
+}
+
+func (fs failingStore) Save(Revocation) error { return fs.err }
+func (fs failingStore) Delete(string) error   { return fs.err }
+
+func testNewList(t *testing.T, s Store, claims map[string]string, now time.Time) *List {
+	l, err := NewList(s, claims, 0, &testNoncer{})
+	require.NoError(t, err)
+	require.NotNil(t, l)
+	l.now = func() time.Time { return now }
+	return l
+}
+
+func testListRevoked(t *testing.T) {
+	var (
+		now = time.Unix(1700000000, 0)
+		l   = testNewList(t, NewMemoryStore(), map[string]string{TypePartner: "partner"}, now)
+
+		iat = func(d time.Duration) json.Number {
+			return json.Number(strconv.FormatInt(now.Add(d).Unix(), 10))
+		}
+	)
+
+	for _, r := range []Revocation{
+		{Type: TypeJTI, Value: "leaked"},
+		{Type: TypeMAC, Value: "AA:BB:CC:DD:EE:FF"},
+		{Type: TypeSerial, Value: "ABC123", Effective: now.Add(-time.Hour)},
+		{Type: TypeUUID, Value: "0F8FAD5B-D9CB-469F-A165-70867728950E"},
+		{Type: TypePartner, Value: "comcast", Effective: now.Add(time.Hour)},
+	} {
+		_, err := l.Revoke(r)
+		require.NoError(t, err)
+	}
+
+	testData := []struct {
+		name     string
+		claims   map[string]any
+		expected bool
+	}{
+		{"NoClaims", map[string]any{}, false},
+		{"JTI", map[string]any{"jti": "leaked", "iat": iat(-time.Minute)}, true},
+		{"OtherJTI", map[string]any{"jti": "other", "iat": iat(-time.Minute)}, false},
+		{"MACNormalized", map[string]any{"mac": "aabbccddeeff", "iat": iat(-time.Minute)}, true},
+		{"MACIssuedAfter", map[string]any{"mac": "aabbccddeeff", "iat": iat(time.Minute)}, false},
+		{"MACNoIssuedAt", map[string]any{"mac": "aabbccddeeff"}, true},
+		{"SerialBeforeEffective", map[string]any{"serial": "ABC123", "iat": iat(-2 * time.Hour)}, true},
+		{"SerialAfterEffective", map[string]any{"serial": "ABC123", "iat": iat(-time.Minute)}, false},
+		{"UUID", map[string]any{"uuid": "0f8fad5b-d9cb-469f-a165-70867728950e", "iat": float64(now.Unix() - 1)}, true},
+		{"PartnerFutureEffective", map[string]any{"partner": "comcast", "iat": iat(30 * time.Minute)}, true},
+		{"PartnerDefaultClaim", map[string]any{DefaultPartnerClaim: "comcast", "iat": iat(0)}, false},
+		{"NonStringClaim", map[string]any{"jti": 123}, false},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			assert.Equal(t, record.expected, l.Revoked(record.claims))
+		})
+	}
+}
+
+func testListRevokeAndRemove(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		now     = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		store   = NewMemoryStore()
+		l       = testNewList(t, store, nil, now)
+	)
+
+	first, err := l.Revoke(Revocation{Type: TypeJTI, Value: "abc", Reason: "leaked"})
+	require.NoError(err)
+	assert.Equal(
+		Revocation{ID: "1", Type: TypeJTI, Value: "abc", Effective: now, Created: now, Reason: "leaked"},
+		first,
+	)
+
+	effective := now.Add(-time.Hour)
+	second, err := l.Revoke(Revocation{Type: TypeSerial, Value: "ABC123", Effective: effective})
+	require.NoError(err)
+	assert.Equal("2", second.ID)
+	assert.Equal(effective, second.Effective)
+
+	_, err = l.Revoke(Revocation{Type: "nosuch", Value: "abc"})
+	assert.ErrorIs(err, ErrInvalidType)
+
+	assert.Equal([]Revocation{first, second}, l.Revocations())
+	stored, err := store.Load()
+	require.NoError(err)
+	assert.ElementsMatch([]Revocation{first, second}, stored)
+
+	// the list is restored from its store
+	restored := testNewList(t, store, nil, now)
+	assert.Equal([]Revocation{first, second}, restored.Revocations())
+	assert.True(restored.Revoked(map[string]any{"jti": "abc"}))
+
+	removed, err := l.Remove(first.ID)
+	require.NoError(err)
+	assert.True(removed)
+	assert.False(l.Revoked(map[string]any{"jti": "abc"}))
+	assert.Equal([]Revocation{second}, l.Revocations())
+
+	removed, err = l.Remove(first.ID)
+	require.NoError(err)
+	assert.False(removed)
+}
+
+func testListStoreErrors(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		expected = errors.New("expected")
+		now      = time.Now()
+		l        = testNewList(t, failingStore{Store: NewMemoryStore(), err: expected}, nil, now)
+	)
+
+	_, err := l.Revoke(Revocation{Type: TypeJTI, Value: "abc"})
+	assert.ErrorIs(err, expected)
+	assert.Empty(l.Revocations())
+	assert.False(l.Revoked(map[string]any{"jti": "abc"}))
+
+	l = testNewList(t, NewMemoryStore(), nil, now)
+	r, err := l.Revoke(Revocation{Type: TypeJTI, Value: "abc"})
+	require.NoError(t, err)
+	l.store = failingStore{Store: l.store, err: expected}
+	removed, err := l.Remove(r.ID)
+	assert.ErrorIs(err, expected)
+	assert.False(removed)
+	assert.True(l.Revoked(map[string]any{"jti": "abc"}))
+
+	l, err = NewList(NewMemoryStore(), nil, 0, &testNoncer{err: expected})
+	require.NoError(t, err)
+	_, err = l.Revoke(Revocation{Type: TypeJTI, Value: "abc"})
+	assert.ErrorIs(err, expected)
+}
+
+func testListInvalidClaims(t *testing.T) {
+	l, err := NewList(NewMemoryStore(), map[string]string{"nosuch": "claim"}, 0, nil)
+	assert.ErrorIs(t, err, ErrInvalidType)
+	assert.Nil(t, l)
+
+	l, err = NewList(NewMemoryStore(), map[string]string{TypeMAC: ""}, 0, nil)
+	assert.ErrorIs(t, err, ErrNoClaim)
+	assert.Nil(t, l)
+}
+
+func testListClaims(t *testing.T) {
+	l := testNewList(t, NewMemoryStore(), map[string]string{TypePartner: "pid"}, time.Now())
+	claims := l.Claims()
+	assert.Equal(
+		t,
+		map[string]string{TypeJTI: "jti", TypeMAC: "mac", TypeSerial: "serial", TypeUUID: "uuid", TypePartner: "pid"},
+		claims,
+	)
+
+	// the returned map is a copy
+	claims[TypeJTI] = "changed"
+	assert.Equal(t, "jti", l.Claims()[TypeJTI])
+}
+
+func testListPrune(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		now     = time.Now().UTC()
+		store   = NewMemoryStore()
+	)
+
+	// revocations whose retention elapsed while themis was down are pruned when loaded
+	require.NoError(store.Save(Revocation{ID: "stale", Type: TypeJTI, Value: "stale", Effective: now.Add(-2 * time.Hour)}))
+	require.NoError(store.Save(Revocation{ID: "current", Type: TypeJTI, Value: "current", Effective: now.Add(-time.Minute)}))
+	l, err := NewList(store, nil, time.Hour, &testNoncer{})
+	require.NoError(err)
+	l.now = func() time.Time { return now }
+
+	stored, err := store.Load()
+	require.NoError(err)
+	assert.Len(stored, 1)
+	assert.Len(l.Revocations(), 1)
+	assert.False(l.Revoked(map[string]any{"jti": "stale"}))
+	assert.True(l.Revoked(map[string]any{"jti": "current"}))
+
+	r, err := l.Revoke(Revocation{Type: TypeMAC, Value: "112233445566"})
+	require.NoError(err)
+	assert.Len(l.Revocations(), 2)
+
+	// once every token issued before a revocation has expired, the revocation is pruned
+	now = now.Add(time.Hour - time.Second)
+	revocations := l.Revocations()
+	require.Len(revocations, 1)
+	assert.Equal(r.ID, revocations[0].ID)
+	assert.False(l.Revoked(map[string]any{"jti": "current"}))
+
+	stored, err = store.Load()
+	require.NoError(err)
+	assert.Equal([]Revocation{r}, stored)
+
+	// a store failure leaves the revocation in place
+	now = now.Add(time.Hour)
+	l.store = failingStore{Store: store, err: errors.New("expected")}
+	assert.Len(l.Revocations(), 1)
+	_, err = l.Revoke(Revocation{Type: TypeJTI, Value: "abc"})
+	assert.Error(err)
+}
+
+func TestList(t *testing.T) {
+	t.Run("Revoked", testListRevoked)
+	t.Run("RevokeAndRemove", testListRevokeAndRemove)
+	t.Run("StoreErrors", testListStoreErrors)
+	t.Run("InvalidClaims", testListInvalidClaims)
+	t.Run("Claims", testListClaims)
+	t.Run("Prune", testListPrune)
+}
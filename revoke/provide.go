@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package revoke
+
+import (
+	"time"
+
+	"github.com/xmidt-org/themis/v2/config"
+	"github.com/xmidt-org/themis/v2/random"
+	"go.uber.org/fx"
+)
+
+// Options is the configuration for token revocation
+type Options struct {
+	// File is the system path of the JSON file that persists revocations.  If unset, and no Store
+	// is present in the container, revocations are kept only in memory and are lost on restart.
+	File string
+
+	// Claims overrides the claim examined for each revocation type.  For example, if partner ids
+	// are issued in a partner claim, set partner: partner here.
+	Claims map[string]string
+
+	// FeedMaxAge is the Cache-Control max-age for the revocation feed.  If unset, DefaultFeedMaxAge is used.
+	FeedMaxAge time.Duration
+}
+
+// RevokeIn is the set of dependencies for this package's components
+type RevokeIn struct {
+	fx.In
+
+	Unmarshaller config.Unmarshaller
+
+	// Noncer is the optional strategy for generating revocation IDs
+	Noncer random.Noncer `optional:"true"`
+
+	// Store is the optional Store used to persist revocations.  If present in the container,
+	// it is used in place of the store described by Options.
+	Store Store `optional:"true"`
+
+	// Retention is how long revocations are kept once they take effect, which should be the longest
+	// lifetime of any token.  If unset, revocations are kept until they are removed.
+	Retention time.Duration `name:"revocation_retention" optional:"true"`
+}
+
+// RevokeOut is the set of components emitted by this package.  If revocation is not
+// configured, every component is nil.
+type RevokeOut struct {
+	fx.Out
+
+	// List is the set of active revocations
+	List *List
+
+	// HandlerRevoke is the administrative http.Handler that adds revocations
+	HandlerRevoke HandlerRevoke
+
+	// HandlerRemove is the administrative http.Handler that removes revocations
+	HandlerRemove HandlerRemove
+
+	// HandlerList is the administrative http.Handler that lists revocations with all of their fields
+	HandlerList HandlerList
+
+	// HandlerFeed is the http.Handler that publishes the revocations to verifiers
+	HandlerFeed HandlerFeed
+}
+
+// Unmarshal returns an uber/fx style provider that creates this package's components from the
+// Options at the given configuration key.  If the key is not set, revocation is disabled.
+func Unmarshal(configKey string) func(RevokeIn) (RevokeOut, error) {
+	return func(in RevokeIn) (RevokeOut, error) {
+		if !in.Unmarshaller.IsSet(configKey) {
+			return RevokeOut{}, nil
+		}
+
+		var o Options
+		if err := in.Unmarshaller.UnmarshalKey(configKey, &o); err != nil {
+			return RevokeOut{}, err
+		}
+
+		store := in.Store
+		if store == nil && len(o.File) > 0 {
+			var err error
+			if store, err = NewFileStore(o.File); err != nil {
+				return RevokeOut{}, err
+			}
+		} else if store == nil {
+			store = NewMemoryStore()
+		}
+
+		l, err := NewList(store, o.Claims, in.Retention, in.Noncer)
+		if err != nil {
+			return RevokeOut{}, err
+		}
+
+		if o.FeedMaxAge <= 0 {
+			o.FeedMaxAge = DefaultFeedMaxAge
+		}
+
+		return RevokeOut{
+			List:          l,
+			HandlerRevoke: NewHandlerRevoke(NewRevokeEndpoint(l)),
+			HandlerRemove: NewHandlerRemove(NewRemoveEndpoint(l)),
+			HandlerList:   NewHandlerList(NewListEndpoint(l)),
+			HandlerFeed:   NewHandlerFeed(NewFeedEndpoint(l), o.FeedMaxAge),
+		}, nil
+	}
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package revoke
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/config"
+)
+
+func testUnmarshal(t *testing.T, configuration string, s Store) (RevokeOut, error) {
+	v := viper.New()
+	require.NoError(t, config.Json(configuration)(config.ViperIn{}, v))
+	return Unmarshal("revocation")(RevokeIn{
+		Unmarshaller: config.ViperUnmarshaller{Viper: v},
+		Store:        s,
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("NotSet", func(t *testing.T) {
+		out, err := testUnmarshal(t, `{}`, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, out.List)
+		assert.Nil(t, out.HandlerRevoke)
+		assert.Nil(t, out.HandlerRemove)
+		assert.Nil(t, out.HandlerList)
+		assert.Nil(t, out.HandlerFeed)
+	})
+
+	t.Run("Memory", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+		)
+
+		out, err := testUnmarshal(t, `{"revocation": {"claims": {"partner": "partner"}}}`, nil)
+		require.NoError(err)
+		require.NotNil(out.List)
+		assert.NotNil(out.HandlerRevoke)
+		assert.NotNil(out.HandlerRemove)
+		assert.NotNil(out.HandlerList)
+		assert.NotNil(out.HandlerFeed)
+		assert.Equal("partner", out.List.Claims()[TypePartner])
+	})
+
+	t.Run("File", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+			path    = filepath.Join(t.TempDir(), "revocations.json")
+		)
+
+		out, err := testUnmarshal(t, `{"revocation": {"file": "`+path+`"}}`, nil)
+		require.NoError(err)
+		require.NotNil(out.List)
+		_, err = out.List.Revoke(Revocation{Type: TypeJTI, Value: "abc"})
+		require.NoError(err)
+		assert.FileExists(path)
+	})
+
+	t.Run("CustomStore", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+			store   = NewMemoryStore()
+			path    = filepath.Join(t.TempDir(), "revocations.json")
+		)
+
+		out, err := testUnmarshal(t, `{"revocation": {"file": "`+path+`"}}`, store)
+		require.NoError(err)
+		require.NotNil(out.List)
+		_, err = out.List.Revoke(Revocation{Type: TypeJTI, Value: "abc"})
+		require.NoError(err)
+
+		stored, err := store.Load()
+		require.NoError(err)
+		assert.Len(stored, 1)
+		assert.NoFileExists(path)
+	})
+
+	t.Run("InvalidFile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "revocations.json")
+		require.NoError(t, os.WriteFile(path, []byte("this is not JSON"), 0600))
+		_, err := testUnmarshal(t, `{"revocation": {"file": "`+path+`"}}`, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("InvalidClaims", func(t *testing.T) {
+		_, err := testUnmarshal(t, `{"revocation": {"claims": {"nosuch": "claim"}}}`, nil)
+		assert.ErrorIs(t, err, ErrInvalidType)
+	})
+}
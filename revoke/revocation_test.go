@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package revoke
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevocationValidate(t *testing.T) {
+	testData := []struct {
+		revocation Revocation
+		expected   error
+	}{
+		{Revocation{Type: TypeJTI, Value: "abc"}, nil},
+		{Revocation{Type: TypeMAC, Value: "112233445566"}, nil},
+		{Revocation{Type: TypeSerial, Value: "serial"}, nil},
+		{Revocation{Type: TypeUUID, Value: "uuid"}, nil},
+		{Revocation{Type: TypePartner, Value: "comcast"}, nil},
+		{Revocation{Type: "nosuch", Value: "abc"}, ErrInvalidType},
+		{Revocation{Value: "abc"}, ErrInvalidType},
+		{Revocation{Type: TypeJTI}, ErrNoValue},
+	}
+
+	for _, record := range testData {
+		t.Run(record.revocation.Type+"/"+record.revocation.Value, func(t *testing.T) {
+			err := record.revocation.Validate()
+			if record.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, record.expected)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("aabbccddeeff", normalize(TypeMAC, "AA:BB:CC:DD:EE:FF"))
+	assert.Equal("aabbccddeeff", normalize(TypeMAC, "aa-bb-cc-dd-ee-ff"))
+	assert.Equal("aabbccddeeff", normalize(TypeMAC, "aabb.ccdd.eeff"))
+	assert.Equal("0f8fad5b-d9cb-469f-a165-70867728950e", normalize(TypeUUID, "0F8FAD5B-D9CB-469F-A165-70867728950E"))
+	assert.Equal("ABC", normalize(TypeSerial, "ABC"))
+	assert.Equal("ABC", normalize(TypeJTI, "ABC"))
+}
+
+func TestIssuedAt(t *testing.T) {
+	expected := time.Unix(1700000000, 0)
+	testData := []struct {
+		name   string
+		claims map[string]any
+		ok     bool
+	}{
+		{"JSONNumber", map[string]any{"iat": json.Number("1700000000")}, true},
+		{"Float64", map[string]any{"iat": float64(1700000000)}, true},
+		{"Int64", map[string]any{"iat": int64(1700000000)}, true},
+		{"Int", map[string]any{"iat": 1700000000}, true},
+		{"Missing", map[string]any{}, false},
+		{"String", map[string]any{"iat": "1700000000"}, false},
+		{"InvalidNumber", map[string]any{"iat": json.Number("abc")}, false},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			iat, ok := issuedAt(record.claims)
+			assert.Equal(t, record.ok, ok)
+			if record.ok {
+				assert.True(t, expected.Equal(iat))
+			}
+		})
+	}
+}
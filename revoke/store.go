@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package revoke
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Store is a strategy for persisting revocations, so that they survive restarts.  A List
+// serializes all access to its Store, so implementations need not be safe for concurrent use.
+type Store interface {
+	// Load returns every stored Revocation
+	Load() ([]Revocation, error)
+
+	// Save stores a Revocation, replacing any existing Revocation with the same ID
+	Save(Revocation) error
+
+	// Delete removes the Revocation with the given ID.  Deleting an ID that has
+	// no stored Revocation is not an error.
+	Delete(id string) error
+}
+
+// memoryStore is a Store that keeps revocations only in memory
+type memoryStore map[string]Revocation
+
+// NewMemoryStore creates a Store that does not persist revocations
+func NewMemoryStore() Store {
+	return make(memoryStore)
+}
+
+func (ms memoryStore) Load() ([]Revocation, error) {
+	revocations := make([]Revocation, 0, len(ms))
+	for _, r := range ms {
+		revocations = append(revocations, r)
+	}
+
+	return revocations, nil
+}
+
+func (ms memoryStore) Save(r Revocation) error {
+	ms[r.ID] = r
+	return nil
+}
+
+func (ms memoryStore) Delete(id string) error {
+	delete(ms, id)
+	return nil
+}
+
+// fileStore is a Store that writes every revocation to a single JSON file
+type fileStore struct {
+	path        string
+	revocations memoryStore
+}
+
+// NewFileStore creates a Store backed by a JSON file, which holds an array of revocations.  The file
+// is created when the first Revocation is saved, and it is replaced in its entirety on each change.
+func NewFileStore(path string) (Store, error) {
+	fs := &fileStore{
+		path:        path,
+		revocations: make(memoryStore),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fs, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var revocations []Revocation
+	if err := json.Unmarshal(data, &revocations); err != nil {
+		return nil, err
+	}
+
+	for _, r := range revocations {
+		fs.revocations[r.ID] = r
+	}
+
+	return fs, nil
+}
+
+func (fs *fileStore) Load() ([]Revocation, error) {
+	return fs.revocations.Load()
+}
+
+func (fs *fileStore) Save(r Revocation) error {
+	previous, existed := fs.revocations[r.ID]
+	fs.revocations[r.ID] = r
+	if err := fs.write(); err != nil {
+		if existed {
+			fs.revocations[r.ID] = previous
+		} else {
+			delete(fs.revocations, r.ID)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (fs *fileStore) Delete(id string) error {
+	previous, existed := fs.revocations[id]
+	if !existed {
+		return nil
+	}
+
+	delete(fs.revocations, id)
+	if err := fs.write(); err != nil {
+		fs.revocations[id] = previous
+		return err
+	}
+
+	return nil
+}
+
+// write replaces the file with the current revocations
+func (fs *fileStore) write() error {
+	revocations, _ := fs.revocations.Load()
+	sort.Slice(revocations, func(i, j int) bool {
+		return revocations[i].ID < revocations[j].ID
+	})
+
+	data, err := json.MarshalIndent(revocations, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that a partially written file is never loaded
+	f, err := os.CreateTemp(filepath.Dir(fs.path), "."+filepath.Base(fs.path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name()) // nolint: errcheck
+	err = f.Chmod(0600)
+	if err == nil {
+		_, err = f.Write(data)
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), fs.path)
+}
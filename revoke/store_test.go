@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package revoke
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStoreRoundTrip(t *testing.T, s Store) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		now     = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		first  = Revocation{ID: "first", Type: TypeJTI, Value: "abc", Effective: now, Created: now}
+		second = Revocation{ID: "second", Type: TypeMAC, Value: "112233445566", Effective: now.Add(time.Hour), Created: now, Reason: "stolen"}
+	)
+
+	revocations, err := s.Load()
+	require.NoError(err)
+	assert.Empty(revocations)
+
+	require.NoError(s.Save(first))
+	require.NoError(s.Save(second))
+	revocations, err = s.Load()
+	require.NoError(err)
+	assert.ElementsMatch([]Revocation{first, second}, revocations)
+
+	second.Reason = "updated"
+	require.NoError(s.Save(second))
+	require.NoError(s.Delete("first"))
+	require.NoError(s.Delete("nosuch"))
+	revocations, err = s.Load()
+	require.NoError(err)
+	assert.Equal([]Revocation{second}, revocations)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStoreRoundTrip(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "revocations.json")
+		s, err := NewFileStore(path)
+		require.NoError(t, err)
+		testStoreRoundTrip(t, s)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		// the persisted revocations are loaded by a new store
+		reloaded, err := NewFileStore(path)
+		require.NoError(t, err)
+		revocations, err := reloaded.Load()
+		require.NoError(t, err)
+		require.Len(t, revocations, 1)
+		assert.Equal(t, "second", revocations[0].ID)
+		assert.Equal(t, "updated", revocations[0].Reason)
+	})
+
+	t.Run("InvalidFile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "revocations.json")
+		require.NoError(t, os.WriteFile(path, []byte("this is not JSON"), 0600))
+		s, err := NewFileStore(path)
+		assert.Error(t, err)
+		assert.Nil(t, s)
+	})
+
+	t.Run("WriteFailure", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+			path    = filepath.Join(t.TempDir(), "missing", "revocations.json")
+		)
+
+		s, err := NewFileStore(path)
+		require.NoError(err)
+
+		// failed writes leave the store unchanged
+		assert.Error(s.Save(Revocation{ID: "test", Type: TypeJTI, Value: "abc"}))
+		revocations, err := s.Load()
+		require.NoError(err)
+		assert.Empty(revocations)
+	})
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/xmidt-org/candlelight"
 	"github.com/xmidt-org/sallust"
 	"github.com/xmidt-org/themis/v2/key"
+	"github.com/xmidt-org/themis/v2/oauth"
+	"github.com/xmidt-org/themis/v2/revoke"
 	"github.com/xmidt-org/themis/v2/token"
 	"github.com/xmidt-org/themis/v2/xhealth"
 	"github.com/xmidt-org/themis/v2/xhttp/xhttpserver"
@@ -76,22 +78,96 @@ func provideServerChainFactory(in ServerChainIn) xhttpserver.ChainFactory {
 	})
 }
 
+const (
+	// JWKSPath is the path to the JSON Web Key Set on the keys server
+	JWKSPath = "/.well-known/jwks.json"
+
+	// DiscoveryPath is the path to the OpenID Connect discovery document on the keys server
+	DiscoveryPath = "/.well-known/openid-configuration"
+
+	// IssuePath is the path to the token issue endpoint on the issuer server
+	IssuePath = "/issue"
+
+	// PASETOIssuePath is the path to the issue endpoint that only issues PASETO tokens
+	PASETOIssuePath = "/issue/paseto"
+
+	// RefreshPath is the path to the refresh token endpoint on the issuer server
+	RefreshPath = "/refresh"
+
+	// TokenPath is the path to the OAuth 2.0 token endpoint on the issuer server
+	TokenPath = "/token"
+
+	// ClaimsPath is the path to the claims endpoint on the claims server
+	ClaimsPath = "/claims"
+
+	// IntrospectPath is the path to the token introspection endpoint on the introspect server
+	IntrospectPath = "/introspect"
+
+	// RevocationsPath is the path to the public revocation feed on the keys server, and to the
+	// revocation administration API on the revoke server
+	RevocationsPath = "/revocations"
+)
+
 type KeyRoutesIn struct {
 	fx.In
-	Router     *mux.Router `name:"servers.key"`
-	Handler    key.Handler
-	HandlerJWK key.HandlerJWK
+	Router      *mux.Router `name:"servers.key"`
+	Handler     key.Handler
+	HandlerJWK  key.HandlerJWK
+	HandlerJWKS key.HandlerJWKS `optional:"true"`
+
+	// HandlerCOSE serves each public key as a COSE_Key, for verifiers of CWTs
+	HandlerCOSE key.HandlerCOSE `optional:"true"`
+
+	// HandlerPASERK serves each Ed25519 public key as a PASERK, for verifiers of PASETO tokens
+	HandlerPASERK key.HandlerPASERK `optional:"true"`
+
+	// HandlerSecret serves symmetric keys to authenticated verifiers.  Symmetric
+	// keys are never served by the other key handlers.
+	HandlerSecret key.HandlerSecret `optional:"true"`
+
+	// HandlerDiscovery serves the OpenID Connect discovery document
+	HandlerDiscovery token.DiscoveryHandler `optional:"true"`
+
+	// HandlerRevocations publishes token revocations to verifiers, without their reasons
+	HandlerRevocations revoke.HandlerFeed `optional:"true"`
 }
 
 func BuildKeyRoutes(in KeyRoutesIn) {
 	if in.Router != nil {
+		if in.HandlerJWKS != nil {
+			in.Router.Handle(JWKSPath, in.HandlerJWKS).Methods("GET")
+		}
+
+		if in.HandlerDiscovery != nil {
+			in.Router.Handle(DiscoveryPath, in.HandlerDiscovery).Methods("GET")
+		}
+
+		if in.HandlerSecret != nil {
+			in.Router.Handle("/secrets/{kid}", in.HandlerSecret).Methods("GET")
+		}
+
+		if in.HandlerRevocations != nil {
+			in.Router.Handle(RevocationsPath, in.HandlerRevocations).Methods("GET")
+		}
+
 		keys := in.Router.PathPrefix("/keys/{kid}").Methods("GET").Subrouter()
 
 		keys.Headers("Accept", key.ContentTypePEM).Handler(in.Handler)
 		keys.Headers("Accept", key.ContentTypeJWK).Handler(in.HandlerJWK)
+		if in.HandlerCOSE != nil {
+			keys.Headers("Accept", key.ContentTypeCOSEKey).Handler(in.HandlerCOSE)
+		}
+
 		keys.Path("").Handler(in.Handler) // default
 		keys.Path("/key.pem").Handler(in.Handler)
 		keys.Path("/key.json").Handler(in.HandlerJWK)
+		if in.HandlerCOSE != nil {
+			keys.Path("/key.cose").Handler(in.HandlerCOSE)
+		}
+
+		if in.HandlerPASERK != nil {
+			keys.Path("/key.paserk").Handler(in.HandlerPASERK)
+		}
 	}
 }
 
@@ -99,11 +175,48 @@ type IssuerRoutesIn struct {
 	fx.In
 	Router  *mux.Router `name:"servers.issuer"`
 	Handler token.IssueHandler
+
+	// RefreshHandler is the optional handler that redeems refresh tokens
+	RefreshHandler token.RefreshHandler `optional:"true"`
+
+	// PASETOHandler is the optional handler that only issues PASETO tokens
+	PASETOHandler token.PASETOIssueHandler `optional:"true"`
+
+	// TokenHandler is the optional OAuth 2.0 token endpoint
+	TokenHandler oauth.TokenHandler `optional:"true"`
+
+	// Profiles are the handlers of the optional named token profiles
+	Profiles []token.ProfileHandlers `optional:"true"`
 }
 
 func BuildIssuerRoutes(in IssuerRoutesIn) {
 	if in.Router != nil && in.Handler != nil {
-		in.Router.Handle("/issue", SetLogger(in.Handler)).Methods("GET")
+		in.Router.Handle(IssuePath, SetLogger(in.Handler)).Methods("GET")
+	}
+
+	if in.Router != nil && in.PASETOHandler != nil {
+		in.Router.Handle(PASETOIssuePath, SetLogger(in.PASETOHandler)).Methods("GET")
+	}
+
+	if in.Router != nil && in.RefreshHandler != nil {
+		in.Router.Handle(RefreshPath, SetLogger(in.RefreshHandler)).Methods("POST")
+	}
+
+	if in.Router != nil && in.TokenHandler != nil {
+		in.Router.Handle(TokenPath, SetLogger(in.TokenHandler)).Methods("POST")
+	}
+
+	if in.Router != nil {
+		for _, p := range in.Profiles {
+			in.Router.Handle(IssuePath+"/"+p.Name, SetLogger(p.IssueHandler)).Methods("GET")
+			if p.PASETOIssueHandler != nil {
+				in.Router.Handle(IssuePath+"/"+p.Name+"/paseto", SetLogger(p.PASETOIssueHandler)).Methods("GET")
+			}
+
+			if p.RefreshHandler != nil {
+				in.Router.Handle(RefreshPath+"/"+p.Name, SetLogger(p.RefreshHandler)).Methods("POST")
+			}
+		}
 	}
 }
 
@@ -111,11 +224,52 @@ type ClaimsRoutesIn struct {
 	fx.In
 	Router  *mux.Router `name:"servers.claims"`
 	Handler token.ClaimsHandler
+
+	// Profiles are the handlers of the optional named token profiles
+	Profiles []token.ProfileHandlers `optional:"true"`
 }
 
 func BuildClaimsRoutes(in ClaimsRoutesIn) {
 	if in.Router != nil && in.Handler != nil {
-		in.Router.Handle("/claims", SetLogger(in.Handler)).Methods("GET")
+		in.Router.Handle(ClaimsPath, SetLogger(in.Handler)).Methods("GET")
+	}
+
+	if in.Router != nil {
+		for _, p := range in.Profiles {
+			in.Router.Handle(ClaimsPath+"/"+p.Name, SetLogger(p.ClaimsHandler)).Methods("GET")
+		}
+	}
+}
+
+type IntrospectRoutesIn struct {
+	fx.In
+	Router  *mux.Router `name:"servers.introspect"`
+	Handler token.IntrospectHandler
+}
+
+// BuildIntrospectRoutes adds the RFC 7662 introspection route.  Introspection reveals token
+// claims, so it has its own server that can be secured, e.g. with mTLS, apart from the issuer.
+func BuildIntrospectRoutes(in IntrospectRoutesIn) {
+	if in.Router != nil && in.Handler != nil {
+		in.Router.Handle(IntrospectPath, SetLogger(in.Handler)).Methods("POST")
+	}
+}
+
+type RevokeRoutesIn struct {
+	fx.In
+	Router        *mux.Router `name:"servers.revoke"`
+	HandlerRevoke revoke.HandlerRevoke
+	HandlerRemove revoke.HandlerRemove
+	HandlerList   revoke.HandlerList
+}
+
+// BuildRevokeRoutes adds the revocation administration API.  Anyone who can reach this server can
+// revoke tokens, so it must only be reachable by operators.
+func BuildRevokeRoutes(in RevokeRoutesIn) {
+	if in.Router != nil && in.HandlerRevoke != nil {
+		in.Router.Handle(RevocationsPath, SetLogger(in.HandlerRevoke)).Methods("POST")
+		in.Router.Handle(RevocationsPath, SetLogger(in.HandlerList)).Methods("GET")
+		in.Router.Handle(RevocationsPath+"/{id}", SetLogger(in.HandlerRemove)).Methods("DELETE")
 	}
 }
 
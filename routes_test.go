@@ -10,6 +10,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/xmidt-org/themis/v2/key"
+	"github.com/xmidt-org/themis/v2/token"
 )
 
 func TestBuildKeyRoutes(t *testing.T) {
@@ -34,13 +35,97 @@ func TestBuildKeyRoutes(t *testing.T) {
 			response.Write([]byte("jwk"))
 		})
 
+		handlerCOSE = http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.Header().Set("Content-Type", key.ContentTypeCOSEKey)
+			response.Write([]byte("cose"))
+		})
+
+		handlerPASERK = http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.Header().Set("Content-Type", key.ContentTypePASERK)
+			response.Write([]byte("paserk"))
+		})
+
+		handlerJWKS = http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.Header().Set("Content-Type", key.ContentTypeJWKS)
+			response.Write([]byte("jwks"))
+		})
+
+		handlerSecret = http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.Header().Set("Content-Type", key.ContentTypeJWK)
+			response.Write([]byte("secret " + mux.Vars(request)["kid"]))
+		})
+
+		handlerDiscovery = http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.Header().Set("Content-Type", "application/json")
+			response.Write([]byte("discovery"))
+		})
+
+		handlerRevocations = http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.Write([]byte("revocations"))
+		})
+
 		router = mux.NewRouter()
 	)
 
 	BuildKeyRoutes(KeyRoutesIn{
-		Router:     router,
-		Handler:    handlerPEM,
-		HandlerJWK: handlerJWK,
+		Router:             router,
+		Handler:            handlerPEM,
+		HandlerJWK:         handlerJWK,
+		HandlerJWKS:        handlerJWKS,
+		HandlerCOSE:        handlerCOSE,
+		HandlerPASERK:      handlerPASERK,
+		HandlerSecret:      handlerSecret,
+		HandlerDiscovery:   handlerDiscovery,
+		HandlerRevocations: handlerRevocations,
+	})
+
+	t.Run("revocations", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			response = httptest.NewRecorder()
+			request  = httptest.NewRequest("GET", "/revocations", nil)
+		)
+
+		router.ServeHTTP(response, request)
+		assert.Equal(http.StatusOK, response.Code)
+		assert.Equal("revocations", response.Body.String())
+	})
+
+	t.Run("openid-configuration", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			response = httptest.NewRecorder()
+			request  = httptest.NewRequest("GET", "/.well-known/openid-configuration", nil)
+		)
+
+		router.ServeHTTP(response, request)
+		assert.Equal(http.StatusOK, response.Code)
+		assert.Equal("discovery", response.Body.String())
+	})
+
+	t.Run("secrets", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			response = httptest.NewRecorder()
+			request  = httptest.NewRequest("GET", "/secrets/test", nil)
+		)
+
+		router.ServeHTTP(response, request)
+		assert.Equal(http.StatusOK, response.Code)
+		assert.Equal("secret test", response.Body.String())
+	})
+
+	t.Run("jwks.json", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			response = httptest.NewRecorder()
+			request  = httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+		)
+
+		router.ServeHTTP(response, request)
+		assert.Equal(http.StatusOK, response.Code)
+		assert.Equal(key.ContentTypeJWKS, response.Header().Get("Content-Type"))
+		assert.Equal("jwks", response.Body.String())
 	})
 
 	t.Run("Default", func(t *testing.T) {
@@ -82,6 +167,32 @@ func TestBuildKeyRoutes(t *testing.T) {
 		assert.Equal("jwk", response.Body.String())
 	})
 
+	t.Run("key.cose", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			response = httptest.NewRecorder()
+			request  = httptest.NewRequest("GET", "/keys/test/key.cose", nil)
+		)
+
+		router.ServeHTTP(response, request)
+		assert.Equal(http.StatusOK, response.Code)
+		assert.Equal(key.ContentTypeCOSEKey, response.Header().Get("Content-Type"))
+		assert.Equal("cose", response.Body.String())
+	})
+
+	t.Run("key.paserk", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			response = httptest.NewRecorder()
+			request  = httptest.NewRequest("GET", "/keys/test/key.paserk", nil)
+		)
+
+		router.ServeHTTP(response, request)
+		assert.Equal(http.StatusOK, response.Code)
+		assert.Equal(key.ContentTypePASERK, response.Header().Get("Content-Type"))
+		assert.Equal("paserk", response.Body.String())
+	})
+
 	t.Run("Accept", func(t *testing.T) {
 		t.Run(key.ContentTypePEM, func(t *testing.T) {
 			var (
@@ -110,5 +221,215 @@ func TestBuildKeyRoutes(t *testing.T) {
 			assert.Equal(key.ContentTypeJWK, response.Header().Get("Content-Type"))
 			assert.Equal("jwk", response.Body.String())
 		})
+
+		t.Run(key.ContentTypeCOSEKey, func(t *testing.T) {
+			var (
+				assert   = assert.New(t)
+				response = httptest.NewRecorder()
+				request  = httptest.NewRequest("GET", "/keys/test", nil)
+			)
+
+			request.Header.Set("Accept", key.ContentTypeCOSEKey)
+			router.ServeHTTP(response, request)
+			assert.Equal(http.StatusOK, response.Code)
+			assert.Equal(key.ContentTypeCOSEKey, response.Header().Get("Content-Type"))
+			assert.Equal("cose", response.Body.String())
+		})
+	})
+}
+
+func TestBuildIssuerRoutes(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		router = mux.NewRouter()
+
+		handler = func(name string) http.HandlerFunc {
+			return func(response http.ResponseWriter, request *http.Request) {
+				response.Write([]byte(name))
+			}
+		}
+	)
+
+	BuildIssuerRoutes(IssuerRoutesIn{
+		Router:         router,
+		Handler:        handler("issue"),
+		RefreshHandler: handler("refresh"),
+		PASETOHandler:  handler("paseto"),
+		TokenHandler:   handler("token"),
+		Profiles: []token.ProfileHandlers{
+			{
+				Name:               "devices",
+				IssueHandler:       handler("devices issue"),
+				RefreshHandler:     handler("devices refresh"),
+				PASETOIssueHandler: handler("devices paseto"),
+			},
+			{
+				Name:         "partners",
+				IssueHandler: handler("partners issue"),
+			},
+		},
+	})
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/issue", nil))
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal("issue", response.Body.String())
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/issue/paseto", nil))
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal("paseto", response.Body.String())
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("POST", "/refresh", nil))
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal("refresh", response.Body.String())
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("POST", "/token", nil))
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal("token", response.Body.String())
+
+	for _, record := range []struct {
+		method, path, expected string
+	}{
+		{"GET", "/issue/devices", "devices issue"},
+		{"GET", "/issue/devices/paseto", "devices paseto"},
+		{"POST", "/refresh/devices", "devices refresh"},
+		{"GET", "/issue/partners", "partners issue"},
+	} {
+		response = httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest(record.method, record.path, nil))
+		assert.Equal(http.StatusOK, response.Code)
+		assert.Equal(record.expected, response.Body.String())
+	}
+
+	// profiles without refresh tokens or PASETO tokens have no such routes
+	for _, record := range []struct {
+		method, path string
+	}{
+		{"GET", "/issue/partners/paseto"},
+		{"POST", "/refresh/partners"},
+		{"GET", "/issue/unknown"},
+	} {
+		response = httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest(record.method, record.path, nil))
+		assert.Equal(http.StatusNotFound, response.Code)
+	}
+
+	// without refresh tokens or oauth, there are no refresh or token routes
+	router = mux.NewRouter()
+	BuildIssuerRoutes(IssuerRoutesIn{
+		Router:  router,
+		Handler: handler("issue"),
+	})
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("POST", "/refresh", nil))
+	assert.Equal(http.StatusNotFound, response.Code)
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("POST", "/token", nil))
+	assert.Equal(http.StatusNotFound, response.Code)
+}
+
+func TestBuildClaimsRoutes(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		router = mux.NewRouter()
+
+		handler = func(name string) http.HandlerFunc {
+			return func(response http.ResponseWriter, request *http.Request) {
+				response.Write([]byte(name))
+			}
+		}
+	)
+
+	BuildClaimsRoutes(ClaimsRoutesIn{
+		Router:  router,
+		Handler: handler("claims"),
+		Profiles: []token.ProfileHandlers{
+			{Name: "devices", ClaimsHandler: handler("devices claims")},
+		},
+	})
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/claims", nil))
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal("claims", response.Body.String())
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/claims/devices", nil))
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal("devices claims", response.Body.String())
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/claims/unknown", nil))
+	assert.Equal(http.StatusNotFound, response.Code)
+}
+
+func TestBuildIntrospectRoutes(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		router = mux.NewRouter()
+
+		handler = http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.Write([]byte("introspect"))
+		})
+	)
+
+	BuildIntrospectRoutes(IntrospectRoutesIn{
+		Router:  router,
+		Handler: handler,
+	})
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("POST", "/introspect", nil))
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal("introspect", response.Body.String())
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/introspect", nil))
+	assert.Equal(http.StatusMethodNotAllowed, response.Code)
+}
+
+func TestBuildRevokeRoutes(t *testing.T) {
+	var (
+		router  = mux.NewRouter()
+		handler = func(name string) http.HandlerFunc {
+			return func(response http.ResponseWriter, request *http.Request) {
+				response.Write([]byte(name + mux.Vars(request)["id"]))
+			}
+		}
+	)
+
+	BuildRevokeRoutes(RevokeRoutesIn{
+		Router:        router,
+		HandlerRevoke: handler("revoke"),
+		HandlerRemove: handler("remove "),
+		HandlerList:   handler("list"),
 	})
+
+	testData := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{"POST", "/revocations", "revoke"},
+		{"GET", "/revocations", "list"},
+		{"DELETE", "/revocations/abc", "remove abc"},
+	}
+
+	for _, record := range testData {
+		t.Run(record.method, func(t *testing.T) {
+			var (
+				assert   = assert.New(t)
+				response = httptest.NewRecorder()
+			)
+
+			router.ServeHTTP(response, httptest.NewRequest(record.method, record.path, nil))
+			assert.Equal(http.StatusOK, response.Code)
+			assert.Equal(record.expected, response.Body.String())
+		})
+	}
 }
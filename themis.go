@@ -11,7 +11,9 @@ import (
 	"github.com/xmidt-org/sallust"
 	"github.com/xmidt-org/themis/v2/config"
 	"github.com/xmidt-org/themis/v2/key"
+	"github.com/xmidt-org/themis/v2/oauth"
 	"github.com/xmidt-org/themis/v2/random"
+	"github.com/xmidt-org/themis/v2/revoke"
 	"github.com/xmidt-org/themis/v2/token"
 	"github.com/xmidt-org/themis/v2/xhealth"
 	"github.com/xmidt-org/themis/v2/xhttp/xhttpclient"
@@ -46,6 +48,9 @@ func provideAppOptions(opts fx.Option) fx.Option {
 		fx.Provide(
 			config.ProvideViper,
 			token.Unmarshal("token"),
+			key.UnmarshalStore("keyStore"),
+			key.UnmarshalSecretOptions("keySecrets"),
+			token.UnmarshalDiscoveryOptions("discovery"),
 			xmetricshttp.Unmarshal("prometheus", promhttp.HandlerOpts{}),
 			candlelight.New,
 			func(u config.Unmarshaller) (candlelight.Config, error) {
@@ -59,6 +64,7 @@ func provideAppOptions(opts fx.Option) fx.Option {
 			sallust.WithLogger(),
 			provideMetrics(),
 			token.ProvideMetrics(),
+			key.ProvideMetrics(),
 			fx.Provide(
 				func(u config.Unmarshaller) (c sallust.Config, err error) {
 					err = u.UnmarshalKey("log", &c)
@@ -67,12 +73,30 @@ func provideAppOptions(opts fx.Option) fx.Option {
 				xhealth.Unmarshal("health"),
 				random.Provide,
 				key.Provide,
+				revoke.Unmarshal("revocation"),
+				fx.Annotated{
+					// revocations are pruned once every token they apply to has expired
+					Name:   "revocation_retention",
+					Target: token.MaxLifetime,
+				},
+				func(l *revoke.List) token.Revocations {
+					// avoid a non-nil interface holding a nil List when revocation is disabled
+					if l == nil {
+						return nil
+					}
+
+					return l
+				},
 				token.TokenFactory(),
+				oauth.Unmarshal("oauth"),
+				provideDiscoveryHandler,
 				provideServerChainFactory,
 				xhttpclient.Unmarshal{Key: "client"}.Provide,
 				xhttpserver.Unmarshal{Key: "servers.key", Optional: true}.Annotated(),
 				xhttpserver.Unmarshal{Key: "servers.issuer", Optional: true}.Annotated(),
 				xhttpserver.Unmarshal{Key: "servers.claims", Optional: true}.Annotated(),
+				xhttpserver.Unmarshal{Key: "servers.introspect", Optional: true}.Annotated(),
+				xhttpserver.Unmarshal{Key: "servers.revoke", Optional: true}.Annotated(),
 				xhttpserver.Unmarshal{Key: "servers.metrics", Optional: true}.Annotated(),
 				xhttpserver.Unmarshal{Key: "servers.health", Optional: true}.Annotated(),
 				xhttpserver.Unmarshal{Key: "servers.pprof", Optional: true}.Annotated(),
@@ -93,6 +117,8 @@ func provideAppOptions(opts fx.Option) fx.Option {
 				BuildKeyRoutes,
 				BuildIssuerRoutes,
 				BuildClaimsRoutes,
+				BuildIntrospectRoutes,
+				BuildRevokeRoutes,
 				BuildMetricsRoutes,
 				BuildHealthRoutes,
 				BuildPprofRoutes,
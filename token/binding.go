@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/xmidt-org/themis/v2/key"
+)
+
+const (
+	// ClaimConfirmation is the RFC 7800 confirmation claim, which binds a token to a key
+	ClaimConfirmation = "cnf"
+
+	// ConfirmationThumbprintS256 is the RFC 8705 confirmation method holding the x5t#S256
+	// thumbprint of the client certificate a token is bound to
+	ConfirmationThumbprintS256 = "x5t#S256"
+
+	// ConfirmationJWKThumbprint is the RFC 9449 confirmation method holding the RFC 7638
+	// thumbprint of the DPoP key a token is bound to
+	ConfirmationJWKThumbprint = "jkt"
+
+	// BindingPolicyIssue issues unbound tokens to clients that present no certificate
+	BindingPolicyIssue = "issue"
+
+	// BindingPolicyRefuse refuses to issue tokens to clients that present no certificate
+	BindingPolicyRefuse = "refuse"
+)
+
+var (
+	ErrInvalidBindingPolicy  = errors.New("the certificate binding policy must be either issue or refuse")
+	ErrNoClientCertificate   = errors.New("a client certificate is required")
+	ErrCertificateNotBound   = errors.New("the client certificate does not match the certificate the token is bound to")
+	ErrDPoPKeyNotBound       = errors.New("the DPoP proof key does not match the key the token is bound to")
+	ErrMalformedConfirmation = errors.New("the token's cnf claim is malformed")
+	ErrConfirmationClaim     = errors.New("the cnf claim can only be set from a proof of possession")
+)
+
+// CertificateBinding describes how tokens are bound to the client certificate presented when they
+// are issued, as described in RFC 8705.  A bound token carries a cnf claim with the x5t#S256 thumbprint
+// of the client's leaf certificate, so that verifiers can require the same certificate on their connections.
+type CertificateBinding struct {
+	// Unbound is the policy for requests that present no client certificate.  BindingPolicyIssue,
+	// the default, issues tokens without a cnf claim.  BindingPolicyRefuse rejects the request
+	// with a 403 status, unless the token is bound to a DPoP key instead.
+	Unbound string
+}
+
+// newConfirmationClaimBuilder creates the ClaimBuilder that adds the cnf claim for certificate
+// binding and DPoP.  If neither is configured, this function returns nil.
+func newConfirmationClaimBuilder(o Options) (ClaimBuilder, error) {
+	if o.CertificateBinding == nil && o.DPoP == nil {
+		return nil, nil
+	}
+
+	ccb := confirmationClaimBuilder{
+		certificate: o.CertificateBinding != nil,
+		dpop:        o.DPoP != nil,
+	}
+
+	if ccb.certificate {
+		switch o.CertificateBinding.Unbound {
+		case "", BindingPolicyIssue:
+		case BindingPolicyRefuse:
+			ccb.refuseUnbound = true
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrInvalidBindingPolicy, o.CertificateBinding.Unbound)
+		}
+	}
+
+	return ccb, nil
+}
+
+// confirmationClaimBuilder computes the cnf claim from the proof of possession in each request
+type confirmationClaimBuilder struct {
+	certificate   bool
+	refuseUnbound bool
+	dpop          bool
+}
+
+func (ccb confirmationClaimBuilder) AddClaims(_ context.Context, r *Request, target map[string]any) error {
+	// a cnf claim that didn't come from a proof of possession must never reach the token
+	delete(target, ClaimConfirmation)
+
+	cnf := make(map[string]any, 2)
+	if thumbprint, ok := leafThumbprint(r.TLS); ok && ccb.certificate {
+		cnf[ConfirmationThumbprintS256] = thumbprint
+	}
+
+	if ccb.dpop && len(r.DPoPThumbprint) > 0 {
+		cnf[ConfirmationJWKThumbprint] = r.DPoPThumbprint
+	}
+
+	if len(cnf) > 0 {
+		target[ClaimConfirmation] = cnf
+	} else if ccb.refuseUnbound {
+		return httpError{err: ErrNoClientCertificate, code: http.StatusForbidden}
+	}
+
+	return nil
+}
+
+// leafThumbprint returns the x5t#S256 thumbprint of the client's leaf certificate, if one was presented
+func leafThumbprint(state *tls.ConnectionState) (string, bool) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return "", false
+	}
+
+	return key.CertificateThumbprintS256(state.PeerCertificates[0]), true
+}
+
+// checkBinding verifies that a request presented each proof of possession that a token's claims are
+// bound to.  Unbound claims are always accepted.
+func checkBinding(claims map[string]any, r *Request) error {
+	cnf, ok := claims[ClaimConfirmation]
+	if !ok {
+		return nil
+	}
+
+	confirmation, _ := cnf.(map[string]any)
+	if len(confirmation) == 0 {
+		return ErrMalformedConfirmation
+	}
+
+	for method, value := range confirmation {
+		expected, _ := value.(string)
+		switch method {
+		case ConfirmationThumbprintS256:
+			if actual, ok := leafThumbprint(r.TLS); !ok {
+				return ErrNoClientCertificate
+			} else if actual != expected {
+				return ErrCertificateNotBound
+			}
+
+		case ConfirmationJWKThumbprint:
+			if len(r.DPoPThumbprint) == 0 {
+				return ErrNoDPoPProof
+			} else if r.DPoPThumbprint != expected {
+				return ErrDPoPKeyNotBound
+			}
+
+		default:
+			return fmt.Errorf("%w: unsupported confirmation method %s", ErrMalformedConfirmation, method)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/key"
+)
+
+// testPeerCertificate produces connection state for a client that presented a certificate.  Only
+// the raw DER matters to binding, since that's what the thumbprint is computed from.
+func testPeerCertificate(raw string) (*tls.ConnectionState, string) {
+	cert := &x509.Certificate{Raw: []byte(raw)}
+	return &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}, key.CertificateThumbprintS256(cert)
+}
+
+func testConfirmationClaimBuilder(t *testing.T) {
+	state, thumbprint := testPeerCertificate("device")
+
+	testData := []struct {
+		name     string
+		options  Options
+		state    *tls.ConnectionState
+		jkt      string
+		expected map[string]any
+		code     int
+	}{
+		{
+			name:     "Bound",
+			options:  Options{CertificateBinding: &CertificateBinding{}},
+			state:    state,
+			expected: map[string]any{ClaimConfirmation: map[string]any{ConfirmationThumbprintS256: thumbprint}},
+		},
+		{
+			name:     "BoundRefuse",
+			options:  Options{CertificateBinding: &CertificateBinding{Unbound: BindingPolicyRefuse}},
+			state:    state,
+			expected: map[string]any{ClaimConfirmation: map[string]any{ConfirmationThumbprintS256: thumbprint}},
+		},
+		{
+			name:     "NoTLS",
+			options:  Options{CertificateBinding: &CertificateBinding{Unbound: BindingPolicyIssue}},
+			expected: map[string]any{},
+		},
+		{
+			name:     "NoCertificate",
+			options:  Options{CertificateBinding: &CertificateBinding{}},
+			state:    &tls.ConnectionState{},
+			expected: map[string]any{},
+		},
+		{
+			name:    "NoTLSRefuse",
+			options: Options{CertificateBinding: &CertificateBinding{Unbound: BindingPolicyRefuse}},
+			code:    http.StatusForbidden,
+		},
+		{
+			name:     "DPoP",
+			options:  Options{DPoP: &DPoP{}},
+			state:    state,
+			jkt:      "thumbprint",
+			expected: map[string]any{ClaimConfirmation: map[string]any{ConfirmationJWKThumbprint: "thumbprint"}},
+		},
+		{
+			name:     "DPoPRefuseNoCertificate",
+			options:  Options{CertificateBinding: &CertificateBinding{Unbound: BindingPolicyRefuse}, DPoP: &DPoP{}},
+			jkt:      "thumbprint",
+			expected: map[string]any{ClaimConfirmation: map[string]any{ConfirmationJWKThumbprint: "thumbprint"}},
+		},
+		{
+			name:    "DPoPAndCertificate",
+			options: Options{CertificateBinding: &CertificateBinding{}, DPoP: &DPoP{}},
+			state:   state,
+			jkt:     "thumbprint",
+			expected: map[string]any{ClaimConfirmation: map[string]any{
+				ConfirmationThumbprintS256: thumbprint,
+				ConfirmationJWKThumbprint:  "thumbprint",
+			}},
+		},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+			)
+
+			cb, err := newConfirmationClaimBuilder(record.options)
+			require.NoError(err)
+			require.NotNil(cb)
+
+			r := NewRequest()
+			r.TLS = record.state
+			r.DPoPThumbprint = record.jkt
+
+			// a cnf claim from anywhere other than a proof of possession is never issued
+			target := map[string]any{ClaimConfirmation: "forged"}
+			err = cb.AddClaims(context.Background(), r, target)
+			if record.code > 0 {
+				require.ErrorIs(err, ErrNoClientCertificate)
+				var sc interface{ StatusCode() int }
+				require.ErrorAs(err, &sc)
+				assert.Equal(record.code, sc.StatusCode())
+				return
+			}
+
+			require.NoError(err)
+			assert.Equal(record.expected, target)
+		})
+	}
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		cb, err := newConfirmationClaimBuilder(Options{})
+		assert.Nil(t, cb)
+		assert.NoError(t, err)
+	})
+
+	t.Run("InvalidPolicy", func(t *testing.T) {
+		cb, err := newConfirmationClaimBuilder(Options{CertificateBinding: &CertificateBinding{Unbound: "sometimes"}})
+		assert.Nil(t, cb)
+		assert.ErrorIs(t, err, ErrInvalidBindingPolicy)
+	})
+}
+
+func testCheckBinding(t *testing.T) {
+	var (
+		state, thumbprint = testPeerCertificate("device")
+		other, _          = testPeerCertificate("other")
+		bound             = map[string]any{ClaimConfirmation: map[string]any{ConfirmationThumbprintS256: thumbprint}}
+		dpopBound         = map[string]any{ClaimConfirmation: map[string]any{ConfirmationJWKThumbprint: "thumbprint"}}
+	)
+
+	testData := []struct {
+		name     string
+		claims   map[string]any
+		request  Request
+		expected error
+	}{
+		{"Unbound", map[string]any{}, Request{}, nil},
+		{"Bound", bound, Request{TLS: state}, nil},
+		{"NoCertificate", bound, Request{}, ErrNoClientCertificate},
+		{"OtherCertificate", bound, Request{TLS: other}, ErrCertificateNotBound},
+		{"DPoPBound", dpopBound, Request{DPoPThumbprint: "thumbprint"}, nil},
+		{"NoDPoPProof", dpopBound, Request{TLS: state}, ErrNoDPoPProof},
+		{"OtherDPoPKey", dpopBound, Request{DPoPThumbprint: "other"}, ErrDPoPKeyNotBound},
+		{"Malformed", map[string]any{ClaimConfirmation: "junk"}, Request{TLS: state}, ErrMalformedConfirmation},
+		{"UnsupportedMethod", map[string]any{ClaimConfirmation: map[string]any{"jwk": "junk"}}, Request{TLS: state}, ErrMalformedConfirmation},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			assert.ErrorIs(t, checkBinding(record.claims, &record.request), record.expected)
+		})
+	}
+}
+
+func TestCertificateBinding(t *testing.T) {
+	t.Run("ClaimBuilder", testConfirmationClaimBuilder)
+	t.Run("CheckBinding", testCheckBinding)
+}
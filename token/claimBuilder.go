@@ -149,7 +149,15 @@ func (rc *remoteClaimBuilder) AddClaims(ctx context.Context, r *Request, target
 		r.Logger.Info("successful response from remote claims endpoint")
 		rc.apiDuration.With(prometheus.Labels{CodeLabelKey: strconv.Itoa(http.StatusOK), OutcomeLabelKey: SuccessOutcome}).Observe(duration)
 		rc.apiResults.With(prometheus.Labels{CodeLabelKey: strconv.Itoa(http.StatusOK), OutcomeLabelKey: SuccessOutcome, ReasonLabelKey: ""}).Add(1)
-		maps.Copy(target, result.(map[string]any))
+		for name, value := range result.(map[string]any) {
+			if name == ClaimConfirmation {
+				// a cnf claim must come from a proof of possession, never from the remote system
+				r.Logger.Warn("ignoring the cnf claim from the remote claims endpoint")
+				continue
+			}
+
+			target[name] = value
+		}
 	} else if errors.As(err, &respErr) { // Handle response related errors.
 		code := respErr.StatusCode
 		apiDuration := rc.apiDuration.MustCurryWith(prometheus.Labels{CodeLabelKey: strconv.Itoa(code)})
@@ -325,6 +333,7 @@ func (cb *clientCertificateClaimBuilder) AddClaims(_ context.Context, r *Request
 		trust = cb.trust.NoCertificates
 		target[ClaimTrust] = trust
 		trustReason = NoCertificatesReason
+		r.TrustReason = trustReason
 		trustCounter.With(prometheus.Labels{
 			TrustLabelKey:    strconv.Itoa(trust),
 			ReasonLabelKey:   trustReason,
@@ -435,6 +444,7 @@ func (cb *clientCertificateClaimBuilder) AddClaims(_ context.Context, r *Request
 				trust = cb.trust.UntrustedCertIssuerCN
 				target[ClaimTrust] = trust
 				trustReason = UntrustedCertIssuerCNReason
+				r.TrustReason = trustReason
 				trustCounter.With(prometheus.Labels{
 					TrustLabelKey:    strconv.Itoa(trust),
 					IssuerCNLabelKey: issuerCN,
@@ -459,6 +469,7 @@ func (cb *clientCertificateClaimBuilder) AddClaims(_ context.Context, r *Request
 	issuerCN = strings.ToValidUTF8(issuerCN, "")
 	// take the highest, non-Trusted level
 	target[ClaimTrust] = trust
+	r.TrustReason = trustReason
 	trustCounter.With(prometheus.Labels{
 		TrustLabelKey:    strconv.Itoa(trust),
 		IssuerCNLabelKey: issuerCN,
@@ -481,21 +492,7 @@ func NewClaimBuilders(n random.Noncer, remoteEndpoint endpoint.Endpoint, o Optio
 	}
 
 	builders = append(builders, staticClaimBuilder(staticClaims))
-	if o.Nonce && n != nil {
-		builders = append(builders, nonceClaimBuilder{n: n})
-	}
-
-	if !o.DisableTime {
-		builders = append(
-			builders,
-			&timeClaimBuilder{
-				now:              time.Now,
-				duration:         o.Duration,
-				disableNotBefore: o.DisableNotBefore,
-				notBeforeDelta:   o.NotBeforeDelta,
-			})
-	}
-
+	builders = append(builders, newIssueClaimBuilders(n, o)...)
 	cb, err := newClientCertificateClaimBuilder(o.ClientCertificates, o.PartnerID.Claim, trustCounter)
 	if err != nil {
 		return nil, err
@@ -508,6 +505,19 @@ func NewClaimBuilders(n random.Noncer, remoteEndpoint endpoint.Endpoint, o Optio
 		)
 	}
 
+	policy, err := newTrustPolicy(o)
+	if err != nil {
+		return nil, err
+	} else if policy != nil {
+		// refused requests are rejected before any remote claims are requested
+		builders = append(builders, ClaimBuilderFunc(policy.checkTrust))
+	}
+
+	confirmation, err := newConfirmationClaimBuilder(o)
+	if err != nil {
+		return nil, err
+	}
+
 	if o.Remote != nil && remoteEndpoint != nil {
 		metadata, err := getStaticValues(o.Metadata)
 		if err != nil {
@@ -522,7 +532,47 @@ func NewClaimBuilders(n random.Noncer, remoteEndpoint endpoint.Endpoint, o Optio
 		builders = append(builders, remoteClaimBuilder)
 	}
 
-	return builders, err
+	// the policy runs after the other claim builders, so that its claims override theirs
+	if policy != nil {
+		builders = append(builders, policy)
+	}
+
+	// transforms run after everything else that contributes claims, so that they see every claim
+	// and can override the policy's
+	transforms, err := newTransformClaimBuilder(o)
+	if err != nil {
+		return nil, err
+	} else if transforms != nil {
+		builders = append(builders, transforms)
+	}
+
+	// the confirmation runs last, so that nothing can replace the cnf claim it computes
+	if confirmation != nil {
+		builders = append(builders, confirmation)
+	}
+
+	return builders, nil
+}
+
+// newIssueClaimBuilders creates the builders for the claims unique to each issued token, i.e. the
+// nonce and the time-based claims.
+func newIssueClaimBuilders(n random.Noncer, o Options) (builders ClaimBuilders) {
+	if o.Nonce && n != nil {
+		builders = append(builders, nonceClaimBuilder{n: n})
+	}
+
+	if !o.DisableTime {
+		builders = append(
+			builders,
+			&timeClaimBuilder{
+				now:              time.Now,
+				duration:         o.Duration,
+				disableNotBefore: o.DisableNotBefore,
+				notBeforeDelta:   o.NotBeforeDelta,
+			})
+	}
+
+	return
 }
 
 func getStaticValues(vals []Value) (map[string]any, error) {
@@ -662,6 +662,175 @@ func (suite *NewClaimBuildersTestSuite) TestMinimum() {
 	)
 }
 
+func (suite *NewClaimBuildersTestSuite) TestCertificateBinding() {
+	trustCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: trustMetricName,
+			Help: trustMetricName,
+		},
+		[]string{
+			TrustLabelKey,
+			IssuerCNLabelKey,
+			PartnerIDLabelKey,
+			ReasonLabelKey},
+	)
+
+	builder, err := NewClaimBuilders(suite.noncer, nil, Options{
+		DisableTime:        true,
+		PartnerID:          &PartnerID{},
+		CertificateBinding: &CertificateBinding{Unbound: BindingPolicyRefuse},
+	},
+		false,
+		trustCounter,
+		nil,
+		nil,
+	)
+
+	suite.Require().NoError(err)
+	state, thumbprint := testPeerCertificate("device")
+	actual := make(map[string]any)
+	suite.NoError(
+		builder.AddClaims(context.Background(), &Request{TLS: state, Logger: sallust.Default(), Claims: map[string]any{}}, actual),
+	)
+
+	suite.Equal(map[string]any{ConfirmationThumbprintS256: thumbprint}, actual[ClaimConfirmation])
+	suite.ErrorIs(
+		builder.AddClaims(context.Background(), &Request{TLS: &tls.ConnectionState{}, Logger: sallust.Default(), Claims: map[string]any{}}, make(map[string]any)),
+		ErrNoClientCertificate,
+	)
+
+	_, err = NewClaimBuilders(suite.noncer, nil, Options{PartnerID: &PartnerID{}, CertificateBinding: &CertificateBinding{Unbound: "nosuch"}}, false, trustCounter, nil, nil)
+	suite.ErrorIs(err, ErrInvalidBindingPolicy)
+}
+
+func (suite *NewClaimBuildersTestSuite) TestTrustPolicy() {
+	trustCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: trustMetricName,
+			Help: trustMetricName,
+		},
+		[]string{
+			TrustLabelKey,
+			IssuerCNLabelKey,
+			PartnerIDLabelKey,
+			ReasonLabelKey},
+	)
+
+	builder, err := NewClaimBuilders(suite.noncer, nil, Options{
+		Duration:  time.Hour,
+		PartnerID: &PartnerID{},
+		Claims:    []Value{{Key: "capabilities", Value: "all"}},
+		TrustPolicy: &TrustPolicy{
+			Rules: []TrustRule{
+				{Reasons: []string{ExpiredUntrustedReason}, Refuse: true},
+				{Reasons: []string{NoCertificatesReason}, Duration: time.Minute, Claims: []Value{{Key: "capabilities", Value: "none"}}},
+			},
+		},
+	},
+		false,
+		trustCounter,
+		nil,
+		nil,
+	)
+
+	suite.Require().NoError(err)
+
+	// the trust reason computed from the request selects the rule
+	r := &Request{Logger: sallust.Default(), Claims: map[string]any{}}
+	actual := make(map[string]any)
+	suite.Require().NoError(builder.AddClaims(context.Background(), r, actual))
+	suite.Equal(NoCertificatesReason, r.TrustReason)
+	suite.Equal(json.RawMessage(`"none"`), actual["capabilities"])
+	suite.Equal(actual["iat"].(int64)+60, actual["exp"])
+
+	state, _ := testPeerCertificate("device")
+	r = &Request{TLS: state, Logger: sallust.Default(), Claims: map[string]any{}}
+	suite.ErrorIs(builder.AddClaims(context.Background(), r, make(map[string]any)), ErrTrustRefused)
+	suite.Equal(ExpiredUntrustedReason, r.TrustReason)
+
+	_, err = NewClaimBuilders(suite.noncer, nil, Options{PartnerID: &PartnerID{}, TrustPolicy: &TrustPolicy{Rules: []TrustRule{{Reasons: []string{"nosuch"}}}}}, false, trustCounter, nil, nil)
+	suite.ErrorIs(err, ErrUnknownTrustReason)
+}
+
+func (suite *NewClaimBuildersTestSuite) TestRemoteConfirmation() {
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, _ *http.Request) {
+		response.Header().Set("Content-Type", "application/json")
+		response.Write([]byte(`{"remote": "value", "cnf": {"jkt": "forged"}}`))
+	}))
+
+	defer server.Close()
+	trustCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: trustMetricName,
+			Help: trustMetricName,
+		},
+		[]string{
+			TrustLabelKey,
+			IssuerCNLabelKey,
+			PartnerIDLabelKey,
+			ReasonLabelKey},
+	)
+
+	remoteResults := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "testAPIResultsCounter",
+			Help: "testAPIResultsCounter",
+		},
+		[]string{
+			EndpointLabelKey,
+			MethodLabelKey,
+			CodeLabelKey,
+			OutcomeLabelKey,
+			ReasonLabelKey},
+	)
+
+	remoteDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "testAPIDurationCounter",
+			Help: "testAPIDurationCounter",
+		},
+		[]string{
+			EndpointLabelKey,
+			MethodLabelKey,
+			CodeLabelKey,
+			OutcomeLabelKey},
+	)
+
+	state, thumbprint := testPeerCertificate("device")
+	testData := []struct {
+		name     string
+		binding  *CertificateBinding
+		expected any
+	}{
+		{"Unbound", nil, nil},
+		{"Bound", &CertificateBinding{}, map[string]any{ConfirmationThumbprintS256: thumbprint}},
+	}
+
+	for _, record := range testData {
+		suite.Run(record.name, func() {
+			options := Options{
+				DisableTime:        true,
+				PartnerID:          &PartnerID{},
+				CertificateBinding: record.binding,
+				Remote:             &RemoteClaims{URL: server.URL},
+			}
+
+			endpoint, err := newRemoteEndpoint(nil, options.Remote)
+			suite.Require().NoError(err)
+			builder, err := NewClaimBuilders(suite.noncer, endpoint, options, false, trustCounter, remoteResults, remoteDuration)
+			suite.Require().NoError(err)
+
+			actual := make(map[string]any)
+			suite.Require().NoError(
+				builder.AddClaims(context.Background(), &Request{TLS: state, Logger: sallust.Default(), Claims: map[string]any{}}, actual),
+			)
+
+			suite.Equal("value", actual["remote"])
+			suite.Equal(record.expected, actual[ClaimConfirmation])
+		})
+	}
+}
+
 func (suite *NewClaimBuildersTestSuite) TestMissingKey() {
 	suite.Run("Claims", suite.testClaimsMissingKey)
 	suite.Run("Metadata", suite.testMetadataMissingKey)
@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/xmidt-org/themis/v2/cose"
+)
+
+const (
+	// ContentTypeCWT is the content type of tokens issued as RFC 8392 CBOR Web Tokens.  Clients
+	// request a CWT by sending this type in the Accept header.
+	ContentTypeCWT = "application/cwt"
+
+	// cwtPrefix is the first byte of every CWT, which is the CBOR tag of a COSE_Sign1 message
+	cwtPrefix = 0xc0 | cose.TagSign1
+)
+
+var (
+	ErrInvalidClaimLabel = errors.New("invalid CWT claim label")
+	ErrUnsupportedCWT    = errors.New("CWTs cannot be issued with this configuration")
+)
+
+// cwtLabels are the integer labels registered by RFC 8392 and RFC 8747 for the standard claims
+var cwtLabels = map[string]int{
+	"iss":             1,
+	"sub":             2,
+	"aud":             3,
+	"exp":             4,
+	"nbf":             5,
+	"iat":             6,
+	"jti":             7,
+	ClaimConfirmation: 8,
+}
+
+// ClaimLabel maps a claim name onto the integer label used for it in CWTs
+type ClaimLabel struct {
+	// Claim is the name of the claim, as it appears in JWTs
+	Claim string
+
+	// Label is the CWT claim key
+	Label int
+}
+
+// CWT describes how tokens are issued as CBOR Web Tokens, as described in RFC 8392.  A CWT carries
+// the same claims as a JWT, signed with COSE_Sign1 using the same key.  Refresh tokens, and tokens
+// issued at the OAuth token endpoint, are always JWTs.
+type CWT struct {
+	// Default indicates whether CWTs are issued to clients whose Accept header asks for neither a
+	// CWT nor a JWT.  If false, only clients that accept ContentTypeCWT receive CWTs.  At most one
+	// of CWT and PASETO can be the default format.
+	Default bool
+
+	// Labels are the integer labels for claims, in addition to or replacing the standard labels,
+	// e.g. 1 for iss.  Claims without a label are keyed by their names.
+	Labels []ClaimLabel
+}
+
+// cwtEncoder produces the CBOR payload of CWTs
+type cwtEncoder struct {
+	labels map[string]int
+}
+
+func newCWTEncoder(c CWT, o Options) (*cwtEncoder, error) {
+	if _, ok := cose.Algorithm(o.Alg); !ok {
+		return nil, fmt.Errorf("%w: the %s algorithm is not supported", ErrUnsupportedCWT, o.Alg)
+	}
+
+	if o.Encryption != nil {
+		return nil, fmt.Errorf("%w: tokens cannot be both encrypted and issued as CWTs", ErrUnsupportedCWT)
+	}
+
+	ce := &cwtEncoder{labels: make(map[string]int, len(cwtLabels)+len(c.Labels))}
+	for claim, label := range cwtLabels {
+		ce.labels[claim] = label
+	}
+
+	for _, cl := range c.Labels {
+		if len(cl.Claim) == 0 {
+			return nil, fmt.Errorf("%w: a claim name is required for label %d", ErrInvalidClaimLabel, cl.Label)
+		}
+
+		ce.labels[cl.Claim] = cl.Label
+	}
+
+	claims := make(map[int]string, len(ce.labels))
+	for claim, label := range ce.labels {
+		if other, ok := claims[label]; ok {
+			return nil, fmt.Errorf("%w: %s and %s both have the label %d", ErrInvalidClaimLabel, claim, other, label)
+		}
+
+		claims[label] = claim
+	}
+
+	return ce, nil
+}
+
+// payload produces the CBOR claims set of a CWT.  The claims are first passed through JSON, so that
+// a CWT carries exactly the values that the equivalent JWT would.
+func (ce *cwtEncoder) payload(claims map[string]any) ([]byte, error) {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&normalized); err != nil {
+		return nil, err
+	}
+
+	payload := make(map[any]any, len(normalized))
+	for name, value := range normalized {
+		label, ok := ce.labels[name]
+		if !ok {
+			payload[name] = value
+			continue
+		}
+
+		// the jti claim becomes the cti claim, which is a byte string
+		if s, ok := value.(string); ok && name == "jti" {
+			value = []byte(s)
+		}
+
+		payload[label] = value
+	}
+
+	return cose.Marshal(payload)
+}
+
+// isCWT tests if a token is a CWT rather than the compact serialization of a JWT
+func isCWT(token string) bool {
+	return len(token) > 0 && token[0] == cwtPrefix
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/cose"
+	"github.com/xmidt-org/themis/v2/key"
+)
+
+func testFactoryCWT(t *testing.T) {
+	testData := []struct {
+		alg        string
+		descriptor key.Descriptor
+	}{
+		{"ES256", key.Descriptor{Kid: "test", Type: key.KeyTypeECDSA, Bits: 256}},
+		{"PS256", key.Descriptor{Kid: "test", Type: key.KeyTypeRSA, Bits: 2048}},
+		{AlgEdDSA, key.Descriptor{Kid: "test"}},
+	}
+
+	for _, record := range testData {
+		t.Run(record.alg, func(t *testing.T) {
+			var (
+				assert   = assert.New(t)
+				require  = require.New(t)
+				registry = key.NewRegistry(rand.Reader)
+			)
+
+			f, err := NewFactory(
+				Options{
+					Alg: record.alg,
+					Key: record.descriptor,
+					CWT: &CWT{
+						Labels: []ClaimLabel{{Claim: "mac", Label: -70000}},
+					},
+				},
+				ClaimBuilders{requestClaimBuilder{}},
+				registry,
+			)
+
+			require.NoError(err)
+			r := NewRequest()
+			r.Format = FormatCWT
+			r.Claims["iss"] = "themis"
+			r.Claims["exp"] = int64(4102444800)
+			r.Claims["jti"] = "nonce"
+			r.Claims["mac"] = "112233445566"
+			r.Claims["capabilities"] = []string{"x1:issuer:test:.*:all"}
+			r.Claims[ClaimConfirmation] = map[string]any{ConfirmationJWKThumbprint: "thumbprint"}
+
+			token, err := f.NewToken(context.Background(), r)
+			require.NoError(err)
+			assert.True(isCWT(token))
+
+			pair, ok := registry.Get("test")
+			require.True(ok)
+			payload, alg, err := cose.Verify1([]byte(token), pair.Signer().Public())
+			require.NoError(err)
+			assert.Equal(record.alg, alg)
+
+			claims, err := cose.Unmarshal(payload)
+			require.NoError(err)
+			assert.Equal(
+				map[any]any{
+					int64(1):       "themis",
+					int64(4):       int64(4102444800),
+					int64(7):       []byte("nonce"),
+					int64(8):       map[any]any{ConfirmationJWKThumbprint: "thumbprint"},
+					int64(-70000):  "112233445566",
+					"capabilities": []any{"x1:issuer:test:.*:all"},
+				},
+				claims,
+			)
+
+			response := httptest.NewRecorder()
+			require.NoError(EncodeIssueResponse(context.Background(), response, token))
+			assert.Equal(ContentTypeCWT, response.Header().Get("Content-Type"))
+			assert.Equal(token, response.Body.String())
+
+			// the same factory issues JWTs to requests that do not ask for a CWT
+			r.Format = FormatJWT
+			token, err = f.NewToken(context.Background(), r)
+			require.NoError(err)
+			assert.False(isCWT(token))
+
+			verified, err := NewVerifier(registry, record.alg).Verify(token)
+			require.NoError(err)
+			assert.Equal("112233445566", verified["mac"])
+		})
+	}
+}
+
+func testFactoryCWTInvalid(t *testing.T) {
+	testData := []struct {
+		name     string
+		options  Options
+		expected error
+	}{
+		{"Symmetric", Options{Alg: "HS256", CWT: &CWT{}}, ErrUnsupportedCWT},
+		{"Encryption", Options{CWT: &CWT{}, Encryption: &Encryption{}}, ErrUnsupportedCWT},
+		{"NoClaim", Options{CWT: &CWT{Labels: []ClaimLabel{{Label: 100}}}}, ErrInvalidClaimLabel},
+		{"DuplicateLabel", Options{CWT: &CWT{Labels: []ClaimLabel{{Claim: "mac", Label: 1}}}}, ErrInvalidClaimLabel},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			f, err := NewFactory(record.options, ClaimBuilders{}, key.NewRegistry(rand.Reader))
+			assert.Nil(t, f)
+			assert.ErrorIs(t, err, record.expected)
+		})
+	}
+
+	t.Run("RelabeledStandardClaim", func(t *testing.T) {
+		f, err := NewFactory(
+			Options{CWT: &CWT{Labels: []ClaimLabel{{Claim: "mac", Label: 1}, {Claim: "iss", Label: 100}}}},
+			ClaimBuilders{},
+			key.NewRegistry(rand.Reader),
+		)
+
+		assert.NotNil(t, f)
+		assert.NoError(t, err)
+	})
+}
+
+func TestFactoryCWT(t *testing.T) {
+	t.Run("Success", testFactoryCWT)
+	t.Run("Invalid", testFactoryCWTInvalid)
+}
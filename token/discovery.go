@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/xmidt-org/themis/v2/config"
+)
+
+const (
+	// DefaultDiscoveryMaxAge is the default Cache-Control max-age for discovery documents
+	DefaultDiscoveryMaxAge = 5 * time.Minute
+)
+
+var (
+	ErrNoJWKSURL       = errors.New("the discovery document requires a JWKS URL")
+	ErrInvalidIssuer   = errors.New("the configured iss claim must be a string to be advertised")
+	ErrInvalidEndpoint = errors.New("invalid discovery endpoint URL")
+)
+
+// DiscoveryOptions holds the externally configured portion of the OpenID Connect discovery document
+type DiscoveryOptions struct {
+	// KeysURL is the external base URL of the keys server, e.g. https://keys.example.com.  If unset,
+	// the URL is derived from each discovery request and the keys server's configured address.
+	KeysURL string
+
+	// IssuerURL is the external base URL of the issuer server.  If unset, the URL is derived from
+	// each discovery request and the issuer server's configured address.
+	IssuerURL string
+
+	// ClaimsURL is the external base URL of the claims server.  If unset, the URL is derived from
+	// each discovery request and the claims server's configured address.
+	ClaimsURL string
+}
+
+// UnmarshalDiscoveryOptions returns an uber/fx style provider that reads the DiscoveryOptions at the given
+// configuration key.  If the key is not set, the zero value is returned and every URL is derived.
+func UnmarshalDiscoveryOptions(configKey string) func(config.Unmarshaller) (DiscoveryOptions, error) {
+	return func(u config.Unmarshaller) (DiscoveryOptions, error) {
+		var o DiscoveryOptions
+		if !u.IsSet(configKey) {
+			return o, nil
+		}
+
+		err := u.UnmarshalKey(configKey, &o)
+		return o, err
+	}
+}
+
+// DiscoveryEndpoints are the URLs of the endpoints advertised in the discovery document.  Each URL may
+// omit its host, e.g. http://:6501/issue, in which case the host from the discovery request is used
+// with the URL's port.  A URL with neither host nor port, e.g. http:///issue, uses the request's host
+// and port.  This allows the same configuration to work behind any hostname.
+type DiscoveryEndpoints struct {
+	// JWKS is the URL of the JSON Web Key Set.  This field is required.
+	JWKS string
+
+	// Issue is the URL of the token issue endpoint.  If unset, it is not advertised.
+	Issue string
+
+	// Claims is the URL of the claims endpoint.  If unset, it is not advertised.
+	Claims string
+}
+
+// Discovery is the OpenID Connect discovery document for a token factory.  Themis does not implement
+// the OpenID Connect flows, so only the metadata verifiers need is present, along with the themis
+// issue and claims endpoints.
+type Discovery struct {
+	Issuer                           string   `json:"issuer,omitempty"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	IssueEndpoint                    string   `json:"issue_endpoint,omitempty"`
+	ClaimsEndpoint                   string   `json:"claims_endpoint,omitempty"`
+
+	// CertificateBoundAccessTokens is the RFC 8705 indicator that tokens are bound to client certificates
+	CertificateBoundAccessTokens bool `json:"tls_client_certificate_bound_access_tokens,omitempty"`
+
+	// DPoPSigningAlgValuesSupported are the RFC 9449 DPoP proof algorithms accepted, if DPoP is enabled
+	DPoPSigningAlgValuesSupported []string `json:"dpop_signing_alg_values_supported,omitempty"`
+}
+
+// staticIssuer returns the statically configured iss claim, if there is one.  An iss claim taken
+// from HTTP requests varies from token to token and so cannot be advertised.
+func staticIssuer(o Options) (string, error) {
+	var issuer string
+	for _, v := range o.Claims {
+		if v.Key != "iss" || !v.IsStatic() {
+			continue
+		}
+
+		raw, err := v.RawMessage()
+		if err != nil {
+			return "", err
+		}
+
+		if err := json.Unmarshal(raw, &issuer); err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidIssuer, raw)
+		}
+	}
+
+	return issuer, nil
+}
+
+// discoveryURL is an advertised URL, possibly without a host
+type discoveryURL struct {
+	url *url.URL
+}
+
+func parseDiscoveryURL(v string) (discoveryURL, error) {
+	if len(v) == 0 {
+		return discoveryURL{}, nil
+	}
+
+	u, err := url.Parse(v)
+	if err != nil {
+		return discoveryURL{}, fmt.Errorf("%w: %s", ErrInvalidEndpoint, err)
+	} else if len(u.Scheme) == 0 {
+		return discoveryURL{}, fmt.Errorf("%w: %s has no scheme", ErrInvalidEndpoint, v)
+	}
+
+	return discoveryURL{url: u}, nil
+}
+
+// resolve produces the advertised URL for a discovery request
+func (du discoveryURL) resolve(request *http.Request) string {
+	if du.url == nil {
+		return ""
+	} else if len(du.url.Hostname()) > 0 {
+		return du.url.String()
+	}
+
+	resolved := *du.url
+	resolved.Host = request.Host
+	if port := du.url.Port(); len(port) > 0 {
+		requestHost := url.URL{Host: request.Host}
+		resolved.Host = net.JoinHostPort(requestHost.Hostname(), port)
+	}
+
+	return resolved.String()
+}
+
+type DiscoveryHandler http.Handler
+
+// NewDiscoveryHandler creates a handler that serves the OpenID Connect discovery document for the
+// token factory described by a set of Options.  The document is computed from configuration once,
+// save for any endpoint hosts that are taken from each request.
+func NewDiscoveryHandler(o Options, e DiscoveryEndpoints, maxAge time.Duration) (DiscoveryHandler, error) {
+	issuer, err := staticIssuer(o)
+	if err != nil {
+		return nil, err
+	}
+
+	alg := o.Alg
+	if len(alg) == 0 {
+		alg = DefaultAlg
+	}
+
+	if len(e.JWKS) == 0 {
+		return nil, ErrNoJWKSURL
+	}
+
+	jwksURL, err := parseDiscoveryURL(e.JWKS)
+	if err != nil {
+		return nil, err
+	}
+
+	issueURL, err := parseDiscoveryURL(e.Issue)
+	if err != nil {
+		return nil, err
+	}
+
+	claimsURL, err := parseDiscoveryURL(e.Claims)
+	if err != nil {
+		return nil, err
+	}
+
+	var dpopAlgValues []string
+	if o.DPoP != nil {
+		dpopAlgValues = o.DPoP.Algs
+		if len(dpopAlgValues) == 0 {
+			dpopAlgValues = dpopAlgs
+		}
+	}
+
+	cacheControl := fmt.Sprintf("public, max-age=%d", int64(maxAge/time.Second))
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		data, err := json.Marshal(Discovery{
+			Issuer:                           issuer,
+			JWKSURI:                          jwksURL.resolve(request),
+			IDTokenSigningAlgValuesSupported: []string{alg},
+			IssueEndpoint:                    issueURL.resolve(request),
+			ClaimsEndpoint:                   claimsURL.resolve(request),
+			CertificateBoundAccessTokens:     o.CertificateBinding != nil,
+			DPoPSigningAlgValuesSupported:    dpopAlgValues,
+		})
+
+		if err != nil {
+			response.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		response.Header().Set("Content-Type", "application/json")
+		response.Header().Set("Cache-Control", cacheControl)
+		_, _ = response.Write(data)
+	}), nil
+}
@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/config"
+)
+
+func testDiscovery(t *testing.T, h DiscoveryHandler, host string) Discovery {
+	var (
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("GET", "/.well-known/openid-configuration", nil)
+	)
+
+	request.Host = host
+	h.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=300", response.Header().Get("Cache-Control"))
+
+	var d Discovery
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &d))
+	return d
+}
+
+func TestNewDiscoveryHandler(t *testing.T) {
+	t.Run("ExternalURLs", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+		)
+
+		h, err := NewDiscoveryHandler(
+			Options{
+				Alg: "ES256",
+				Claims: []Value{
+					{Key: "iss", Value: "https://issuer.example.com"},
+					{Key: "aud", Value: "test"},
+				},
+				CertificateBinding: &CertificateBinding{},
+				DPoP:               &DPoP{Algs: []string{"ES256"}},
+			},
+			DiscoveryEndpoints{
+				JWKS:   "https://keys.example.com/.well-known/jwks.json",
+				Issue:  "https://issuer.example.com/issue",
+				Claims: "https://claims.example.com/claims",
+			},
+			DefaultDiscoveryMaxAge,
+		)
+
+		require.NoError(err)
+		require.NotNil(h)
+		assert.Equal(
+			Discovery{
+				Issuer:                           "https://issuer.example.com",
+				JWKSURI:                          "https://keys.example.com/.well-known/jwks.json",
+				IDTokenSigningAlgValuesSupported: []string{"ES256"},
+				IssueEndpoint:                    "https://issuer.example.com/issue",
+				ClaimsEndpoint:                   "https://claims.example.com/claims",
+				CertificateBoundAccessTokens:     true,
+				DPoPSigningAlgValuesSupported:    []string{"ES256"},
+			},
+			testDiscovery(t, h, "ignored.example.com"),
+		)
+	})
+
+	t.Run("DerivedURLs", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+		)
+
+		h, err := NewDiscoveryHandler(
+			Options{
+				Claims: []Value{
+					{Key: "iss", Value: "themis"},
+				},
+			},
+			DiscoveryEndpoints{
+				JWKS:  "http://:6500/.well-known/jwks.json",
+				Issue: "https:///issue",
+			},
+			DefaultDiscoveryMaxAge,
+		)
+
+		require.NoError(err)
+		require.NotNil(h)
+		assert.Equal(
+			Discovery{
+				Issuer:                           "themis",
+				JWKSURI:                          "http://themis.example.com:6500/.well-known/jwks.json",
+				IDTokenSigningAlgValuesSupported: []string{DefaultAlg},
+				IssueEndpoint:                    "https://themis.example.com:8443/issue",
+			},
+			testDiscovery(t, h, "themis.example.com:8443"),
+		)
+
+		assert.Equal(
+			"http://[::1]:6500/.well-known/jwks.json",
+			testDiscovery(t, h, "[::1]").JWKSURI,
+		)
+	})
+
+	t.Run("HTTPIssuer", func(t *testing.T) {
+		h, err := NewDiscoveryHandler(
+			Options{
+				Claims: []Value{
+					{Key: "iss", Header: "X-Issuer"},
+				},
+			},
+			DiscoveryEndpoints{
+				JWKS: "http://:6500/.well-known/jwks.json",
+			},
+			DefaultDiscoveryMaxAge,
+		)
+
+		require.NoError(t, err)
+		assert.Empty(t, testDiscovery(t, h, "localhost").Issuer)
+	})
+
+	t.Run("InvalidIssuer", func(t *testing.T) {
+		h, err := NewDiscoveryHandler(
+			Options{
+				Claims: []Value{
+					{Key: "iss", Value: 123},
+				},
+			},
+			DiscoveryEndpoints{
+				JWKS: "http://:6500/.well-known/jwks.json",
+			},
+			DefaultDiscoveryMaxAge,
+		)
+
+		assert.ErrorIs(t, err, ErrInvalidIssuer)
+		assert.Nil(t, h)
+	})
+
+	t.Run("NoJWKS", func(t *testing.T) {
+		h, err := NewDiscoveryHandler(Options{}, DiscoveryEndpoints{}, time.Minute)
+		assert.ErrorIs(t, err, ErrNoJWKSURL)
+		assert.Nil(t, h)
+	})
+
+	t.Run("InvalidEndpoint", func(t *testing.T) {
+		for _, e := range []DiscoveryEndpoints{
+			{JWKS: "/.well-known/jwks.json"},
+			{JWKS: "http://:6500/.well-known/jwks.json", Issue: "http://%zz/issue"},
+			{JWKS: "http://:6500/.well-known/jwks.json", Claims: "claims"},
+		} {
+			h, err := NewDiscoveryHandler(Options{}, e, time.Minute)
+			assert.ErrorIs(t, err, ErrInvalidEndpoint)
+			assert.Nil(t, h)
+		}
+	})
+}
+
+func TestUnmarshalDiscoveryOptions(t *testing.T) {
+	testData := []struct {
+		configuration string
+		expected      DiscoveryOptions
+	}{
+		{
+			configuration: `{}`,
+		},
+		{
+			configuration: `{"discovery": {"keysURL": "https://keys.example.com", "claimsURL": "https://claims.example.com"}}`,
+			expected: DiscoveryOptions{
+				KeysURL:   "https://keys.example.com",
+				ClaimsURL: "https://claims.example.com",
+			},
+		},
+	}
+
+	for _, record := range testData {
+		t.Run(record.configuration, func(t *testing.T) {
+			v := viper.New()
+			require.NoError(t, config.Json(record.configuration)(config.ViperIn{}, v))
+
+			o, err := UnmarshalDiscoveryOptions("discovery")(config.ViperUnmarshaller{Viper: v})
+			assert.NoError(t, err)
+			assert.Equal(t, record.expected, o)
+		})
+	}
+}
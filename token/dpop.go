@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/lestrrat-go/jwx/jwk"
+	"go.uber.org/zap"
+)
+
+const (
+	// DPoPHeader is the HTTP header carrying a DPoP proof
+	DPoPHeader = "DPoP"
+
+	// DPoPType is the required typ header of a DPoP proof
+	DPoPType = "dpop+jwt"
+
+	// DefaultDPoPMaxAge is how far the iat of a DPoP proof may be from the current time when no
+	// maximum age is configured
+	DefaultDPoPMaxAge = time.Minute
+)
+
+var (
+	ErrNoDPoPProof        = errors.New("a DPoP proof is required")
+	ErrInvalidDPoPProof   = errors.New("the DPoP proof is not valid")
+	ErrDPoPProofReplayed  = errors.New("the DPoP proof has already been used")
+	ErrUnsupportedDPoPAlg = errors.New("unsupported DPoP proof algorithm")
+)
+
+// dpopAlgs are the asymmetric algorithms accepted for DPoP proofs when none are configured.  A proof
+// carries its own public key, so symmetric algorithms can never be used.
+var dpopAlgs = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// DPoP describes how tokens are bound to DPoP keys, as described in RFC 9449.  A client proves
+// possession of its key by sending a proof, a JWT signed by that key, in the DPoP header.  Tokens
+// issued to such clients carry a cnf claim with the jkt thumbprint of the key.
+type DPoP struct {
+	// Required indicates whether issue and refresh requests without a DPoP proof are rejected.
+	// If false, requests without a proof receive unbound tokens.
+	Required bool
+
+	// Algs are the accepted proof algorithms.  If unset, any asymmetric algorithm is accepted.
+	Algs []string
+
+	// MaxAge is how far the iat of a proof may be from the current time, in either direction.
+	// If unset, DefaultDPoPMaxAge is used.
+	MaxAge time.Duration
+
+	// URL is the externally visible base URL of the issuer server, e.g. https://issuer.example.com,
+	// used to check the htu claim of proofs.  If unset, the URL is derived from each request, which
+	// only works when clients connect to themis directly.
+	URL string
+}
+
+// dpopRequestBuilder validates the DPoP proof of an HTTP request.  Replayed proofs are detected by
+// their jti, which is remembered until the proof's iat is too old to be accepted.  As with rotated
+// refresh tokens, replays are only detected by the themis instance that first saw the proof.
+type dpopRequestBuilder struct {
+	required bool
+	maxAge   time.Duration
+	base     *url.URL
+	parser   *jwt.Parser
+	seen     *redeemed
+	now      func() time.Time
+}
+
+func newDPoPRequestBuilder(d DPoP) (*dpopRequestBuilder, error) {
+	for _, alg := range d.Algs {
+		if !slices.Contains(dpopAlgs, alg) {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedDPoPAlg, alg)
+		}
+	}
+
+	if len(d.Algs) == 0 {
+		d.Algs = dpopAlgs
+	}
+
+	drb := &dpopRequestBuilder{
+		required: d.Required,
+		maxAge:   d.MaxAge,
+		parser: &jwt.Parser{
+			ValidMethods:         d.Algs,
+			UseJSONNumber:        true,
+			SkipClaimsValidation: true,
+		},
+		seen: &redeemed{expiry: make(map[string]time.Time)},
+		now:  time.Now,
+	}
+
+	if drb.maxAge <= 0 {
+		drb.maxAge = DefaultDPoPMaxAge
+	}
+
+	if len(d.URL) > 0 {
+		var err error
+		if drb.base, err = url.Parse(d.URL); err != nil {
+			return nil, fmt.Errorf("invalid DPoP URL: %w", err)
+		}
+	}
+
+	return drb, nil
+}
+
+// invalidProof produces the error for a DPoP proof that was rejected
+func invalidProof(format string, args ...any) error {
+	return httpError{
+		err:  fmt.Errorf("%w: %s", ErrInvalidDPoPProof, fmt.Sprintf(format, args...)),
+		code: http.StatusBadRequest,
+	}
+}
+
+func (drb *dpopRequestBuilder) Build(original *http.Request, tr *Request) error {
+	proofs := original.Header.Values(DPoPHeader)
+	switch {
+	case len(proofs) == 0 && drb.required:
+		return httpError{err: ErrNoDPoPProof, code: http.StatusBadRequest}
+	case len(proofs) == 0:
+		return nil
+	case len(proofs) > 1:
+		return invalidProof("only one proof is allowed")
+	}
+
+	var (
+		proofKey jwk.Key
+		claims   = jwt.MapClaims{}
+	)
+
+	_, err := drb.parser.ParseWithClaims(proofs[0], claims, func(t *jwt.Token) (any, error) {
+		if t.Header["typ"] != DPoPType {
+			return nil, fmt.Errorf("the typ header must be %s", DPoPType)
+		}
+
+		data, err := json.Marshal(t.Header["jwk"])
+		if err != nil {
+			return nil, err
+		}
+
+		if proofKey, err = jwk.ParseKey(data); err != nil {
+			return nil, fmt.Errorf("the jwk header is not a valid key: %w", err)
+		}
+
+		switch proofKey.(type) {
+		case jwk.RSAPrivateKey, jwk.ECDSAPrivateKey, jwk.OKPPrivateKey, jwk.SymmetricKey:
+			return nil, errors.New("the jwk header must be a public key")
+		}
+
+		var public any
+		err = proofKey.Raw(&public)
+		return public, err
+	})
+
+	if err != nil {
+		return invalidProof("%s", err)
+	}
+
+	if err := drb.checkClaims(original, claims); err != nil {
+		return err
+	}
+
+	thumbprint, err := proofKey.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return invalidProof("%s", err)
+	}
+
+	tr.DPoPThumbprint = base64.RawURLEncoding.EncodeToString(thumbprint)
+	tr.Logger = tr.Logger.With(zap.String("dpop_jkt", tr.DPoPThumbprint))
+	return nil
+}
+
+// checkClaims verifies that a proof was created for this request, recently, and has not been used before
+func (drb *dpopRequestBuilder) checkClaims(original *http.Request, claims jwt.MapClaims) error {
+	if htm, _ := claims["htm"].(string); htm != original.Method {
+		return invalidProof("htm %q does not match the request method", htm)
+	}
+
+	htu, _ := claims["htu"].(string)
+	if actual, err := url.Parse(htu); err != nil || normalizeHTU(actual) != normalizeHTU(drb.requestURL(original)) {
+		return invalidProof("htu %q does not match the request URL", htu)
+	}
+
+	now := drb.now()
+	iat, ok, err := numericDate(claims, "iat")
+	switch {
+	case err != nil || !ok:
+		return invalidProof("iat is required")
+	case iat.Before(now.Add(-drb.maxAge)) || iat.After(now.Add(drb.maxAge)):
+		return invalidProof("iat is outside the acceptable window")
+	}
+
+	jti, _ := claims["jti"].(string)
+	if len(jti) == 0 {
+		return invalidProof("jti is required")
+	}
+
+	if !drb.seen.redeem(jti, iat.Add(drb.maxAge), now) {
+		return httpError{err: ErrDPoPProofReplayed, code: http.StatusBadRequest}
+	}
+
+	return nil
+}
+
+// requestURL is the URL a client used to send a request
+func (drb *dpopRequestBuilder) requestURL(original *http.Request) *url.URL {
+	if drb.base != nil {
+		return drb.base.JoinPath(original.URL.Path)
+	}
+
+	u := &url.URL{Scheme: "http", Host: original.Host, Path: original.URL.Path}
+	if original.TLS != nil {
+		u.Scheme = "https"
+	}
+
+	return u
+}
+
+// normalizeHTU produces the RFC 9449 comparison form of an htu, ignoring the query, fragment,
+// the case of the scheme and host, and any default port.
+func normalizeHTU(u *url.URL) string {
+	var (
+		scheme = strings.ToLower(u.Scheme)
+		host   = strings.ToLower(u.Hostname())
+		port   = u.Port()
+	)
+
+	if (scheme == "https" && port == "443") || (scheme == "http" && port == "80") {
+		port = ""
+	}
+
+	if len(port) > 0 {
+		host += ":" + port
+	}
+
+	path := u.EscapedPath()
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return scheme + "://" + host + path
+}
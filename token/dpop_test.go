@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDPoPKey is a client's DPoP key
+type testDPoPKey struct {
+	private *ecdsa.PrivateKey
+	jwk     map[string]any
+	jkt     string
+}
+
+func newTestDPoPKey(t *testing.T) testDPoPKey {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	public, err := jwk.New(&private.PublicKey)
+	require.NoError(t, err)
+	thumbprint, err := public.Thumbprint(crypto.SHA256)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(public)
+	require.NoError(t, err)
+	var header map[string]any
+	require.NoError(t, json.Unmarshal(data, &header))
+
+	return testDPoPKey{
+		private: private,
+		jwk:     header,
+		jkt:     base64.RawURLEncoding.EncodeToString(thumbprint),
+	}
+}
+
+// proof creates a DPoP proof, with any overrides applied to the default headers and claims
+func (k testDPoPKey) proof(t *testing.T, headers map[string]any, claims jwt.MapClaims) string {
+	proof := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"jti": "proof-1",
+		"htm": "GET",
+		"htu": "https://issuer.example.com/issue",
+		"iat": time.Now().Unix(),
+	})
+
+	proof.Header["typ"] = DPoPType
+	proof.Header["jwk"] = k.jwk
+	for name, value := range headers {
+		if value == nil {
+			delete(proof.Header, name)
+		} else {
+			proof.Header[name] = value
+		}
+	}
+
+	c := proof.Claims.(jwt.MapClaims)
+	for name, value := range claims {
+		if value == nil {
+			delete(c, name)
+		} else {
+			c[name] = value
+		}
+	}
+
+	value, err := proof.SignedString(k.private)
+	require.NoError(t, err)
+	return value
+}
+
+func testDPoPRequest(proofs ...string) *http.Request {
+	request := httptest.NewRequest("GET", "https://issuer.example.com/issue?mac=112233445566", nil)
+	request.TLS = &tls.ConnectionState{}
+	for _, p := range proofs {
+		request.Header.Add(DPoPHeader, p)
+	}
+
+	return request
+}
+
+func testDPoPRequestBuilderValid(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		k       = newTestDPoPKey(t)
+	)
+
+	drb, err := newDPoPRequestBuilder(DPoP{})
+	require.NoError(err)
+
+	r := NewRequest()
+	require.NoError(drb.Build(testDPoPRequest(k.proof(t, nil, nil)), r))
+	assert.Equal(k.jkt, r.DPoPThumbprint)
+
+	// the comparison of htu ignores case and default ports
+	r = NewRequest()
+	require.NoError(drb.Build(testDPoPRequest(k.proof(t, nil, jwt.MapClaims{"jti": "proof-2", "htu": "HTTPS://Issuer.Example.com:443/issue"})), r))
+	assert.Equal(k.jkt, r.DPoPThumbprint)
+
+	// an unbound token is issued to clients without a proof, unless proofs are required
+	r = NewRequest()
+	require.NoError(drb.Build(testDPoPRequest(), r))
+	assert.Empty(r.DPoPThumbprint)
+
+	drb, err = newDPoPRequestBuilder(DPoP{Required: true})
+	require.NoError(err)
+	err = drb.Build(testDPoPRequest(), NewRequest())
+	assert.ErrorIs(err, ErrNoDPoPProof)
+}
+
+func testDPoPRequestBuilderExternalURL(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		k       = newTestDPoPKey(t)
+	)
+
+	drb, err := newDPoPRequestBuilder(DPoP{URL: "https://themis.example.com"})
+	require.NoError(err)
+
+	// behind a load balancer, the htu is the external URL rather than the URL themis received
+	r := NewRequest()
+	request := httptest.NewRequest("GET", "http://10.0.0.1:8080/issue", nil)
+	request.Header.Set(DPoPHeader, k.proof(t, nil, jwt.MapClaims{"htu": "https://themis.example.com/issue"}))
+	require.NoError(drb.Build(request, r))
+	assert.Equal(k.jkt, r.DPoPThumbprint)
+
+	request = httptest.NewRequest("GET", "http://10.0.0.1:8080/issue", nil)
+	request.Header.Set(DPoPHeader, k.proof(t, nil, jwt.MapClaims{"jti": "proof-2", "htu": "http://10.0.0.1:8080/issue"}))
+	assert.ErrorIs(drb.Build(request, NewRequest()), ErrInvalidDPoPProof)
+}
+
+func testDPoPRequestBuilderInvalid(t *testing.T) {
+	var (
+		k     = newTestDPoPKey(t)
+		other = newTestDPoPKey(t)
+	)
+
+	privateJWK, err := jwk.New(k.private)
+	require.NoError(t, err)
+
+	testData := []struct {
+		name     string
+		proofs   []string
+		expected error
+	}{
+		{"TwoProofs", []string{k.proof(t, nil, nil), k.proof(t, nil, nil)}, ErrInvalidDPoPProof},
+		{"NotAJWT", []string{"junk"}, ErrInvalidDPoPProof},
+		{"WrongType", []string{k.proof(t, map[string]any{"typ": "JWT"}, nil)}, ErrInvalidDPoPProof},
+		{"NoJWK", []string{k.proof(t, map[string]any{"jwk": nil}, nil)}, ErrInvalidDPoPProof},
+		{"PrivateJWK", []string{k.proof(t, map[string]any{"jwk": privateJWK}, nil)}, ErrInvalidDPoPProof},
+		{"OtherKey", []string{k.proof(t, map[string]any{"jwk": other.jwk}, nil)}, ErrInvalidDPoPProof},
+		{"WrongMethod", []string{k.proof(t, nil, jwt.MapClaims{"htm": "POST"})}, ErrInvalidDPoPProof},
+		{"WrongURL", []string{k.proof(t, nil, jwt.MapClaims{"htu": "https://issuer.example.com/refresh"})}, ErrInvalidDPoPProof},
+		{"WrongHost", []string{k.proof(t, nil, jwt.MapClaims{"htu": "https://evil.example.com/issue"})}, ErrInvalidDPoPProof},
+		{"NoIssuedAt", []string{k.proof(t, nil, jwt.MapClaims{"iat": nil})}, ErrInvalidDPoPProof},
+		{"TooOld", []string{k.proof(t, nil, jwt.MapClaims{"iat": time.Now().Add(-2 * DefaultDPoPMaxAge).Unix()})}, ErrInvalidDPoPProof},
+		{"TooNew", []string{k.proof(t, nil, jwt.MapClaims{"iat": time.Now().Add(2 * DefaultDPoPMaxAge).Unix()})}, ErrInvalidDPoPProof},
+		{"NoJTI", []string{k.proof(t, nil, jwt.MapClaims{"jti": nil})}, ErrInvalidDPoPProof},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			drb, err := newDPoPRequestBuilder(DPoP{})
+			require.NoError(t, err)
+
+			r := NewRequest()
+			err = drb.Build(testDPoPRequest(record.proofs...), r)
+			assert.ErrorIs(t, err, record.expected)
+			assert.Empty(t, r.DPoPThumbprint)
+
+			var sc interface{ StatusCode() int }
+			require.ErrorAs(t, err, &sc)
+			assert.Equal(t, http.StatusBadRequest, sc.StatusCode())
+		})
+	}
+
+	t.Run("AlgNotAllowed", func(t *testing.T) {
+		drb, err := newDPoPRequestBuilder(DPoP{Algs: []string{"RS256"}})
+		require.NoError(t, err)
+		assert.ErrorIs(t, drb.Build(testDPoPRequest(k.proof(t, nil, nil)), NewRequest()), ErrInvalidDPoPProof)
+	})
+
+	t.Run("SymmetricAlg", func(t *testing.T) {
+		drb, err := newDPoPRequestBuilder(DPoP{Algs: []string{"HS256"}})
+		assert.Nil(t, drb)
+		assert.ErrorIs(t, err, ErrUnsupportedDPoPAlg)
+	})
+}
+
+func testDPoPRequestBuilderReplay(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		k       = newTestDPoPKey(t)
+		proof   = k.proof(t, nil, nil)
+	)
+
+	drb, err := newDPoPRequestBuilder(DPoP{})
+	require.NoError(err)
+
+	require.NoError(drb.Build(testDPoPRequest(proof), NewRequest()))
+	assert.ErrorIs(drb.Build(testDPoPRequest(proof), NewRequest()), ErrDPoPProofReplayed)
+}
+
+func testDPoPTokenBinding(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		k       = newTestDPoPKey(t)
+		o       = Options{DPoP: &DPoP{}}
+	)
+
+	rb, err := NewRequestBuilders(o)
+	require.NoError(err)
+	cb, err := newConfirmationClaimBuilder(o)
+	require.NoError(err)
+
+	r, err := BuildRequest(testDPoPRequest(k.proof(t, nil, nil)), rb)
+	require.NoError(err)
+
+	claims := make(map[string]any)
+	require.NoError(cb.AddClaims(context.Background(), r, claims))
+	assert.Equal(map[string]any{ConfirmationJWKThumbprint: k.jkt}, claims[ClaimConfirmation])
+}
+
+func TestDPoPRequestBuilder(t *testing.T) {
+	t.Run("Valid", testDPoPRequestBuilderValid)
+	t.Run("ExternalURL", testDPoPRequestBuilderExternalURL)
+	t.Run("Invalid", testDPoPRequestBuilderInvalid)
+	t.Run("Replay", testDPoPRequestBuilderReplay)
+	t.Run("TokenBinding", testDPoPTokenBinding)
+}
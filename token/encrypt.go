@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwe"
+	"github.com/xmidt-org/themis/v2/key"
+)
+
+const (
+	// DefaultEncryptionEnc is the default JWE content encryption algorithm
+	DefaultEncryptionEnc = "A256GCM"
+
+	// ContentTypeJWT is the content type of issued tokens that are encrypted.  Signed tokens
+	// are issued as application/jose, while this type lets clients know that they received a
+	// nested JWT, encrypted to its recipient, that they cannot read.
+	ContentTypeJWT = "application/jwt"
+
+	// jweSegments is the number of segments in the compact serialization of a JWE
+	jweSegments = 5
+)
+
+var (
+	ErrUnsupportedRecipientKey = errors.New("the recipient key cannot be used to encrypt tokens")
+	ErrUnsupportedEncryption   = errors.New("unsupported token encryption algorithm")
+	ErrEncryptedRefresh        = errors.New("refresh tokens cannot be issued with encrypted tokens")
+)
+
+// Encryption describes how signed tokens are encrypted to a recipient as nested JWTs.  Only the
+// recipient can read the claims of an encrypted token.
+type Encryption struct {
+	// Alg is the JWE key management algorithm.  RSA keys support RSA-OAEP and RSA-OAEP-256, while
+	// ECDSA keys support ECDH-ES and ECDH-ES with AES key wrapping, e.g. ECDH-ES+A256KW.  If unset,
+	// RSA-OAEP is used for RSA keys and ECDH-ES for ECDSA keys.
+	Alg string
+
+	// Enc is the JWE content encryption algorithm.  If unset, DefaultEncryptionEnc is used.
+	Enc string
+
+	// Recipient describes the public key that tokens are encrypted to
+	Recipient key.RecipientDescriptor
+}
+
+// encrypter wraps signed tokens in a JWE
+type encrypter struct {
+	alg     jwa.KeyEncryptionAlgorithm
+	enc     jwa.ContentEncryptionAlgorithm
+	key     any
+	headers jwe.Headers
+}
+
+// newEncrypter reads the recipient key and validates the algorithms used to encrypt tokens to it
+func newEncrypter(e Encryption) (*encrypter, error) {
+	recipient, err := key.ReadRecipient(e.Recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	var allowed []jwa.KeyEncryptionAlgorithm
+	switch recipient.Key.(type) {
+	case *rsa.PublicKey:
+		allowed = []jwa.KeyEncryptionAlgorithm{jwa.RSA_OAEP, jwa.RSA_OAEP_256}
+	case *ecdsa.PublicKey:
+		allowed = []jwa.KeyEncryptionAlgorithm{jwa.ECDH_ES, jwa.ECDH_ES_A128KW, jwa.ECDH_ES_A192KW, jwa.ECDH_ES_A256KW}
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedRecipientKey, recipient.Key)
+	}
+
+	en := &encrypter{
+		alg:     allowed[0],
+		enc:     DefaultEncryptionEnc,
+		key:     recipient.Key,
+		headers: jwe.NewHeaders(),
+	}
+
+	if len(e.Alg) > 0 {
+		en.alg = jwa.KeyEncryptionAlgorithm(e.Alg)
+		if !slices.Contains(allowed, en.alg) {
+			return nil, fmt.Errorf("%w: %s cannot be used with a %T", ErrUnsupportedEncryption, e.Alg, recipient.Key)
+		}
+	}
+
+	if len(e.Enc) > 0 {
+		if err := en.enc.Accept(e.Enc); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncryption, err)
+		}
+	}
+
+	// the payload of the JWE is itself a JWT, as described in RFC 7519 section 5.2
+	if err := en.headers.Set(jwe.ContentTypeKey, "JWT"); err != nil {
+		return nil, err
+	}
+
+	if len(recipient.Kid) > 0 {
+		if err := en.headers.Set(jwe.KeyIDKey, recipient.Kid); err != nil {
+			return nil, err
+		}
+	}
+
+	return en, nil
+}
+
+// encrypt produces the compact serialization of a JWE containing a signed token
+func (en *encrypter) encrypt(signed string) (string, error) {
+	encrypted, err := jwe.Encrypt(
+		[]byte(signed),
+		en.alg,
+		en.key,
+		en.enc,
+		jwa.NoCompress,
+		jwe.WithProtectedHeaders(en.headers),
+	)
+
+	return string(encrypted), err
+}
+
+// isEncrypted tests if a token is in the compact serialization of a JWE rather than a JWS
+func isEncrypted(token string) bool {
+	return strings.Count(token, ".") == jweSegments-1
+}
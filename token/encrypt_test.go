@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/key"
+)
+
+// testRecipientFile writes the public portion of a key to a PEM file
+func testRecipientFile(t *testing.T, public crypto.PublicKey) string {
+	data, err := key.MarshalPKIXPublicKeyToPEM(public)
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "recipient.pem")
+	require.NoError(t, os.WriteFile(file, data, 0600))
+	return file
+}
+
+func testFactoryEncryption(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	testData := []struct {
+		name        string
+		alg         string
+		private     any
+		public      crypto.PublicKey
+		expectedAlg jwa.KeyEncryptionAlgorithm
+	}{
+		{"RSADefault", "", rsaKey, &rsaKey.PublicKey, jwa.RSA_OAEP},
+		{"RSA-OAEP-256", "RSA-OAEP-256", rsaKey, &rsaKey.PublicKey, jwa.RSA_OAEP_256},
+		{"ECDSADefault", "", ecdsaKey, &ecdsaKey.PublicKey, jwa.ECDH_ES},
+		{"ECDH-ES+A256KW", "ECDH-ES+A256KW", ecdsaKey, &ecdsaKey.PublicKey, jwa.ECDH_ES_A256KW},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert   = assert.New(t)
+				require  = require.New(t)
+				registry = key.NewRegistry(rand.Reader)
+			)
+
+			f, err := NewFactory(
+				Options{
+					Alg: "ES256",
+					Key: key.Descriptor{Kid: "test", Type: key.KeyTypeECDSA, Bits: 256},
+					Encryption: &Encryption{
+						Alg:       record.alg,
+						Recipient: key.RecipientDescriptor{Kid: "recipient", File: testRecipientFile(t, record.public)},
+					},
+				},
+				ClaimBuilders{requestClaimBuilder{}},
+				registry,
+			)
+
+			require.NoError(err)
+			r := NewRequest()
+			r.Claims["allowedResources"] = "secret"
+			token, err := f.NewToken(context.Background(), r)
+			require.NoError(err)
+			assert.True(isEncrypted(token))
+
+			message, err := jwe.Parse([]byte(token))
+			require.NoError(err)
+			headers := message.ProtectedHeaders()
+			assert.Equal(record.expectedAlg, headers.Algorithm())
+			assert.Equal(jwa.A256GCM, headers.ContentEncryption())
+			assert.Equal("JWT", headers.ContentType())
+			assert.Equal("recipient", headers.KeyID())
+
+			// the recipient can decrypt the nested, signed token
+			signed, err := jwe.Decrypt([]byte(token), record.expectedAlg, record.private)
+			require.NoError(err)
+			assert.False(isEncrypted(string(signed)))
+
+			claims, err := NewVerifier(registry, "ES256").Verify(string(signed))
+			require.NoError(err)
+			assert.Equal("secret", claims["allowedResources"])
+
+			response := httptest.NewRecorder()
+			require.NoError(EncodeIssueResponse(context.Background(), response, token))
+			assert.Equal(ContentTypeJWT, response.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func testFactoryEncryptionInvalid(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	var (
+		rsaFile = testRecipientFile(t, &rsaKey.PublicKey)
+		edFile  = testRecipientFile(t, edPublic)
+	)
+
+	testData := []struct {
+		name       string
+		encryption Encryption
+		refresh    *Refresh
+		expected   error
+	}{
+		{"NoFile", Encryption{}, nil, key.ErrNoRecipientFile},
+		{"UnsupportedKey", Encryption{Recipient: key.RecipientDescriptor{File: edFile}}, nil, ErrUnsupportedRecipientKey},
+		{"AlgMismatch", Encryption{Alg: "ECDH-ES", Recipient: key.RecipientDescriptor{File: rsaFile}}, nil, ErrUnsupportedEncryption},
+		{"UnsupportedEnc", Encryption{Enc: "nosuch", Recipient: key.RecipientDescriptor{File: rsaFile}}, nil, ErrUnsupportedEncryption},
+		{"Refresh", Encryption{Recipient: key.RecipientDescriptor{File: rsaFile}}, &Refresh{}, ErrEncryptedRefresh},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			f, err := NewFactory(
+				Options{Encryption: &record.encryption, Refresh: record.refresh},
+				ClaimBuilders{},
+				key.NewRegistry(rand.Reader),
+			)
+
+			assert.Nil(t, f)
+			assert.ErrorIs(t, err, record.expected)
+		})
+	}
+}
+
+func TestFactoryEncryption(t *testing.T) {
+	t.Run("Success", testFactoryEncryption)
+	t.Run("Invalid", testFactoryEncryptionInvalid)
+}
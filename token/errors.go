@@ -14,3 +14,7 @@ func (e httpError) Error() string {
 func (e httpError) StatusCode() int {
 	return e.code
 }
+
+func (e httpError) Unwrap() error {
+	return e.err
+}
@@ -4,12 +4,17 @@ package token
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"sync/atomic"
 
+	"github.com/lestrrat-go/jwx/jwk"
 	"github.com/xmidt-org/sallust"
+	"github.com/xmidt-org/themis/v2/cose"
 	"github.com/xmidt-org/themis/v2/key"
+	"github.com/xmidt-org/themis/v2/paseto"
 	"go.uber.org/zap"
 
 	"github.com/golang-jwt/jwt"
@@ -17,6 +22,9 @@ import (
 
 const (
 	DefaultAlg = "RS256"
+
+	// AlgEdDSA is the JWA algorithm for Ed25519 signatures
+	AlgEdDSA = "EdDSA"
 )
 
 // Request is a token creation request.  Clients can pass in arbitrary claims, typically things like "iss",
@@ -34,6 +42,18 @@ type Request struct {
 	// For non-tls connections, this field is unset.
 	TLS *tls.ConnectionState
 
+	// DPoPThumbprint is the RFC 7638 thumbprint of the key that signed the request's DPoP proof.
+	// This field is unset if the request had no DPoP proof.
+	DPoPThumbprint string
+
+	// TrustReason is the reason for the trust computed from the request's client certificate, such as
+	// expired_trusted.  This field is unset until the trust claim has been added.
+	TrustReason string
+
+	// Format is the format of the issued token, which is one of FormatJWT, FormatCWT, or FormatPASETO.
+	// If unset, or if the Factory is not configured for the format, a JWT is issued.
+	Format string
+
 	// The following fields are for remote claims' requests.
 	Metadata        map[string]any // Metadata is the request payload.
 	PathWildCards   map[string]any // PathWildCards are the request path wildcards.
@@ -57,49 +77,235 @@ type Factory interface {
 	NewToken(context.Context, *Request) (string, error)
 }
 
+// ClaimsFactory is a Factory that also reports the claims of each token it creates, so that
+// callers can describe an issued token, e.g. its lifetime, without parsing it
+type ClaimsFactory interface {
+	Factory
+
+	// NewTokenClaims produces a token along with the claims it contains
+	NewTokenClaims(context.Context, *Request) (string, map[string]any, error)
+}
+
 type factory struct {
 	method       jwt.SigningMethod
 	claimBuilder ClaimBuilder
 
-	// pair is an atomic value so that future updates can implement key rotation
+	// descriptor is the key Descriptor as registered, including any defaults
+	descriptor key.Descriptor
+
+	// certificateThumbprint controls whether the x5t#S256 header is added to tokens
+	certificateThumbprint bool
+
+	// encrypter is the optional encryption applied to signed tokens
+	encrypter *encrypter
+
+	// cwt is the optional encoder for tokens issued as CWTs
+	cwt *cwtEncoder
+
+	// paseto is the optional encoder for tokens issued as PASETO tokens
+	paseto *pasetoEncoder
+
+	// pair is an atomic value so that the signing key can be rotated
 	pair atomic.Value
 }
 
 func (f *factory) NewToken(ctx context.Context, r *Request) (string, error) {
+	token, _, err := f.newToken(ctx, r)
+	return token, err
+}
+
+func (f *factory) NewTokenClaims(ctx context.Context, r *Request) (string, map[string]any, error) {
+	return f.newToken(ctx, r)
+}
+
+// newToken produces a signed token along with the claims it contains
+func (f *factory) newToken(ctx context.Context, r *Request) (string, map[string]any, error) {
 	merged := make(map[string]any, len(r.Claims))
 	if err := f.claimBuilder.AddClaims(ctx, r, merged); err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	r.Logger.Info("new token", zap.Any("trust", merged[ClaimTrust]))
-	token := jwt.NewWithClaims(f.method, jwt.MapClaims(merged))
-	pair := f.pair.Load().(key.Pair)
+	token, err := f.issueClaims(r, merged)
+	return token, merged, err
+}
+
+// issueClaims produces an access token with the given claims in the format of the request.  A JWT
+// is signed and, if configured, encrypted as a nested JWT.
+func (f *factory) issueClaims(r *Request, claims map[string]any) (string, error) {
+	switch {
+	case r.Format == FormatCWT && f.cwt != nil:
+		return f.signCWT(claims)
+	case r.Format == FormatPASETO && f.paseto != nil:
+		return f.signPASETO(claims)
+	}
+
+	token, err := f.signClaims(claims, "")
+	if err != nil || f.encrypter == nil {
+		return token, err
+	}
+
+	return f.encrypter.encrypt(token)
+}
+
+// signClaims signs a token with the given claims using the current key Pair.  If typ is set, it
+// replaces the default typ header of JWT.
+func (f *factory) signClaims(claims map[string]any, typ string) (string, error) {
+	token := jwt.NewWithClaims(f.method, jwt.MapClaims(claims))
+	if len(typ) > 0 {
+		token.Header["typ"] = typ
+	}
+
+	pair := f.currentPair()
 	token.Header["kid"] = pair.KID()
+	if certificates := pair.Certificates(); f.certificateThumbprint && len(certificates) > 0 {
+		token.Header[jwk.X509CertThumbprintS256Key] = key.CertificateThumbprintS256(certificates[0])
+	}
+
+	if signer := pair.Signer(); signer != nil {
+		return signToken(token, signer)
+	}
+
 	return token.SignedString(pair.Sign())
 }
 
+// signCWT signs a CWT with the given claims using the current key Pair
+func (f *factory) signCWT(claims map[string]any) (string, error) {
+	payload, err := f.cwt.payload(claims)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		pair   = f.currentPair()
+		alg    = f.method.Alg()
+		signer = pair.Signer()
+	)
+
+	if signer == nil {
+		return "", fmt.Errorf("%w: symmetric keys cannot sign CWTs", ErrUnsupportedCWT)
+	}
+
+	token, err := cose.Sign1(alg, pair.KID(), payload, func(input []byte) ([]byte, error) {
+		return signJWS(alg, signer, input)
+	})
+
+	return string(token), err
+}
+
+// signPASETO signs a PASETO v4.public token with the given claims using the current key Pair.
+// The footer identifies the key with its k4.pid PASERK.
+func (f *factory) signPASETO(claims map[string]any) (string, error) {
+	message, err := f.paseto.message(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signer := f.currentPair().Signer()
+	if signer == nil {
+		return "", fmt.Errorf("%w: symmetric keys cannot sign PASETO tokens", ErrUnsupportedPASETO)
+	}
+
+	public, ok := signer.Public().(ed25519.PublicKey)
+	if !ok {
+		return "", fmt.Errorf("%w: %T keys cannot sign PASETO tokens", ErrUnsupportedPASETO, signer.Public())
+	}
+
+	footer, err := json.Marshal(map[string]string{FooterKeyID: paseto.PublicKeyID(public)})
+	if err != nil {
+		return "", err
+	}
+
+	return paseto.SignV4(message, footer, func(input []byte) ([]byte, error) {
+		return signJWS(AlgEdDSA, signer, input)
+	})
+}
+
 // NewFactory creates a token Factory from a Descriptor.  The supplied Noncer is used if and only
 // if d.Nonce is true.  Alternatively, supplying a nil Noncer will disable nonce creation altogether.
 // The token's key pair is registered with the given key Registry.
 func NewFactory(o Options, cb ClaimBuilder, kr key.Registry) (Factory, error) {
+	f, err := newFactory(o, cb, kr)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func newFactory(o Options, cb ClaimBuilder, kr key.Registry) (*factory, error) {
 	if len(o.Alg) == 0 {
 		o.Alg = DefaultAlg
 	}
 
 	f := &factory{
-		method:       jwt.GetSigningMethod(o.Alg),
-		claimBuilder: cb,
+		method:                jwt.GetSigningMethod(o.Alg),
+		claimBuilder:          cb,
+		certificateThumbprint: o.CertificateThumbprint,
 	}
 
 	if f.method == nil {
 		return nil, fmt.Errorf("no such signing method: %s", o.Alg)
 	}
 
+	// publish the signing algorithm with the key unless configured otherwise
+	if len(o.Key.Alg) == 0 {
+		o.Key.Alg = o.Alg
+	}
+
+	// EdDSA can only ever be used with ed25519 keys, so there's no need to configure the type
+	if o.Alg == AlgEdDSA && len(o.Key.Type) == 0 && len(o.Key.File) == 0 {
+		o.Key.Type = key.KeyTypeEd25519
+	}
+
+	if o.CWT != nil {
+		var err error
+		if f.cwt, err = newCWTEncoder(*o.CWT, o); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.PASETO != nil {
+		var err error
+		if f.paseto, err = newPASETOEncoder(o); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := defaultFormat(o); err != nil {
+		return nil, err
+	}
+
+	if o.Encryption != nil {
+		// a refresh token would disclose the cached claims of its encrypted access token, and
+		// themis cannot decrypt a refresh token encrypted to the recipient
+		if o.Refresh != nil {
+			return nil, ErrEncryptedRefresh
+		}
+
+		var err error
+		if f.encrypter, err = newEncrypter(*o.Encryption); err != nil {
+			return nil, err
+		}
+	}
+
 	pair, err := kr.Register(o.Key)
 	if err != nil {
 		return nil, err
 	}
 
-	f.pair.Store(pair)
+	f.descriptor = o.Key
+	f.setPair(pair)
 	return f, nil
 }
+
+// currentPair returns the key Pair presently used to sign tokens
+func (f *factory) currentPair() key.Pair {
+	return f.pair.Load().(key.Pair)
+}
+
+// setPair atomically replaces the key Pair used to sign tokens.  This method
+// is used as a key.Rotator listener.
+func (f *factory) setPair(p key.Pair) {
+	f.pair.Store(p)
+}
@@ -4,13 +4,22 @@ package token
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/xmidt-org/themis/v2/key"
 	"github.com/xmidt-org/themis/v2/random"
 	"go.uber.org/zap"
 
+	"github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -77,10 +86,177 @@ func testNewFactorySuccess(t *testing.T) {
 	token, err := factory.NewToken(context.Background(), &Request{Logger: zap.NewNop()})
 	require.NoError(err)
 	assert.True(len(token) > 0)
+
+	pair, ok := registry.Get("test")
+	require.True(ok)
+	assert.Equal("RS256", pair.Alg())
+}
+
+func testNewFactoryRotation(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		registry = key.NewRegistry(rand.Reader)
+	)
+
+	f, err := newFactory(Options{
+		Alg: "ES256",
+		Key: key.Descriptor{
+			Kid:              "test",
+			Type:             key.KeyTypeECDSA,
+			Bits:             256,
+			RotationInterval: time.Hour,
+		},
+		Duration: time.Hour,
+	}, ClaimBuilders{}, registry)
+
+	require.NoError(err)
+	require.NotNil(f)
+
+	rotator, err := key.NewRotator(registry, f.descriptor, f.currentPair(), time.Hour, nil, nil)
+	require.NoError(err)
+	rotator.OnRotate(f.setPair)
+
+	next, err := rotator.Rotate()
+	require.NoError(err)
+	assert.Equal("ES256", next.Alg())
+
+	signed, err := f.NewToken(context.Background(), &Request{Logger: zap.NewNop()})
+	require.NoError(err)
+
+	token, _, err := new(jwt.Parser).ParseUnverified(signed, jwt.MapClaims{})
+	require.NoError(err)
+	assert.Equal(next.KID(), token.Header["kid"])
+}
+
+func testNewFactoryEdDSA(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		registry = key.NewRegistry(rand.Reader)
+	)
+
+	factory, err := NewFactory(Options{
+		Alg: AlgEdDSA,
+		Key: key.Descriptor{
+			Kid: "test",
+		},
+	}, ClaimBuilders{}, registry)
+
+	require.NoError(err)
+	require.NotNil(factory)
+
+	signed, err := factory.NewToken(context.Background(), &Request{Logger: zap.NewNop()})
+	require.NoError(err)
+
+	pair, ok := registry.Get("test")
+	require.True(ok)
+	assert.Equal(AlgEdDSA, pair.Alg())
+
+	private, ok := pair.Sign().(ed25519.PrivateKey)
+	require.True(ok)
+
+	token, err := jwt.Parse(signed, func(token *jwt.Token) (any, error) {
+		return private.Public(), nil
+	})
+
+	require.NoError(err)
+	assert.True(token.Valid)
+	assert.Equal(AlgEdDSA, token.Header["alg"])
+}
+
+func testNewFactoryExternalSigner(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		registry = key.NewRegistry(rand.Reader)
+	)
+
+	factory, err := NewFactory(Options{
+		Alg: "ES256",
+		Key: key.Descriptor{
+			Kid:    "test",
+			Type:   key.KeyTypeECDSA,
+			Bits:   256,
+			Signer: &key.SignerDescriptor{Type: key.SignerTypeMemory},
+		},
+	}, ClaimBuilders{}, registry)
+
+	require.NoError(err)
+	require.NotNil(factory)
+
+	pair, ok := registry.Get("test")
+	require.True(ok)
+	require.NotNil(pair.Signer())
+	assert.Equal(pair.Signer(), pair.Sign())
+
+	signed, err := factory.NewToken(context.Background(), &Request{Logger: zap.NewNop()})
+	require.NoError(err)
+
+	token, err := jwt.Parse(signed, func(token *jwt.Token) (any, error) {
+		return pair.Signer().Public(), nil
+	})
+
+	require.NoError(err)
+	assert.True(token.Valid)
+	assert.Equal("test", token.Header["kid"])
+}
+
+func testNewFactoryCertificateThumbprint(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		registry = key.NewRegistry(rand.Reader)
+
+		directory = t.TempDir()
+		keyFile   = filepath.Join(directory, "key.pem")
+		certFile  = filepath.Join(directory, "cert.pem")
+	)
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(err)
+	require.NoError(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: key.PEMTypePrivateKey, Bytes: der}), 0600))
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err = x509.CreateCertificate(rand.Reader, template, template, private.Public(), private)
+	require.NoError(err)
+	require.NoError(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: key.PEMTypeCertificate, Bytes: der}), 0600))
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(err)
+
+	factory, err := NewFactory(Options{
+		Alg:                   AlgEdDSA,
+		CertificateThumbprint: true,
+		Key: key.Descriptor{
+			Kid:             "test",
+			File:            keyFile,
+			CertificateFile: certFile,
+		},
+	}, ClaimBuilders{}, registry)
+
+	require.NoError(err)
+	signed, err := factory.NewToken(context.Background(), &Request{Logger: zap.NewNop()})
+	require.NoError(err)
+
+	token, _, err := new(jwt.Parser).ParseUnverified(signed, jwt.MapClaims{})
+	require.NoError(err)
+	assert.Equal(key.CertificateThumbprintS256(cert), token.Header["x5t#S256"])
 }
 
 func TestNewFactory(t *testing.T) {
 	t.Run("InvalidAlg", testNewFactoryInvalidAlg)
 	t.Run("InvalidKeyType", testNewFactoryInvalidKeyType)
 	t.Run("Success", testNewFactorySuccess)
+	t.Run("Rotation", testNewFactoryRotation)
+	t.Run("EdDSA", testNewFactoryEdDSA)
+	t.Run("ExternalSigner", testNewFactoryExternalSigner)
+	t.Run("CertificateThumbprint", testNewFactoryCertificateThumbprint)
 }
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const (
+	// FormatJWT is the format of tokens issued as signed, and possibly encrypted, JWTs
+	FormatJWT = "jwt"
+
+	// FormatCWT is the format of tokens issued as CBOR Web Tokens
+	FormatCWT = "cwt"
+
+	// FormatPASETO is the format of tokens issued as PASETO v4.public tokens
+	FormatPASETO = "paseto"
+)
+
+var (
+	ErrConflictingFormats = errors.New("CWT and PASETO cannot both be the default token format")
+)
+
+// defaultFormat returns the format issued to clients that do not ask for one
+func defaultFormat(o Options) (string, error) {
+	switch {
+	case o.CWT != nil && o.CWT.Default && o.PASETO != nil && o.PASETO.Default:
+		return "", ErrConflictingFormats
+	case o.CWT != nil && o.CWT.Default:
+		return FormatCWT, nil
+	case o.PASETO != nil && o.PASETO.Default:
+		return FormatPASETO, nil
+	default:
+		return "", nil
+	}
+}
+
+// formatRequestBuilder selects the token format from the Accept header of each request
+type formatRequestBuilder struct {
+	preferred string
+	cwt       bool
+}
+
+func (frb formatRequestBuilder) Build(original *http.Request, tr *Request) error {
+	tr.Format = frb.preferred
+	for _, accept := range original.Header.Values("Accept") {
+		for value := range strings.SplitSeq(accept, ",") {
+			mediaType, _, err := mime.ParseMediaType(value)
+			switch {
+			case err != nil:
+				continue
+			case mediaType == ContentTypeCWT && frb.cwt:
+				tr.Format = FormatCWT
+				return nil
+			case mediaType == ContentTypeJWT || mediaType == "application/jose":
+				tr.Format = FormatJWT
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// fixedFormat is a RequestBuilder that issues tokens in a single format, regardless of the request
+type fixedFormat string
+
+func (ff fixedFormat) Build(_ *http.Request, tr *Request) error {
+	tr.Format = string(ff)
+	return nil
+}
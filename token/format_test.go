@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultFormat(t *testing.T) {
+	testData := []struct {
+		options  Options
+		expected string
+	}{
+		{Options{}, ""},
+		{Options{CWT: &CWT{}, PASETO: &PASETO{}}, ""},
+		{Options{CWT: &CWT{Default: true}, PASETO: &PASETO{}}, FormatCWT},
+		{Options{CWT: &CWT{}, PASETO: &PASETO{Default: true}}, FormatPASETO},
+	}
+
+	for _, record := range testData {
+		format, err := defaultFormat(record.options)
+		assert.NoError(t, err)
+		assert.Equal(t, record.expected, format)
+	}
+
+	format, err := defaultFormat(Options{CWT: &CWT{Default: true}, PASETO: &PASETO{Default: true}})
+	assert.Empty(t, format)
+	assert.ErrorIs(t, err, ErrConflictingFormats)
+}
+
+func TestFormatRequestBuilder(t *testing.T) {
+	testData := []struct {
+		builder  formatRequestBuilder
+		accept   []string
+		expected string
+	}{
+		{formatRequestBuilder{cwt: true}, nil, ""},
+		{formatRequestBuilder{preferred: FormatCWT, cwt: true}, nil, FormatCWT},
+		{formatRequestBuilder{preferred: FormatPASETO}, nil, FormatPASETO},
+		{formatRequestBuilder{cwt: true}, []string{"application/json"}, ""},
+		{formatRequestBuilder{preferred: FormatCWT, cwt: true}, []string{"application/json"}, FormatCWT},
+		{formatRequestBuilder{cwt: true}, []string{ContentTypeCWT}, FormatCWT},
+		{formatRequestBuilder{cwt: true}, []string{"text/plain, application/cwt;q=0.9"}, FormatCWT},
+		{formatRequestBuilder{cwt: true}, []string{"text/plain", ContentTypeCWT}, FormatCWT},
+		{formatRequestBuilder{preferred: FormatPASETO}, []string{ContentTypeCWT}, FormatPASETO},
+		{formatRequestBuilder{preferred: FormatCWT, cwt: true}, []string{ContentTypeJWT}, FormatJWT},
+		{formatRequestBuilder{preferred: FormatPASETO, cwt: true}, []string{"application/jose, application/cwt"}, FormatJWT},
+		{formatRequestBuilder{preferred: FormatCWT, cwt: true}, []string{"not a media type;;", ContentTypeJWT}, FormatJWT},
+	}
+
+	for _, record := range testData {
+		t.Run(fmt.Sprint(record.builder, record.accept), func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/issue", nil)
+			for _, accept := range record.accept {
+				request.Header.Add("Accept", accept)
+			}
+
+			r := NewRequest()
+			require.NoError(t, record.builder.Build(request, r))
+			assert.Equal(t, record.expected, r.Format)
+
+			// a fixed format overrides whatever the client asked for
+			require.NoError(t, fixedFormat(FormatPASETO).Build(request, r))
+			assert.Equal(t, FormatPASETO, r.Format)
+		})
+	}
+}
@@ -23,6 +23,9 @@ func NewIssueHandler(e endpoint.Endpoint, rb RequestBuilders) IssueHandler {
 	)
 }
 
+// PASETOIssueHandler is the IssueHandler for the route that only issues PASETO tokens
+type PASETOIssueHandler http.Handler
+
 type ClaimsHandler http.Handler
 
 func NewClaimsHandler(e endpoint.Endpoint, rb RequestBuilders) ClaimsHandler {
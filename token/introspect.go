@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xmidt-org/sallust"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNoIntrospectionToken = errors.New("the token parameter is required")
+)
+
+// IntrospectionResponse is the RFC 7662 response for a token.  For an active token, the
+// token's claims are included as top-level members alongside active.  Nothing else is
+// disclosed about an inactive token.
+type IntrospectionResponse map[string]any
+
+// introspectionOutcome maps a verification error onto its metric label value
+func introspectionOutcome(err error) string {
+	switch {
+	case err == nil:
+		return ValidOutcome
+	case errors.Is(err, ErrTokenExpired):
+		return ExpiredOutcome
+	case errors.Is(err, ErrTokenNotYetValid):
+		return NotYetValidOutcome
+	case errors.Is(err, ErrBadSignature):
+		return BadSignatureOutcome
+	case errors.Is(err, ErrUnknownKid):
+		return UnknownKidOutcome
+	case errors.Is(err, ErrTokenRevoked):
+		return RevokedOutcome
+	case errors.Is(err, ErrNotAccessToken):
+		return NotAccessOutcome
+	default:
+		return MalformedOutcome
+	}
+}
+
+// NewIntrospectEndpoint returns a go-kit endpoint that verifies a token, producing an IntrospectionResponse.
+// A token that fails verification is not an error, but an inactive token.
+//
+// The outcomes counter is optional.  If supplied, it must have the OutcomeLabelKey label.
+func NewIntrospectEndpoint(v Verifier, outcomes *prometheus.CounterVec) endpoint.Endpoint {
+	return func(ctx context.Context, value any) (any, error) {
+		claims, err := v.Verify(value.(string))
+		outcome := introspectionOutcome(err)
+		if outcomes != nil {
+			outcomes.With(prometheus.Labels{OutcomeLabelKey: outcome}).Add(1)
+		}
+
+		if err != nil {
+			sallust.Get(ctx).Info("inactive token", zap.String("outcome", outcome), zap.Error(err))
+			return IntrospectionResponse{"active": false}, nil
+		}
+
+		response := make(IntrospectionResponse, len(claims)+1)
+		for k, v := range claims {
+			response[k] = v
+		}
+
+		response["active"] = true
+		return response, nil
+	}
+}
+
+// DecodeIntrospectRequest extracts the token from an RFC 7662 introspection request, which is
+// a form post with the token in the token parameter.
+func DecodeIntrospectRequest(_ context.Context, request *http.Request) (any, error) {
+	if err := request.ParseForm(); err != nil {
+		return nil, httpError{err: err, code: http.StatusBadRequest}
+	}
+
+	token := request.PostForm.Get("token")
+	if len(token) == 0 {
+		return nil, httpError{err: ErrNoIntrospectionToken, code: http.StatusBadRequest}
+	}
+
+	return token, nil
+}
+
+// EncodeIntrospectResponse writes an IntrospectionResponse, which must never be cached
+func EncodeIntrospectResponse(ctx context.Context, response http.ResponseWriter, value any) error {
+	response.Header().Set("Cache-Control", "no-store")
+	return kithttp.EncodeJSONResponse(ctx, response, value)
+}
+
+type IntrospectHandler http.Handler
+
+func NewIntrospectHandler(e endpoint.Endpoint) IntrospectHandler {
+	return kithttp.NewServer(
+		e,
+		DecodeIntrospectRequest,
+		EncodeIntrospectResponse,
+		kithttp.ServerBefore(func(ctx context.Context, r *http.Request) context.Context {
+			return WithTracingHeaders(ctx, r)
+		}),
+	)
+}
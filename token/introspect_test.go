@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/key"
+)
+
+func testIntrospect(t *testing.T, h IntrospectHandler, form url.Values) (*httptest.ResponseRecorder, map[string]any) {
+	var (
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("POST", "/introspect", strings.NewReader(form.Encode()))
+	)
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h.ServeHTTP(response, request)
+
+	var body map[string]any
+	if response.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	}
+
+	return response, body
+}
+
+func TestNewIntrospectHandler(t *testing.T) {
+	var (
+		registry = key.NewRegistry(rand.Reader)
+		other    = key.NewRegistry(rand.Reader)
+		outcomes = prometheus.NewCounterVec(
+			prometheus.CounterOpts{Name: "test"},
+			[]string{OutcomeLabelKey},
+		)
+
+		handler = NewIntrospectHandler(
+			NewIntrospectEndpoint(
+				NewRevokingVerifier(NewVerifier(registry, "ES256"), testRevocations("revoked")),
+				outcomes,
+			),
+		)
+	)
+
+	_, err := registry.Register(key.Descriptor{Kid: "test", Type: key.KeyTypeECDSA, Bits: 256, Alg: "ES256"})
+	require.NoError(t, err)
+	_, err = other.Register(key.Descriptor{Kid: "test", Type: key.KeyTypeECDSA, Bits: 256, Alg: "ES256"})
+	require.NoError(t, err)
+	_, err = other.Register(key.Descriptor{Kid: "unknown", Type: key.KeyTypeECDSA, Bits: 256, Alg: "ES256"})
+	require.NoError(t, err)
+
+	t.Run("Active", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+			exp     = time.Now().Add(time.Hour).Unix()
+		)
+
+		response, body := testIntrospect(t, handler, url.Values{
+			"token": {testSignToken(t, registry, "test", "ES256", jwt.MapClaims{"sub": "test", "exp": exp, "active": "ignored"})},
+		})
+
+		require.Equal(http.StatusOK, response.Code)
+		assert.Equal("no-store", response.Header().Get("Cache-Control"))
+		assert.Equal(
+			map[string]any{
+				"active": true,
+				"sub":    "test",
+				"exp":    float64(exp),
+			},
+			body,
+		)
+
+		assert.Equal(1.0, testutil.ToFloat64(outcomes.WithLabelValues(ValidOutcome)))
+	})
+
+	testData := []struct {
+		name    string
+		token   string
+		outcome string
+	}{
+		{"Expired", testSignToken(t, registry, "test", "ES256", jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}), ExpiredOutcome},
+		{"NotYetValid", testSignToken(t, registry, "test", "ES256", jwt.MapClaims{"nbf": time.Now().Add(time.Hour).Unix()}), NotYetValidOutcome},
+		{"BadSignature", testSignToken(t, other, "test", "ES256", jwt.MapClaims{"sub": "test"}), BadSignatureOutcome},
+		{"UnknownKid", testSignToken(t, other, "unknown", "ES256", jwt.MapClaims{"sub": "test"}), UnknownKidOutcome},
+		{"Malformed", "this is not a token", MalformedOutcome},
+		{"Revoked", testSignToken(t, registry, "test", "ES256", jwt.MapClaims{"jti": "revoked"}), RevokedOutcome},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+			)
+
+			response, body := testIntrospect(t, handler, url.Values{"token": {record.token}})
+			require.Equal(http.StatusOK, response.Code)
+			assert.Equal(map[string]any{"active": false}, body)
+			assert.Equal(1.0, testutil.ToFloat64(outcomes.WithLabelValues(record.outcome)))
+		})
+	}
+
+	t.Run("NoToken", func(t *testing.T) {
+		response, _ := testIntrospect(t, handler, url.Values{"token_type_hint": {"access_token"}})
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("RefreshToken", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			require = require.New(t)
+			rf, v   = testNewRefresher(t, Options{}, nil, nil)
+			issued  = testRefreshIssue(t, rf, "112233445566")
+			handler = NewIntrospectHandler(NewIntrospectEndpoint(v, outcomes))
+		)
+
+		// refresh tokens are signed by the same key as access tokens, but are never active
+		response, body := testIntrospect(t, handler, url.Values{"token": {issued.RefreshToken}})
+		require.Equal(http.StatusOK, response.Code)
+		assert.Equal(map[string]any{"active": false}, body)
+		assert.Equal(1.0, testutil.ToFloat64(outcomes.WithLabelValues(NotAccessOutcome)))
+
+		response, body = testIntrospect(t, handler, url.Values{"token": {issued.Token}})
+		require.Equal(http.StatusOK, response.Code)
+		assert.Equal(true, body["active"])
+	})
+}
@@ -13,6 +13,7 @@ const (
 	TrustCounter                            = "trust_total"
 	RemoteClaimsAPIResultCounter            = "remote_claims_api_result_total"
 	RemoteClaimsAPIRequestDurationHistogram = "remote_claims_api_request_duration_seconds"
+	IntrospectCounter                       = "introspect_total"
 )
 
 // Metric label keys for API Result counter.
@@ -25,6 +26,7 @@ const (
 	TrustLabelKey     = "trust"
 	IssuerCNLabelKey  = "issuer_cn"
 	PartnerIDLabelKey = "partner_id"
+	ProfileLabelKey   = "profile"
 )
 
 // Metric label values for outcomes.
@@ -34,6 +36,18 @@ const (
 	UnknownOutcome = "unknown"
 )
 
+// Metric label values for introspection outcomes.
+const (
+	ValidOutcome        = "valid"
+	ExpiredOutcome      = "expired"
+	NotYetValidOutcome  = "not_yet_valid"
+	BadSignatureOutcome = "bad_signature"
+	UnknownKidOutcome   = "unknown_kid"
+	RevokedOutcome      = "revoked"
+	MalformedOutcome    = "malformed"
+	NotAccessOutcome    = "not_access_token"
+)
+
 // Metric label values for reasons.
 const (
 	UnknownReason = "unknown"
@@ -78,6 +92,7 @@ func ProvideMetrics() fx.Option {
 			IssuerCNLabelKey,
 			PartnerIDLabelKey,
 			ReasonLabelKey,
+			ProfileLabelKey,
 		),
 		xmetrics.ProvideCounterVec(
 			prometheus.CounterOpts{
@@ -89,6 +104,7 @@ func ProvideMetrics() fx.Option {
 			CodeLabelKey,
 			OutcomeLabelKey,
 			ReasonLabelKey,
+			ProfileLabelKey,
 		),
 		xmetrics.ProvideHistogramVec(
 			prometheus.HistogramOpts{
@@ -99,6 +115,14 @@ func ProvideMetrics() fx.Option {
 			MethodLabelKey,
 			CodeLabelKey,
 			OutcomeLabelKey,
+			ProfileLabelKey,
+		),
+		xmetrics.ProvideCounterVec(
+			prometheus.CounterOpts{
+				Name: IntrospectCounter,
+				Help: "The total number of introspected tokens, by outcome.",
+			},
+			OutcomeLabelKey,
 		),
 	)
 }
@@ -6,11 +6,43 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"slices"
 	"time"
 
 	"github.com/xmidt-org/themis/v2/key"
 )
 
+const (
+	// ValueTypeString is the default type of values from HTTP requests
+	ValueTypeString = "string"
+
+	// ValueTypeInt converts values from HTTP requests to 64-bit integers
+	ValueTypeInt = "int"
+
+	// ValueTypeFloat converts values from HTTP requests to 64-bit floating point numbers
+	ValueTypeFloat = "float"
+
+	// ValueTypeBool converts values from HTTP requests to booleans, accepting the same
+	// values as strconv.ParseBool
+	ValueTypeBool = "bool"
+
+	// ValueTypeStringList is the same as ValueTypeString with List set
+	ValueTypeStringList = "stringList"
+
+	// ValueTypeJSON embeds values from HTTP requests, which must be well formed JSON, as is
+	ValueTypeJSON = "json"
+)
+
+// valueTypes are the supported types of values from HTTP requests
+var valueTypes = []string{
+	ValueTypeString,
+	ValueTypeInt,
+	ValueTypeFloat,
+	ValueTypeBool,
+	ValueTypeStringList,
+	ValueTypeJSON,
+}
+
 const (
 	DefaultTrustLevelNoCertificates        = 0
 	DefaultTrustLevelExpiredUntrusted      = 0
@@ -52,6 +84,41 @@ type Value struct {
 
 	// Value is the statically assigned value from configuration
 	Value any
+
+	// Type is the type a header or parameter value is converted to, which is one of string, int,
+	// float, bool, stringList, or json.  Requests whose values cannot be converted are rejected
+	// with a 400.  If unset, the first value of the header or parameter is used as a string.
+	Type string
+
+	// List indicates that every value of a header or parameter is converted, producing a list.
+	// If unset, a typed header or parameter with more than one value is rejected with a 400.
+	List bool
+
+	// Separator, if set, splits each value of a list header or parameter, such as the
+	// comma separated values of a header.  Each element is trimmed of surrounding whitespace,
+	// and empty elements are dropped.
+	Separator string
+}
+
+// ValueType returns the type of the values of this Value, which is string if no type is configured
+func (v Value) ValueType() string {
+	switch v.Type {
+	case "", ValueTypeStringList:
+		return ValueTypeString
+	default:
+		return v.Type
+	}
+}
+
+// IsList tests if this Value produces a list
+func (v Value) IsList() bool {
+	return v.List || v.Type == ValueTypeStringList
+}
+
+// IsTyped tests if this Value converts the values of a header or parameter, rather than just
+// using the first one as is
+func (v Value) IsTyped() bool {
+	return len(v.Type) > 0 || v.IsList()
 }
 
 // IsFromHTTP tests if this value is extracted from an HTTP request
@@ -88,6 +155,15 @@ func (v Value) Validate() error {
 		return fmt.Errorf("value `%s` can't have multiple types: %s", v.Key, types)
 	}
 
+	switch {
+	case len(v.Type) > 0 && !slices.Contains(valueTypes, v.Type):
+		return fmt.Errorf("value `%s`: %w: %s", v.Key, ErrInvalidValueType, v.Type)
+	case (v.IsTyped() || len(v.Separator) > 0) && len(v.Header) == 0 && len(v.Parameter) == 0:
+		return fmt.Errorf("value `%s`: %w", v.Key, ErrTypeNotAllowed)
+	case len(v.Separator) > 0 && !v.IsList():
+		return fmt.Errorf("value `%s`: %w", v.Key, ErrSeparatorNotAllowed)
+	}
+
 	return nil
 }
 
@@ -247,6 +323,25 @@ type CertChecks struct {
 	IssuerCN *regexp.Regexp
 }
 
+// Refresh describes how refresh tokens are issued alongside access tokens and later redeemed
+// for new access tokens.
+type Refresh struct {
+	// Duration is how long a refresh token is valid.  If unset, DefaultRefreshDuration is used.
+	Duration time.Duration
+
+	// Rotate indicates whether redeeming a refresh token also issues a new refresh token.  When true,
+	// each refresh token can be redeemed only once by a given process until it restarts.  Redeemed
+	// refresh tokens are tracked in memory, so a refresh token redeemed by one replica can still be
+	// redeemed by another, or by the same replica after a restart, until it expires.  Revoking the
+	// claims of a stolen refresh token is the only way to stop it everywhere.
+	Rotate bool
+
+	// RemoteMaxAge is how old the claims cached in a refresh token can be before the remote claims
+	// are fetched again when the refresh token is redeemed.  If unset, the remote claims are never
+	// fetched again and the cached claims are reused as is.  This field has no effect unless Remote is set.
+	RemoteMaxAge time.Duration
+}
+
 // Options holds the configurable information for a token Factory
 type Options struct {
 	// Alg is the required JWT signing algorithm to use
@@ -256,9 +351,36 @@ type Options struct {
 	// If unset, client certificates are not considered when issuing tokens.
 	ClientCertificates *ClientCertificates
 
+	// CertificateBinding is the optional configuration for binding tokens to client certificates.
+	// If set, each token issued to a client that presented a certificate carries a cnf claim
+	// with that certificate's thumbprint.
+	CertificateBinding *CertificateBinding
+
+	// DPoP is the optional configuration for binding tokens to DPoP keys.  If set, each token
+	// issued to a client that sent a DPoP proof carries a cnf claim with the key's thumbprint.
+	DPoP *DPoP
+
 	// Key describes the signing key to use
 	Key key.Descriptor
 
+	// CertificateThumbprint indicates whether the x5t#S256 header, identifying the signing key's
+	// certificate, is added to each token.  This field has no effect unless Key.CertificateFile is set.
+	CertificateThumbprint bool
+
+	// Encryption is the optional configuration for encrypting tokens to a recipient.  If set, each
+	// signed token is issued as a nested JWT within a JWE that only the recipient can decrypt.
+	// Themis cannot decrypt tokens it has encrypted, so Encryption cannot be combined with Refresh,
+	// as refresh tokens would otherwise disclose the claims they cache.
+	Encryption *Encryption
+
+	// CWT is the optional configuration for issuing tokens as CBOR Web Tokens.  If set, clients
+	// may ask for a CWT, signed with the same key, rather than a JWT.  CWTs cannot be encrypted.
+	CWT *CWT
+
+	// PASETO is the optional configuration for issuing tokens as PASETO v4.public tokens.  If set,
+	// Alg must be EdDSA, and tokens can be issued as PASETO tokens rather than JWTs.
+	PASETO *PASETO
+
 	// Claims is an optional map of claims to add to every token emitted by this factory.
 	// Any claims here can be overridden by claims within a token Request.
 	//
@@ -297,6 +419,21 @@ type Options struct {
 	// claims from the remote system do not override claims configured on the Factory.
 	Remote *RemoteClaims
 
+	// Refresh is the optional refresh token configuration.  If unset, no refresh tokens are issued.
+	Refresh *Refresh
+
+	// TrustPolicy is the optional configuration for varying tokens by the trust of each request.
+	// If unset, every token is issued with the same duration and claims, whatever its trust.
+	TrustPolicy *TrustPolicy
+
+	// Transforms are the optional expressions that derive, rewrite, or delete claims.  Transforms run
+	// in order, after every other claim has been added, including those from remote claims and the trust policy.
+	Transforms []Transform
+
+	// Profiles are the optional named token profiles served alongside these options.  Each profile
+	// issues tokens at /issue/{name} with its own options.  Only the top-level options can have profiles.
+	Profiles []Profile
+
 	// The following options are for remote claims' requests.
 	Metadata        []Value // Metadata describes the non-claim request payload, which can be statically configured or supplied via a request.
 	PathWildCards   []Value // PathWildCards are the request path wildcards, which can be statically configured or supplied via a HTTP request.
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/themis/v2/paseto"
+)
+
+const (
+	// ContentTypePASETO is the content type of tokens issued as PASETO tokens.  PASETO has no
+	// registered media type, so this type follows the pattern of application/jwt.
+	ContentTypePASETO = "application/paseto"
+
+	// FooterKeyID is the PASETO footer claim holding the k4.pid PASERK of the signing key
+	FooterKeyID = "kid"
+)
+
+var (
+	ErrUnsupportedPASETO = errors.New("PASETO tokens cannot be issued with this configuration")
+)
+
+// pasetoTimeClaims are the registered PASETO claims that hold ISO 8601 times rather than the
+// numeric dates of JWTs
+var pasetoTimeClaims = []string{"exp", "nbf", "iat"}
+
+// PASETO describes how tokens are issued as PASETO v4.public tokens, which are signed with Ed25519
+// and have no algorithm header for a client to tamper with.  PASETO tokens carry the same claims as
+// JWTs, and are always signed by the token key, so Alg must be EdDSA.
+type PASETO struct {
+	// Default indicates whether PASETO tokens are issued to clients whose Accept header asks for
+	// neither a CWT nor a JWT.  If false, PASETO tokens are only issued by the PASETO issue route.
+	// At most one of CWT and PASETO can be the default format.
+	Default bool
+}
+
+// pasetoEncoder produces the messages of PASETO tokens
+type pasetoEncoder struct{}
+
+func newPASETOEncoder(o Options) (*pasetoEncoder, error) {
+	if o.Alg != AlgEdDSA {
+		return nil, fmt.Errorf("%w: the alg must be %s rather than %s", ErrUnsupportedPASETO, AlgEdDSA, o.Alg)
+	}
+
+	if o.Encryption != nil {
+		return nil, fmt.Errorf("%w: tokens cannot be both encrypted and issued as PASETO tokens", ErrUnsupportedPASETO)
+	}
+
+	return &pasetoEncoder{}, nil
+}
+
+// message produces the JSON message of a PASETO token, with the numeric dates of the time claims
+// converted to RFC 3339 times
+func (pe *pasetoEncoder) message(claims map[string]any) ([]byte, error) {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&normalized); err != nil {
+		return nil, err
+	}
+
+	for _, name := range pasetoTimeClaims {
+		if n, ok := normalized[name].(json.Number); ok {
+			seconds, err := n.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("the %s claim is not a numeric date: %w", name, err)
+			}
+
+			normalized[name] = time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+		}
+	}
+
+	return json.Marshal(normalized)
+}
+
+// isPASETO tests if a token is a PASETO token rather than a JWT
+func isPASETO(token string) bool {
+	return strings.HasPrefix(token, paseto.HeaderV4Public)
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/key"
+	"github.com/xmidt-org/themis/v2/paseto"
+)
+
+func testFactoryPASETO(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		registry = key.NewRegistry(rand.Reader)
+	)
+
+	f, err := NewFactory(
+		Options{
+			Alg:    AlgEdDSA,
+			Key:    key.Descriptor{Kid: "test"},
+			PASETO: &PASETO{},
+		},
+		ClaimBuilders{requestClaimBuilder{}},
+		registry,
+	)
+
+	require.NoError(err)
+	r := NewRequest()
+	r.Format = FormatPASETO
+	r.Claims["iss"] = "themis"
+	r.Claims["exp"] = int64(4102444800)
+	r.Claims["iat"] = json.Number("1700000000")
+	r.Claims["mac"] = "112233445566"
+
+	token, err := f.NewToken(context.Background(), r)
+	require.NoError(err)
+	assert.True(isPASETO(token))
+
+	pair, ok := registry.Get("test")
+	require.True(ok)
+	public := pair.Signer().Public().(ed25519.PublicKey)
+	message, footer, err := paseto.VerifyV4(token, public)
+	require.NoError(err)
+
+	var claims map[string]any
+	require.NoError(json.Unmarshal(message, &claims))
+	assert.Equal(
+		map[string]any{
+			"iss": "themis",
+			"exp": "2100-01-01T00:00:00Z",
+			"iat": "2023-11-14T22:13:20Z",
+			"mac": "112233445566",
+		},
+		claims,
+	)
+
+	assert.JSONEq(`{"kid": "`+paseto.PublicKeyID(public)+`"}`, string(footer))
+
+	response := httptest.NewRecorder()
+	require.NoError(EncodeIssueResponse(context.Background(), response, token))
+	assert.Equal(ContentTypePASETO, response.Header().Get("Content-Type"))
+	assert.Equal(token, response.Body.String())
+
+	// the same factory issues JWTs to requests that do not ask for a PASETO token
+	r.Format = ""
+	token, err = f.NewToken(context.Background(), r)
+	require.NoError(err)
+	assert.False(isPASETO(token))
+
+	verified, err := NewVerifier(registry, AlgEdDSA).Verify(token)
+	require.NoError(err)
+	assert.Equal("112233445566", verified["mac"])
+}
+
+func testFactoryPASETOInvalid(t *testing.T) {
+	testData := []struct {
+		name     string
+		options  Options
+		expected error
+	}{
+		{"DefaultAlg", Options{PASETO: &PASETO{}}, ErrUnsupportedPASETO},
+		{"Symmetric", Options{Alg: "HS256", PASETO: &PASETO{}}, ErrUnsupportedPASETO},
+		{"Encryption", Options{Alg: AlgEdDSA, PASETO: &PASETO{}, Encryption: &Encryption{}}, ErrUnsupportedPASETO},
+		{"ConflictingDefaults", Options{Alg: AlgEdDSA, PASETO: &PASETO{Default: true}, CWT: &CWT{Default: true}}, ErrConflictingFormats},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			f, err := NewFactory(record.options, ClaimBuilders{}, key.NewRegistry(rand.Reader))
+			assert.Nil(t, f)
+			assert.ErrorIs(t, err, record.expected)
+		})
+	}
+}
+
+func TestFactoryPASETO(t *testing.T) {
+	t.Run("Success", testFactoryPASETO)
+	t.Run("Invalid", testFactoryPASETOInvalid)
+}
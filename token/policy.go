@@ -0,0 +1,229 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+)
+
+var (
+	ErrTrustRefused          = errors.New("tokens are not issued for the trust of this request")
+	ErrUnknownTrustReason    = errors.New("unknown trust reason")
+	ErrInvalidTrustRange     = errors.New("the min trust of a trust policy rule cannot exceed its max trust")
+	ErrDynamicPolicyClaim    = errors.New("trust policy claims must have static values")
+	ErrConflictingPolicyRule = errors.New("a trust policy rule must either refuse tokens or change them, but not both")
+)
+
+// trustReasons are the reasons the client certificate claim builder computes trust for
+var trustReasons = []string{
+	NoCertificatesReason,
+	ExpiredUntrustedReason,
+	ExpiredTrustedReason,
+	UntrustedReason,
+	TrustedReason,
+	UntrustedCertIssuerCNReason,
+}
+
+// TrustPolicy varies the tokens issued for each request by the trust computed from its client
+// certificate, so that low trust clients can be given short lived tokens with fewer capabilities.
+type TrustPolicy struct {
+	// MinTrust is the lowest trust for which tokens are issued.  Requests with less trust, or with
+	// no trust claim at all, are refused with a 403.  If unset, no request is refused for its trust alone.
+	MinTrust int
+
+	// Rules vary tokens by trust.  The first rule that matches a request applies to its token.
+	// Tokens for requests that match no rule are issued as configured by Options.
+	Rules []TrustRule
+}
+
+// TrustRule applies to requests whose trust is within a range and, optionally, whose trust was
+// computed for one of a set of reasons.
+type TrustRule struct {
+	// Min is the lowest trust, inclusive, this rule applies to.  If unset, there is no lower bound.
+	Min *int
+
+	// Max is the highest trust, inclusive, this rule applies to.  If unset, there is no upper bound.
+	Max *int
+
+	// Reasons are the trust reasons, such as expired_trusted, this rule applies to.  If unset,
+	// this rule applies regardless of the reason the trust was computed for.
+	Reasons []string
+
+	// Refuse indicates that no tokens are issued to requests matching this rule.  Such
+	// requests are refused with a 403.
+	Refuse bool
+
+	// Duration, if positive, replaces Options.Duration for tokens matching this rule.
+	// This field has no effect if Options.DisableTime is set.
+	Duration time.Duration
+
+	// Claims are the static claims added to tokens matching this rule.  These claims override
+	// any others with the same names, including those from remote claims.  The cnf claim cannot
+	// be set this way.
+	Claims []Value
+}
+
+// newTrustPolicy creates the trustPolicy from configuration.  If no policy is configured,
+// this function returns nil.
+func newTrustPolicy(o Options) (*trustPolicy, error) {
+	if o.TrustPolicy == nil {
+		return nil, nil
+	}
+
+	tp := &trustPolicy{
+		minTrust: o.TrustPolicy.MinTrust,
+		rules:    make([]trustRule, 0, len(o.TrustPolicy.Rules)),
+	}
+
+	for i, r := range o.TrustPolicy.Rules {
+		rule := trustRule{
+			min:      math.MinInt,
+			max:      math.MaxInt,
+			reasons:  r.Reasons,
+			refuse:   r.Refuse,
+			duration: r.Duration,
+		}
+
+		if r.Min != nil {
+			rule.min = *r.Min
+		}
+
+		if r.Max != nil {
+			rule.max = *r.Max
+		}
+
+		if rule.min > rule.max {
+			return nil, fmt.Errorf("rule %d: %w", i, ErrInvalidTrustRange)
+		}
+
+		for _, reason := range r.Reasons {
+			if !slices.Contains(trustReasons, reason) {
+				return nil, fmt.Errorf("rule %d: %w: %s", i, ErrUnknownTrustReason, reason)
+			}
+		}
+
+		for _, v := range r.Claims {
+			switch {
+			case v.Key == ClaimConfirmation:
+				return nil, fmt.Errorf("rule %d: %w", i, ErrConfirmationClaim)
+			case !v.IsStatic():
+				return nil, fmt.Errorf("rule %d: %w: %s", i, ErrDynamicPolicyClaim, v.Key)
+			}
+		}
+
+		claims, err := getStaticValues(r.Claims)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+
+		if rule.refuse && (rule.duration > 0 || len(claims) > 0) {
+			return nil, fmt.Errorf("rule %d: %w", i, ErrConflictingPolicyRule)
+		}
+
+		rule.claims = claims
+		tp.rules = append(tp.rules, rule)
+	}
+
+	return tp, nil
+}
+
+type trustRule struct {
+	min      int
+	max      int
+	reasons  []string
+	refuse   bool
+	duration time.Duration
+	claims   map[string]any
+}
+
+func (tr trustRule) matches(trust int, reason string) bool {
+	return trust >= tr.min && trust <= tr.max && (len(tr.reasons) == 0 || slices.Contains(tr.reasons, reason))
+}
+
+// trustPolicy is a ClaimBuilder that applies the TrustPolicy to tokens.  It must run after
+// the claims it overrides, including the time-based claims and the trust claim, have been added.
+type trustPolicy struct {
+	minTrust int
+	rules    []trustRule
+}
+
+// match returns the first rule that applies to the given trust and reason, or nil if there is none
+func (tp *trustPolicy) match(trust int, reason string) *trustRule {
+	for i := range tp.rules {
+		if tp.rules[i].matches(trust, reason) {
+			return &tp.rules[i]
+		}
+	}
+
+	return nil
+}
+
+// checkTrust refuses requests whose trust is too low for any token to be issued.  It is run as
+// soon as the trust claim is set, so that refused requests never reach the remote claims.
+func (tp *trustPolicy) checkTrust(_ context.Context, r *Request, target map[string]any) error {
+	trust, ok := trustLevel(target[ClaimTrust])
+	switch {
+	case !ok && tp.minTrust > 0:
+		return httpError{err: fmt.Errorf("%w: no trust", ErrTrustRefused), code: http.StatusForbidden}
+	case !ok:
+		return nil
+	case trust < tp.minTrust:
+		return httpError{err: fmt.Errorf("%w: trust %d is below %d", ErrTrustRefused, trust, tp.minTrust), code: http.StatusForbidden}
+	}
+
+	if rule := tp.match(trust, r.TrustReason); rule != nil && rule.refuse {
+		return httpError{err: fmt.Errorf("%w: trust %d (%s)", ErrTrustRefused, trust, r.TrustReason), code: http.StatusForbidden}
+	}
+
+	return nil
+}
+
+func (tp *trustPolicy) AddClaims(ctx context.Context, r *Request, target map[string]any) error {
+	// the trust is checked again, as claims added since, such as remote claims, may have changed it
+	if err := tp.checkTrust(ctx, r, target); err != nil {
+		return err
+	}
+
+	trust, ok := trustLevel(target[ClaimTrust])
+	if !ok {
+		return nil
+	}
+
+	rule := tp.match(trust, r.TrustReason)
+	if rule == nil {
+		return nil
+	}
+
+	maps.Copy(target, rule.claims)
+	if iat, ok := target["iat"].(int64); ok && rule.duration > 0 {
+		target["exp"] = iat + int64(rule.duration/time.Second)
+	}
+
+	return nil
+}
+
+// trustLevel returns the value of a trust claim, which is a json.Number when the claims were
+// read back from a refresh token
+func trustLevel(v any) (int, bool) {
+	switch t := v.(type) {
+	case int:
+		return t, true
+	case int64:
+		return int(t), true
+	case float64:
+		return int(t), t == math.Trunc(t)
+	case json.Number:
+		n, err := t.Int64()
+		return int(n), err == nil
+	default:
+		return 0, false
+	}
+}
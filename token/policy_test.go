@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/sallust"
+)
+
+func testTrust(v int) *int {
+	return &v
+}
+
+func TestNewTrustPolicy(t *testing.T) {
+	t.Run("Unset", func(t *testing.T) {
+		tp, err := newTrustPolicy(Options{})
+		assert.Nil(t, tp)
+		assert.NoError(t, err)
+	})
+
+	testData := []struct {
+		name     string
+		rule     TrustRule
+		expected error
+	}{
+		{"InvalidRange", TrustRule{Min: testTrust(500), Max: testTrust(100)}, ErrInvalidTrustRange},
+		{"UnknownReason", TrustRule{Reasons: []string{"nosuch"}}, ErrUnknownTrustReason},
+		{"DynamicClaim", TrustRule{Claims: []Value{{Key: "mac", Header: "X-Midt-Mac-Address"}}}, ErrDynamicPolicyClaim},
+		{"ConfirmationClaim", TrustRule{Claims: []Value{{Key: ClaimConfirmation, Value: map[string]any{"jkt": "forged"}}}}, ErrConfirmationClaim},
+		{"RefuseAndDuration", TrustRule{Refuse: true, Duration: time.Minute}, ErrConflictingPolicyRule},
+		{"RefuseAndClaims", TrustRule{Refuse: true, Claims: []Value{{Key: "capabilities", Value: "none"}}}, ErrConflictingPolicyRule},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			tp, err := newTrustPolicy(Options{TrustPolicy: &TrustPolicy{Rules: []TrustRule{record.rule}}})
+			assert.Nil(t, tp)
+			assert.ErrorIs(t, err, record.expected)
+		})
+	}
+}
+
+func TestTrustPolicy(t *testing.T) {
+	tp, err := newTrustPolicy(Options{
+		TrustPolicy: &TrustPolicy{
+			MinTrust: 100,
+			Rules: []TrustRule{
+				{Reasons: []string{ExpiredUntrustedReason}, Refuse: true},
+				{Max: testTrust(500), Duration: 5 * time.Minute, Claims: []Value{{Key: "capabilities", Value: []string{"read"}}}},
+				{Min: testTrust(501), Max: testTrust(999), Reasons: []string{ExpiredTrustedReason}, Duration: time.Hour},
+			},
+		},
+	})
+
+	require.NoError(t, err)
+
+	const iat int64 = 1700000000
+	testData := []struct {
+		name         string
+		trust        any
+		reason       string
+		refused      bool
+		exp          int64
+		capabilities any
+	}{
+		{"NoTrust", nil, "", true, iat + 86400, "all"},
+		{"BelowMinTrust", 0, NoCertificatesReason, true, iat + 86400, "all"},
+		{"RefusedReason", 200, ExpiredUntrustedReason, true, iat + 86400, "all"},
+		{"LowTrust", 100, UntrustedReason, false, iat + 300, json.RawMessage(`["read"]`)},
+		{"LowTrustNumber", json.Number("500"), UntrustedReason, false, iat + 300, json.RawMessage(`["read"]`)},
+		{"ExpiredTrusted", 750, ExpiredTrustedReason, false, iat + 3600, "all"},
+		{"NoRule", 1000, TrustedReason, false, iat + 86400, "all"},
+		{"NoRuleForReason", 750, TrustedReason, false, iat + 86400, "all"},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+				r       = NewRequest()
+				target  = map[string]any{"iat": iat, "exp": iat + 86400, "capabilities": "all"}
+			)
+
+			if record.trust != nil {
+				target[ClaimTrust] = record.trust
+			}
+
+			r.TrustReason = record.reason
+			err := tp.AddClaims(context.Background(), r, target)
+			if record.refused {
+				require.ErrorIs(err, ErrTrustRefused)
+
+				var sc interface{ StatusCode() int }
+				require.ErrorAs(err, &sc)
+				assert.Equal(http.StatusForbidden, sc.StatusCode())
+				assert.Equal(err, tp.checkTrust(context.Background(), r, target))
+				return
+			}
+
+			require.NoError(err)
+			assert.Equal(record.exp, target["exp"])
+			assert.Equal(record.capabilities, target["capabilities"])
+		})
+	}
+}
+
+func TestTrustPolicyWithoutTime(t *testing.T) {
+	tp, err := newTrustPolicy(Options{
+		TrustPolicy: &TrustPolicy{
+			Rules: []TrustRule{{Duration: time.Minute}},
+		},
+	})
+
+	require.NoError(t, err)
+
+	// without an iat claim, there is nothing to compute an exp from
+	target := map[string]any{ClaimTrust: 0}
+	require.NoError(t, tp.AddClaims(context.Background(), &Request{Logger: sallust.Default()}, target))
+	assert.NotContains(t, target, "exp")
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// DefaultProfile is the name of the profile configured directly by Options, i.e. the one that
+// issues tokens at the unnamed routes.  It is the value of the profile label of its metrics.
+const DefaultProfile = "default"
+
+var (
+	ErrInvalidProfileName  = errors.New("profile names can only contain letters, digits, '-' and '_'")
+	ErrReservedProfileName = errors.New("profile name is reserved")
+	ErrDuplicateProfile    = errors.New("duplicate profile name")
+	ErrNestedProfiles      = errors.New("profiles cannot have profiles of their own")
+)
+
+// profileNamePattern restricts profile names to those that can be used as path segments as is
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// reservedProfileNames are the names that cannot be given to a profile, either because they name
+// the default profile or because the default profile already has a route of that name
+var reservedProfileNames = []string{DefaultProfile, "paseto"}
+
+// Profile is a named set of token Options.  A profile issues tokens at its own routes, e.g. /issue/{name},
+// alongside the default profile, so that a single themis instance can serve clients that need different
+// claims, durations or keys.
+type Profile struct {
+	// Name identifies the profile in its routes and in the profile label of metrics
+	Name string
+
+	// Options configures the tokens issued by this profile.  Nothing is inherited from the default
+	// profile, so each profile configures its own key, with a kid distinct from those of every
+	// other profile, as well as its own claims and remote claims.
+	Options `mapstructure:",squash"`
+}
+
+// ProfileHandlers are the handlers of a named token profile.  RefreshHandler and PASETOIssueHandler
+// are nil unless the profile configures refresh tokens or PASETO tokens, respectively.
+type ProfileHandlers struct {
+	Name               string
+	IssueHandler       IssueHandler
+	ClaimsHandler      ClaimsHandler
+	RefreshHandler     RefreshHandler
+	PASETOIssueHandler PASETOIssueHandler
+}
+
+// validateProfiles checks the names of configured profiles
+func validateProfiles(profiles []Profile) error {
+	names := make(map[string]bool, len(profiles))
+	for _, p := range profiles {
+		switch {
+		case !profileNamePattern.MatchString(p.Name):
+			return fmt.Errorf("%w: '%s'", ErrInvalidProfileName, p.Name)
+		case slices.Contains(reservedProfileNames, p.Name):
+			return fmt.Errorf("%w: %s", ErrReservedProfileName, p.Name)
+		case names[p.Name]:
+			return fmt.Errorf("%w: %s", ErrDuplicateProfile, p.Name)
+		case len(p.Profiles) > 0:
+			return fmt.Errorf("%w: %s", ErrNestedProfiles, p.Name)
+		}
+
+		names[p.Name] = true
+	}
+
+	return nil
+}
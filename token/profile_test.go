@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProfiles(t *testing.T) {
+	testData := []struct {
+		name     string
+		profiles []Profile
+		expected error
+	}{
+		{"None", nil, nil},
+		{"Valid", []Profile{{Name: "devices"}, {Name: "partner_services"}, {Name: "test-rigs"}}, nil},
+		{"Empty", []Profile{{Name: ""}}, ErrInvalidProfileName},
+		{"Path", []Profile{{Name: "devices/v2"}}, ErrInvalidProfileName},
+		{"Default", []Profile{{Name: DefaultProfile}}, ErrReservedProfileName},
+		{"PASETO", []Profile{{Name: "paseto"}}, ErrReservedProfileName},
+		{"Duplicate", []Profile{{Name: "devices"}, {Name: "devices"}}, ErrDuplicateProfile},
+		{"Nested", []Profile{{Name: "devices", Options: Options{Profiles: []Profile{{Name: "nested"}}}}}, ErrNestedProfiles},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			err := validateProfiles(record.profiles)
+			if record.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, record.expected)
+			}
+		})
+	}
+}
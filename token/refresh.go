@@ -0,0 +1,381 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/golang-jwt/jwt"
+	"github.com/xmidt-org/themis/v2/random"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultRefreshDuration is the default lifetime of refresh tokens
+	DefaultRefreshDuration = 30 * 24 * time.Hour
+
+	// RefreshTokenHeader is the HTTP response header that carries a refresh token
+	// alongside an issued access token
+	RefreshTokenHeader = "X-Midt-Refresh-Token"
+
+	// RefreshTokenParameter is the form parameter that carries the refresh token being redeemed
+	RefreshTokenParameter = "refresh_token"
+
+	// ClaimTokenUse is the claim that distinguishes refresh tokens from access tokens
+	ClaimTokenUse = "token_use"
+
+	// TokenUseRefresh is the value of the ClaimTokenUse claim in refresh tokens
+	TokenUseRefresh = "refresh"
+
+	// TypeRefreshToken is the typ header of refresh tokens.  Refresh tokens are signed with the
+	// same keys as access tokens, so verifiers must check this header to tell them apart.
+	TypeRefreshToken = "refresh+jwt"
+
+	// ClaimCachedClaims is the refresh token claim holding the claims of the access token
+	// it was issued with.  Nesting these claims keeps a refresh token from being mistaken
+	// for an access token by verifiers that examine claims such as trust.
+	ClaimCachedClaims = "claims"
+
+	// ClaimCachedAt is the refresh token claim holding the time its cached claims were built
+	ClaimCachedAt = "claims_iat"
+
+	// ClaimTokenFormat is the refresh token claim holding the format of the access token it was
+	// issued with, so that a token issued by a route for a single format is refreshed in that format
+	ClaimTokenFormat = "token_format"
+
+	// ClaimTokenProfile is the refresh token claim holding the name of the profile that issued it,
+	// so that it can only be redeemed by that profile.  Refresh tokens of the default profile have no
+	// such claim.
+	ClaimTokenProfile = "token_profile"
+
+	// ClaimTrustReason is the refresh token claim holding the reason for the trust cached with its
+	// claims, so that a TrustPolicy applies the same way when the refresh token is redeemed
+	ClaimTrustReason = "trust_reason"
+
+	// redeemedPruneInterval is how often expired entries are removed from the redeemed set
+	redeemedPruneInterval = time.Minute
+)
+
+var (
+	ErrNoRefreshToken       = errors.New("the refresh_token parameter is required")
+	ErrInvalidRefreshToken  = errors.New("the refresh token is not valid")
+	ErrNotRefreshToken      = errors.New("the token is not a refresh token")
+	ErrRefreshTokenRedeemed = errors.New("the refresh token has already been redeemed")
+	ErrRefreshTokenProfile  = errors.New("the refresh token was issued by another profile")
+)
+
+// reissuedClaims are the claims which are never cached in a refresh token, since
+// they are unique to each issued token
+var reissuedClaims = map[string]bool{
+	"jti": true,
+	"iat": true,
+	"exp": true,
+	"nbf": true,
+}
+
+// IssueResponse is an issued access token along with an optional refresh token
+type IssueResponse struct {
+	// Token is the access token, which is signed and possibly encrypted
+	Token string
+
+	// RefreshToken is the signed refresh token.  This field is unset if no
+	// refresh token was issued.
+	RefreshToken string
+}
+
+// RefreshRequest is a request to redeem a refresh token for a new access token
+type RefreshRequest struct {
+	// Request is the token Request built from the HTTP request that redeems the refresh token.
+	// It is used if the remote claims must be fetched again.
+	Request *Request
+
+	// RefreshToken is the signed refresh token being redeemed
+	RefreshToken string
+}
+
+// Refresher issues refresh tokens with access tokens and redeems them for new access tokens.
+// A refresh token caches the claims of its access token, so that redeeming it avoids the
+// expense of building those claims again.
+type Refresher interface {
+	// Issue creates an access token along with a refresh token
+	Issue(context.Context, *Request) (IssueResponse, error)
+
+	// Refresh redeems a refresh token, producing a new access token.  If refresh tokens are
+	// rotated, a new refresh token is also produced.
+	Refresh(context.Context, *RefreshRequest) (IssueResponse, error)
+}
+
+// redeemed tracks rotated refresh tokens, by jti, until they expire so that each one can be
+// redeemed only once.  This set is neither persisted nor shared, so it only applies within a process.
+type redeemed struct {
+	lock      sync.Mutex
+	expiry    map[string]time.Time
+	nextPrune time.Time
+}
+
+// redeem marks a refresh token as redeemed, returning false if it was already redeemed
+func (rd *redeemed) redeem(jti string, exp, now time.Time) bool {
+	rd.lock.Lock()
+	defer rd.lock.Unlock()
+
+	if !now.Before(rd.nextPrune) {
+		for k, e := range rd.expiry {
+			if !now.Before(e) {
+				delete(rd.expiry, k)
+			}
+		}
+
+		rd.nextPrune = now.Add(redeemedPruneInterval)
+	}
+
+	if _, ok := rd.expiry[jti]; ok {
+		return false
+	}
+
+	rd.expiry[jti] = exp
+	return true
+}
+
+type refresher struct {
+	factory      *factory
+	verifier     Verifier
+	revocations  Revocations
+	remote       ClaimBuilder
+	reissue      ClaimBuilders
+	noncer       random.Noncer
+	duration     time.Duration
+	remoteMaxAge time.Duration
+	redeemed     *redeemed
+	now          func() time.Time
+
+	// profile is the name of the profile this refresher issues tokens for, or empty for the default profile
+	profile string
+}
+
+// newRefresher creates a Refresher that signs tokens with the given factory.  The Verifier checks
+// refresh tokens when they are redeemed, and the optional Revocations is consulted with each refresh
+// token's cached claims.  The remote ClaimBuilder is nil if remote claims are not configured.
+func newRefresher(o Options, f *factory, v Verifier, r Revocations, remote ClaimBuilder, n random.Noncer) *refresher {
+	if n == nil {
+		// refresh tokens always have a jti, whether or not access tokens do
+		n = random.NewBase64Noncer(nil, 0, nil)
+	}
+
+	rf := &refresher{
+		factory:      f,
+		verifier:     v,
+		revocations:  r,
+		remote:       remote,
+		reissue:      newIssueClaimBuilders(n, o),
+		noncer:       n,
+		duration:     o.Refresh.Duration,
+		remoteMaxAge: o.Refresh.RemoteMaxAge,
+		now:          time.Now,
+	}
+
+	if rf.duration <= 0 {
+		rf.duration = DefaultRefreshDuration
+	}
+
+	if o.Refresh.Rotate {
+		rf.redeemed = &redeemed{expiry: make(map[string]time.Time)}
+	}
+
+	return rf
+}
+
+func (rf *refresher) Issue(ctx context.Context, r *Request) (IssueResponse, error) {
+	token, claims, err := rf.factory.newToken(ctx, r)
+	if err != nil {
+		return IssueResponse{}, err
+	}
+
+	refreshToken, err := rf.newRefreshToken(claims, rf.now(), r)
+	if err != nil {
+		return IssueResponse{}, err
+	}
+
+	return IssueResponse{Token: token, RefreshToken: refreshToken}, nil
+}
+
+// newRefreshToken signs a refresh token that caches the given access token claims, along with the
+// format and trust reason of the request they were issued for
+func (rf *refresher) newRefreshToken(claims map[string]any, cachedAt time.Time, r *Request) (string, error) {
+	cached := make(map[string]any, len(claims))
+	for k, v := range claims {
+		if !reissuedClaims[k] {
+			cached[k] = v
+		}
+	}
+
+	jti, err := rf.noncer.Nonce()
+	if err != nil {
+		return "", err
+	}
+
+	now := rf.now()
+	refreshClaims := map[string]any{
+		"jti":             jti,
+		"iat":             now.Unix(),
+		"exp":             now.Add(rf.duration).Unix(),
+		ClaimTokenUse:     TokenUseRefresh,
+		ClaimCachedClaims: cached,
+		ClaimCachedAt:     cachedAt.Unix(),
+	}
+
+	if len(r.Format) > 0 {
+		refreshClaims[ClaimTokenFormat] = r.Format
+	}
+
+	if len(r.TrustReason) > 0 {
+		refreshClaims[ClaimTrustReason] = r.TrustReason
+	}
+
+	if len(rf.profile) > 0 {
+		refreshClaims[ClaimTokenProfile] = rf.profile
+	}
+
+	return rf.factory.signClaims(refreshClaims, TypeRefreshToken)
+}
+
+func (rf *refresher) Refresh(ctx context.Context, rr *RefreshRequest) (IssueResponse, error) {
+	refreshClaims, err := rf.verifier.Verify(rr.RefreshToken)
+	if err != nil {
+		return IssueResponse{}, httpError{
+			err:  fmt.Errorf("%w: %w", ErrInvalidRefreshToken, err),
+			code: http.StatusBadRequest,
+		}
+	}
+
+	claims, ok := refreshClaims[ClaimCachedClaims].(map[string]any)
+	if !ok || refreshClaims[ClaimTokenUse] != TokenUseRefresh {
+		return IssueResponse{}, httpError{err: ErrNotRefreshToken, code: http.StatusBadRequest}
+	}
+
+	// every profile's keys are in the same registry, so the profile is checked explicitly
+	if profile, _ := refreshClaims[ClaimTokenProfile].(string); profile != rf.profile {
+		return IssueResponse{}, httpError{err: ErrRefreshTokenProfile, code: http.StatusBadRequest}
+	}
+
+	// a refresh token is bound to the same certificate or DPoP key as the access tokens it caches
+	if err := checkBinding(claims, rr.Request); err != nil {
+		return IssueResponse{}, httpError{err: err, code: http.StatusForbidden}
+	}
+
+	if rf.revocations != nil {
+		// revocations apply to the refresh token's own jti and iat, along with the cached device claims
+		check := make(map[string]any, len(claims)+2)
+		for k, v := range claims {
+			check[k] = v
+		}
+
+		check["jti"] = refreshClaims["jti"]
+		check["iat"] = refreshClaims["iat"]
+		if rf.revocations.Revoked(check) {
+			return IssueResponse{}, httpError{err: ErrTokenRevoked, code: http.StatusBadRequest}
+		}
+	}
+
+	now := rf.now()
+	cachedAt, _, err := numericDate(jwt.MapClaims(refreshClaims), ClaimCachedAt)
+	if err != nil {
+		return IssueResponse{}, httpError{err: err, code: http.StatusBadRequest}
+	}
+
+	if rf.remote != nil && rf.remoteMaxAge > 0 && now.Sub(cachedAt) >= rf.remoteMaxAge {
+		rr.Request.Logger.Info("refreshing remote claims", zap.Time("cachedAt", cachedAt))
+		if err := rf.remote.AddClaims(ctx, rr.Request, claims); err != nil {
+			return IssueResponse{}, err
+		}
+
+		cachedAt = now
+	}
+
+	// the trust isn't computed again, so neither is its reason
+	rr.Request.TrustReason, _ = refreshClaims[ClaimTrustReason].(string)
+	if err := rf.reissue.AddClaims(ctx, rr.Request, claims); err != nil {
+		return IssueResponse{}, err
+	}
+
+	// without a format from the request, tokens are refreshed in the format they were issued in
+	if len(rr.Request.Format) == 0 {
+		rr.Request.Format, _ = refreshClaims[ClaimTokenFormat].(string)
+	}
+
+	var response IssueResponse
+	if response.Token, err = rf.factory.issueClaims(rr.Request, claims); err != nil {
+		return IssueResponse{}, err
+	}
+
+	if rf.redeemed != nil {
+		if response.RefreshToken, err = rf.newRefreshToken(claims, cachedAt, rr.Request); err != nil {
+			return IssueResponse{}, err
+		}
+
+		// redeem last, so that a failure above does not use up the refresh token
+		jti, _ := refreshClaims["jti"].(string)
+		exp, _, _ := numericDate(jwt.MapClaims(refreshClaims), "exp")
+		if !rf.redeemed.redeem(jti, exp, now) {
+			return IssueResponse{}, httpError{err: ErrRefreshTokenRedeemed, code: http.StatusBadRequest}
+		}
+	}
+
+	return response, nil
+}
+
+// NewRefreshingIssueEndpoint returns a go-kit endpoint that issues access tokens along with refresh tokens
+func NewRefreshingIssueEndpoint(rf Refresher) endpoint.Endpoint {
+	return func(ctx context.Context, v any) (any, error) {
+		return rf.Issue(ctx, v.(*Request))
+	}
+}
+
+// NewRefreshEndpoint returns a go-kit endpoint that redeems refresh tokens
+func NewRefreshEndpoint(rf Refresher) endpoint.Endpoint {
+	return func(ctx context.Context, v any) (any, error) {
+		return rf.Refresh(ctx, v.(*RefreshRequest))
+	}
+}
+
+// DecodeRefreshRequest produces a RefreshRequest from a form post with the refresh token in
+// the RefreshTokenParameter.  The remainder of the HTTP request is decoded just as for
+// issue requests, in case the remote claims must be fetched again.
+func DecodeRefreshRequest(rb RequestBuilders) func(context.Context, *http.Request) (any, error) {
+	decode := DecodeServerRequest(rb)
+	return func(ctx context.Context, hr *http.Request) (any, error) {
+		tr, err := decode(ctx, hr)
+		if err != nil {
+			return nil, err
+		}
+
+		refreshToken := hr.PostForm.Get(RefreshTokenParameter)
+		if len(refreshToken) == 0 {
+			return nil, httpError{err: ErrNoRefreshToken, code: http.StatusBadRequest}
+		}
+
+		return &RefreshRequest{
+			Request:      tr.(*Request),
+			RefreshToken: refreshToken,
+		}, nil
+	}
+}
+
+type RefreshHandler http.Handler
+
+func NewRefreshHandler(e endpoint.Endpoint, rb RequestBuilders) RefreshHandler {
+	return kithttp.NewServer(
+		e,
+		DecodeRefreshRequest(rb),
+		EncodeIssueResponse,
+		kithttp.ServerBefore(func(ctx context.Context, r *http.Request) context.Context {
+			return WithTracingHeaders(ctx, r)
+		}),
+	)
+}
@@ -0,0 +1,469 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/key"
+	"github.com/xmidt-org/themis/v2/random"
+)
+
+// testRevokedMAC revokes tokens with a mac claim of "revoked"
+type testRevokedMAC struct{}
+
+func (testRevokedMAC) Revoked(claims map[string]any) bool {
+	return claims["mac"] == "revoked" && claims["iat"] != nil
+}
+
+func testNewRefresher(t *testing.T, o Options, r Revocations, remote ClaimBuilder) (*refresher, Verifier) {
+	var (
+		registry = key.NewRegistry(rand.Reader)
+		noncer   = random.NewBase64Noncer(nil, 0, nil)
+	)
+
+	o.Alg = "ES256"
+	o.Key = key.Descriptor{Kid: "test", Type: key.KeyTypeECDSA, Bits: 256}
+	o.Nonce = true
+	o.Duration = time.Hour
+	if o.Refresh == nil {
+		o.Refresh = &Refresh{}
+	}
+
+	cb := append(ClaimBuilders{requestClaimBuilder{}, staticClaimBuilder{ClaimTrust: 1000}}, newIssueClaimBuilders(noncer, o)...)
+	f, err := newFactory(o, cb, registry)
+	require.NoError(t, err)
+
+	return newRefresher(o, f, newRefreshVerifier(registry, o.Alg), r, remote, noncer), NewVerifier(registry, o.Alg)
+}
+
+func testRefreshRequest(refreshToken string) *RefreshRequest {
+	r := NewRequest()
+	r.Metadata["mac"] = "112233445566"
+	return &RefreshRequest{Request: r, RefreshToken: refreshToken}
+}
+
+func testRefreshIssue(t *testing.T, rf *refresher, mac string) IssueResponse {
+	r := NewRequest()
+	r.Claims["mac"] = mac
+	response, err := rf.Issue(context.Background(), r)
+	require.NoError(t, err)
+	require.NotEmpty(t, response.Token)
+	require.NotEmpty(t, response.RefreshToken)
+	return response
+}
+
+func testRefresherIssue(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		rf, v    = testNewRefresher(t, Options{}, nil, nil)
+		response = testRefreshIssue(t, rf, "112233445566")
+	)
+
+	access, err := v.Verify(response.Token)
+	require.NoError(err)
+	assert.Equal("112233445566", access["mac"])
+	assert.NotContains(access, ClaimTokenUse)
+
+	refresh, err := rf.verifier.Verify(response.RefreshToken)
+	require.NoError(err)
+	assert.Equal(TokenUseRefresh, refresh[ClaimTokenUse])
+	assert.NotEqual(access["jti"], refresh["jti"])
+	assert.Contains(refresh, ClaimCachedAt)
+
+	exp, ok, err := numericDate(refresh, "exp")
+	require.NoError(err)
+	require.True(ok)
+	assert.WithinDuration(time.Now().Add(DefaultRefreshDuration), exp, time.Minute)
+
+	// the access token claims are cached, except for those unique to each token
+	cached, ok := refresh[ClaimCachedClaims].(map[string]any)
+	require.True(ok)
+	assert.Equal("112233445566", cached["mac"])
+	assert.Contains(cached, ClaimTrust)
+	for claim := range reissuedClaims {
+		assert.NotContains(cached, claim)
+	}
+}
+
+func testRefresherRefresh(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		rf, v    = testNewRefresher(t, Options{}, nil, nil)
+		issued   = testRefreshIssue(t, rf, "112233445566")
+		original map[string]any
+	)
+
+	original, err := v.Verify(issued.Token)
+	require.NoError(err)
+
+	// without rotation, a refresh token can be redeemed repeatedly
+	for range 2 {
+		response, err := rf.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+		require.NoError(err)
+		assert.Empty(response.RefreshToken)
+
+		access, err := v.Verify(response.Token)
+		require.NoError(err)
+		assert.Equal("112233445566", access["mac"])
+		assert.Equal(original[ClaimTrust], access[ClaimTrust])
+		assert.NotEqual(original["jti"], access["jti"])
+		assert.Contains(access, "exp")
+		assert.NotContains(access, ClaimTokenUse)
+	}
+}
+
+func testRefresherBound(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		rf, v   = testNewRefresher(t, Options{}, nil, nil)
+
+		state, thumbprint = testPeerCertificate("device")
+		other, _          = testPeerCertificate("other")
+	)
+
+	// bind the issued tokens just as the certificate binding claim builder would
+	r := NewRequest()
+	r.Claims[ClaimConfirmation] = map[string]any{ConfirmationThumbprintS256: thumbprint}
+	issued, err := rf.Issue(context.Background(), r)
+	require.NoError(err)
+
+	unbound := testRefreshRequest(issued.RefreshToken)
+	_, err = rf.Refresh(context.Background(), unbound)
+	assert.ErrorIs(err, ErrNoClientCertificate)
+
+	wrong := testRefreshRequest(issued.RefreshToken)
+	wrong.Request.TLS = other
+	_, err = rf.Refresh(context.Background(), wrong)
+	assert.ErrorIs(err, ErrCertificateNotBound)
+
+	bound := testRefreshRequest(issued.RefreshToken)
+	bound.Request.TLS = state
+	response, err := rf.Refresh(context.Background(), bound)
+	require.NoError(err)
+
+	access, err := v.Verify(response.Token)
+	require.NoError(err)
+	assert.Equal(map[string]any{ConfirmationThumbprintS256: thumbprint}, access[ClaimConfirmation])
+}
+
+func testRefresherFormat(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		rf, _   = testNewRefresher(t, Options{CWT: &CWT{}}, nil, nil)
+		r       = NewRequest()
+	)
+
+	r.Format = FormatCWT
+	issued, err := rf.Issue(context.Background(), r)
+	require.NoError(err)
+	assert.True(isCWT(issued.Token))
+
+	refresh, err := rf.verifier.Verify(issued.RefreshToken)
+	require.NoError(err)
+	assert.Equal(FormatCWT, refresh[ClaimTokenFormat])
+
+	// without a format in the refresh request, the token is refreshed in the format it was issued in
+	response, err := rf.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+	require.NoError(err)
+	assert.True(isCWT(response.Token))
+
+	rr := testRefreshRequest(issued.RefreshToken)
+	rr.Request.Format = FormatJWT
+	response, err = rf.Refresh(context.Background(), rr)
+	require.NoError(err)
+	assert.False(isCWT(response.Token))
+}
+
+func testRefresherProfile(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		rf, _   = testNewRefresher(t, Options{}, nil, nil)
+	)
+
+	rf.profile = "devices"
+	issued := testRefreshIssue(t, rf, "112233445566")
+	refresh, err := rf.verifier.Verify(issued.RefreshToken)
+	require.NoError(err)
+	assert.Equal("devices", refresh[ClaimTokenProfile])
+
+	_, err = rf.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+	require.NoError(err)
+
+	// the default profile, and any other profile, shares the key registry but not the refresh tokens
+	for _, profile := range []string{"", "partners"} {
+		other := *rf
+		other.profile = profile
+		_, err = other.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+		assert.ErrorIs(err, ErrRefreshTokenProfile)
+
+		var sc interface{ StatusCode() int }
+		require.ErrorAs(err, &sc)
+		assert.Equal(http.StatusBadRequest, sc.StatusCode())
+	}
+
+	// refresh tokens of the default profile carry no profile
+	rf.profile = ""
+	issued = testRefreshIssue(t, rf, "112233445566")
+	refresh, err = rf.verifier.Verify(issued.RefreshToken)
+	require.NoError(err)
+	assert.NotContains(refresh, ClaimTokenProfile)
+}
+
+func testRefresherTrustPolicy(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		rf, v   = testNewRefresher(t, Options{}, nil, nil)
+		r       = NewRequest()
+	)
+
+	policy, err := newTrustPolicy(Options{
+		TrustPolicy: &TrustPolicy{
+			Rules: []TrustRule{{Reasons: []string{TrustedReason}, Duration: 5 * time.Minute}},
+		},
+	})
+
+	require.NoError(err)
+	rf.reissue = append(rf.reissue, policy)
+
+	r.TrustReason = TrustedReason
+	issued, err := rf.Issue(context.Background(), r)
+	require.NoError(err)
+
+	refresh, err := rf.verifier.Verify(issued.RefreshToken)
+	require.NoError(err)
+	assert.Equal(TrustedReason, refresh[ClaimTrustReason])
+
+	// the cached trust and its reason select the same rule when the refresh token is redeemed
+	response, err := rf.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+	require.NoError(err)
+
+	access, err := v.Verify(response.Token)
+	require.NoError(err)
+	exp, _, err := numericDate(access, "exp")
+	require.NoError(err)
+	assert.WithinDuration(time.Now().Add(5*time.Minute), exp, time.Minute)
+}
+
+func testRefresherRotate(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		rf, _   = testNewRefresher(t, Options{Refresh: &Refresh{Rotate: true}}, nil, nil)
+		issued  = testRefreshIssue(t, rf, "112233445566")
+	)
+
+	response, err := rf.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+	require.NoError(err)
+	require.NotEmpty(response.RefreshToken)
+	assert.NotEqual(issued.RefreshToken, response.RefreshToken)
+
+	refresh, err := rf.verifier.Verify(response.RefreshToken)
+	require.NoError(err)
+	assert.Equal("112233445566", refresh[ClaimCachedClaims].(map[string]any)["mac"])
+
+	// the redeemed refresh token cannot be used again
+	_, err = rf.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+	assert.ErrorIs(err, ErrRefreshTokenRedeemed)
+
+	_, err = rf.Refresh(context.Background(), testRefreshRequest(response.RefreshToken))
+	assert.NoError(err)
+
+	// redeemed refresh tokens are only tracked in memory, so another replica with the same keys,
+	// or this one after a restart, still accepts a redeemed refresh token
+	restarted := *rf
+	restarted.redeemed = &redeemed{expiry: make(map[string]time.Time)}
+	_, err = restarted.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+	assert.NoError(err)
+
+	_, err = restarted.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+	assert.ErrorIs(err, ErrRefreshTokenRedeemed)
+}
+
+func testRefresherRejected(t *testing.T) {
+	var (
+		rf, _  = testNewRefresher(t, Options{}, testRevokedMAC{}, nil)
+		issued = testRefreshIssue(t, rf, "112233445566")
+	)
+
+	testData := []struct {
+		name     string
+		token    string
+		expected error
+	}{
+		{"Malformed", "this is not a token", ErrInvalidRefreshToken},
+		{"AccessToken", issued.Token, ErrNotRefreshToken},
+		{"Revoked", testRefreshIssue(t, rf, "revoked").RefreshToken, ErrTokenRevoked},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			response, err := rf.Refresh(context.Background(), testRefreshRequest(record.token))
+			assert.ErrorIs(t, err, record.expected)
+			assert.Empty(t, response)
+
+			var sc interface{ StatusCode() int }
+			require.True(t, errors.As(err, &sc))
+			assert.Equal(t, http.StatusBadRequest, sc.StatusCode())
+		})
+	}
+}
+
+func testRefresherRemoteMaxAge(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		calls   int
+		remote  = ClaimBuilderFunc(func(_ context.Context, r *Request, target map[string]any) error {
+			calls++
+			target["remote"] = r.Metadata["mac"]
+			return nil
+		})
+
+		rf, v  = testNewRefresher(t, Options{Refresh: &Refresh{Rotate: true, RemoteMaxAge: time.Hour}}, nil, remote)
+		issued = testRefreshIssue(t, rf, "112233445566")
+	)
+
+	// the cached claims are fresh, so the remote claims are not fetched
+	response, err := rf.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+	require.NoError(err)
+	assert.Zero(calls)
+
+	access, err := v.Verify(response.Token)
+	require.NoError(err)
+	assert.NotContains(access, "remote")
+
+	// claims cached before the maximum age are fetched again
+	rf.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+	issued = testRefreshIssue(t, rf, "112233445566")
+	rf.now = time.Now
+
+	response, err = rf.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+	require.NoError(err)
+	assert.Equal(1, calls)
+
+	refresh, err := rf.verifier.Verify(response.RefreshToken)
+	require.NoError(err)
+	assert.Equal("112233445566", refresh[ClaimCachedClaims].(map[string]any)["remote"])
+
+	cachedAt, _, err := numericDate(refresh, ClaimCachedAt)
+	require.NoError(err)
+	assert.WithinDuration(time.Now(), cachedAt, time.Minute)
+}
+
+func testRefresherRemoteError(t *testing.T) {
+	var (
+		expected = errors.New("expected")
+		remote   = ClaimBuilderFunc(func(context.Context, *Request, map[string]any) error {
+			return expected
+		})
+
+		rf, _ = testNewRefresher(t, Options{Refresh: &Refresh{Rotate: true, RemoteMaxAge: time.Hour}}, nil, remote)
+	)
+
+	rf.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+	issued := testRefreshIssue(t, rf, "112233445566")
+	rf.now = time.Now
+
+	_, err := rf.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+	assert.ErrorIs(t, err, expected)
+
+	// a failed refresh does not redeem the refresh token
+	rf.remote = nil
+	_, err = rf.Refresh(context.Background(), testRefreshRequest(issued.RefreshToken))
+	assert.NoError(t, err)
+}
+
+func TestRefresher(t *testing.T) {
+	t.Run("Issue", testRefresherIssue)
+	t.Run("Refresh", testRefresherRefresh)
+	t.Run("Bound", testRefresherBound)
+	t.Run("Format", testRefresherFormat)
+	t.Run("Profile", testRefresherProfile)
+	t.Run("TrustPolicy", testRefresherTrustPolicy)
+	t.Run("Rotate", testRefresherRotate)
+	t.Run("Rejected", testRefresherRejected)
+	t.Run("RemoteMaxAge", testRefresherRemoteMaxAge)
+	t.Run("RemoteError", testRefresherRemoteError)
+}
+
+func TestRedeemed(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		now    = time.Now()
+		rd     = redeemed{expiry: make(map[string]time.Time)}
+	)
+
+	assert.True(rd.redeem("first", now.Add(time.Minute), now))
+	assert.False(rd.redeem("first", now.Add(time.Minute), now))
+	assert.True(rd.redeem("second", now.Add(time.Hour), now))
+
+	// expired refresh tokens are eventually forgotten
+	now = now.Add(2 * redeemedPruneInterval)
+	assert.True(rd.redeem("third", now.Add(time.Hour), now))
+	assert.NotContains(rd.expiry, "first")
+	assert.Contains(rd.expiry, "second")
+}
+
+func TestNewRefreshHandler(t *testing.T) {
+	var (
+		rf, v   = testNewRefresher(t, Options{Refresh: &Refresh{Rotate: true}}, nil, nil)
+		issue   = NewIssueHandler(NewRefreshingIssueEndpoint(rf), RequestBuilders{})
+		handler = NewRefreshHandler(NewRefreshEndpoint(rf), RequestBuilders{})
+
+		refresh = func(form url.Values) *httptest.ResponseRecorder {
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("POST", "/refresh", strings.NewReader(form.Encode()))
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			handler.ServeHTTP(response, request)
+			return response
+		}
+	)
+
+	response := httptest.NewRecorder()
+	issue.ServeHTTP(response, httptest.NewRequest("GET", "/issue", nil))
+	require.Equal(t, http.StatusOK, response.Code)
+	refreshToken := response.Header().Get(RefreshTokenHeader)
+	require.NotEmpty(t, refreshToken)
+
+	t.Run("Success", func(t *testing.T) {
+		var (
+			assert   = assert.New(t)
+			require  = require.New(t)
+			response = refresh(url.Values{RefreshTokenParameter: {refreshToken}})
+		)
+
+		require.Equal(http.StatusOK, response.Code)
+		assert.Equal("application/jose", response.Header().Get("Content-Type"))
+		assert.NotEmpty(response.Header().Get(RefreshTokenHeader))
+
+		_, err := v.Verify(response.Body.String())
+		assert.NoError(err)
+	})
+
+	t.Run("Redeemed", func(t *testing.T) {
+		response := refresh(url.Values{RefreshTokenParameter: {refreshToken}})
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("NoRefreshToken", func(t *testing.T) {
+		response := refresh(url.Values{})
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var (
+	ErrUnsupportedSignerAlg = errors.New("signing algorithm is not supported by crypto.Signer")
+	ErrSignerKeyMismatch    = errors.New("signer key is not compatible with the signing algorithm")
+)
+
+// signerHashes maps the JWA algorithms that can be used with a crypto.Signer onto their hash functions.
+// EdDSA signs the message itself, so it has no hash.
+var signerHashes = map[string]crypto.Hash{
+	"RS256":  crypto.SHA256,
+	"RS384":  crypto.SHA384,
+	"RS512":  crypto.SHA512,
+	"PS256":  crypto.SHA256,
+	"PS384":  crypto.SHA384,
+	"PS512":  crypto.SHA512,
+	"ES256":  crypto.SHA256,
+	"ES384":  crypto.SHA384,
+	"ES512":  crypto.SHA512,
+	AlgEdDSA: crypto.Hash(0),
+}
+
+// ecdsaKeySizes is the size in bytes of each of the R and S values for the ECDSA algorithms
+var ecdsaKeySizes = map[string]int{
+	"ES256": 32,
+	"ES384": 48,
+	"ES512": 66,
+}
+
+// signToken produces the compact serialization of a token, delegating the signature to a crypto.Signer.
+// This allows keys to be held outside of this process, e.g. in an HSM or KMS.
+func signToken(token *jwt.Token, signer crypto.Signer) (string, error) {
+	signingString, err := token.SigningString()
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := signJWS(token.Method.Alg(), signer, []byte(signingString))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{signingString, jwt.EncodeSegment(signature)}, "."), nil
+}
+
+// signJWS computes the JWS signature of a signing input for the given algorithm
+func signJWS(alg string, signer crypto.Signer, input []byte) ([]byte, error) {
+	hash, ok := signerHashes[alg]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSignerAlg, alg)
+	}
+
+	digest := input
+	if hash != 0 {
+		h := hash.New()
+		h.Write(input)
+		digest = h.Sum(nil)
+	}
+
+	var opts crypto.SignerOpts = hash
+	switch public := signer.Public(); {
+	case alg == AlgEdDSA:
+		if _, ok := public.(ed25519.PublicKey); !ok {
+			return nil, fmt.Errorf("%w: %s cannot be used with %T", ErrSignerKeyMismatch, alg, public)
+		}
+
+	case alg[0] == 'E':
+		return signECDSA(alg, signer, hash, digest)
+
+	default:
+		if _, ok := public.(*rsa.PublicKey); !ok {
+			return nil, fmt.Errorf("%w: %s cannot be used with %T", ErrSignerKeyMismatch, alg, public)
+		}
+
+		if alg[0] == 'P' {
+			opts = &rsa.PSSOptions{
+				SaltLength: rsa.PSSSaltLengthEqualsHash,
+				Hash:       hash,
+			}
+		}
+	}
+
+	return signer.Sign(rand.Reader, digest, opts)
+}
+
+// signECDSA produces a JWS ECDSA signature, which is the fixed-width concatenation of R and S
+// rather than the ASN.1 structure returned by crypto.Signer.
+func signECDSA(alg string, signer crypto.Signer, hash crypto.Hash, digest []byte) ([]byte, error) {
+	size := ecdsaKeySizes[alg]
+	public, ok := signer.Public().(*ecdsa.PublicKey)
+	if !ok || (public.Curve.Params().BitSize+7)/8 != size {
+		return nil, fmt.Errorf("%w: %s requires a %d-byte ecdsa curve", ErrSignerKeyMismatch, alg, size)
+	}
+
+	der, err := signer.Sign(rand.Reader, digest, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var rs struct {
+		R, S *big.Int
+	}
+
+	if rest, err := asn1.Unmarshal(der, &rs); err != nil {
+		return nil, err
+	} else if len(rest) > 0 {
+		return nil, errors.New("trailing data after ecdsa signature")
+	}
+
+	signature := make([]byte, 2*size)
+	rs.R.FillBytes(signature[:size])
+	rs.S.FillBytes(signature[size:])
+	return signature, nil
+}
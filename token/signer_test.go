@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSignTokenSuccess(t *testing.T, alg string, signer crypto.Signer) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		token = jwt.NewWithClaims(jwt.GetSigningMethod(alg), jwt.MapClaims{"sub": "test"})
+	)
+
+	signed, err := signToken(token, signer)
+	require.NoError(err)
+
+	parsed, err := jwt.Parse(signed, func(*jwt.Token) (any, error) {
+		return signer.Public(), nil
+	})
+
+	require.NoError(err)
+	assert.True(parsed.Valid)
+	assert.Equal(alg, parsed.Header["alg"])
+}
+
+func testSignTokenMismatch(t *testing.T, alg string, signer crypto.Signer) {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(alg), jwt.MapClaims{})
+	signed, err := signToken(token, signer)
+	assert.Empty(t, signed)
+	assert.ErrorIs(t, err, ErrSignerKeyMismatch)
+}
+
+func TestSignToken(t *testing.T) {
+	var (
+		rsaKey, rsaErr     = rsa.GenerateKey(rand.Reader, 2048)
+		p256Key, p256Err   = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		p384Key, p384Err   = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		p521Key, p521Err   = ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+		_, edKey, edErr    = ed25519.GenerateKey(rand.Reader)
+		requiredNoErrors   = []error{rsaErr, p256Err, p384Err, p521Err, edErr}
+		successfulSigners  = map[string]crypto.Signer{}
+		mismatchedSigners  = map[string]crypto.Signer{}
+		unsupportedSigners = []string{"HS256", "none"}
+	)
+
+	for _, err := range requiredNoErrors {
+		require.NoError(t, err)
+	}
+
+	for _, alg := range []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"} {
+		successfulSigners[alg] = rsaKey
+	}
+
+	successfulSigners["ES256"] = p256Key
+	successfulSigners["ES384"] = p384Key
+	successfulSigners["ES512"] = p521Key
+	successfulSigners[AlgEdDSA] = edKey
+
+	mismatchedSigners["RS256"] = p256Key
+	mismatchedSigners["PS256"] = edKey
+	mismatchedSigners["ES256"] = p384Key
+	mismatchedSigners["ES384"] = rsaKey
+	mismatchedSigners[AlgEdDSA] = rsaKey
+
+	t.Run("Success", func(t *testing.T) {
+		for alg, signer := range successfulSigners {
+			t.Run(alg, func(t *testing.T) {
+				testSignTokenSuccess(t, alg, signer)
+			})
+		}
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		for alg, signer := range mismatchedSigners {
+			t.Run(alg, func(t *testing.T) {
+				testSignTokenMismatch(t, alg, signer)
+			})
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		for _, alg := range unsupportedSigners {
+			t.Run(alg, func(t *testing.T) {
+				_, err := signJWS(alg, rsaKey, []byte("test"))
+				assert.ErrorIs(t, err, ErrUnsupportedSignerAlg)
+			})
+		}
+	})
+}
@@ -0,0 +1,256 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/xmidt-org/themis/v2/expression"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidTransform = errors.New("invalid claim transform")
+	ErrTransformFailed  = errors.New("claim transform failed")
+)
+
+// Transform derives a claim from the request, its TLS state, and the claims added by every other
+// claim builder.  A transform's value is a template, and its optional guard is a condition, e.g.:
+//
+//	claim: sub
+//	value: 'mac:${claims.mac | lower}'
+//
+// The following variables are available to templates and conditions:
+//
+//	claims   any     the claims so far, including those set by earlier transforms
+//	request  object  format, trustReason, dpopThumbprint, metadata, pathWildCards and queryParameters
+//	tls      object  present, verified, version, serverName, subject, issuer, serialNumber, dnsNames,
+//	                 notBefore and notAfter, describing the client certificate if there is one
+//
+// Templates and conditions are compiled and checked at startup, so that mistakes are reported before
+// any token is issued.  See the expression package for their syntax.
+type Transform struct {
+	// Claim is the name of the claim this transform sets or deletes.  This field is required,
+	// and cannot be cnf.
+	Claim string
+
+	// If is the optional condition that guards this transform.  If unset, this transform always applies.
+	If string
+
+	// Value is the template whose result becomes the claim's value.  A template that evaluates to
+	// null deletes the claim.  Exactly one of Value or Delete must be set.
+	Value string
+
+	// Delete indicates that this transform deletes the claim rather than setting it.
+	Delete bool
+}
+
+// transformVariables are the variables, and their types, available to transforms
+var transformVariables = map[string]*expression.Type{
+	"claims": expression.Any,
+	"request": expression.Object(map[string]*expression.Type{
+		"format":          expression.String,
+		"trustReason":     expression.String,
+		"dpopThumbprint":  expression.String,
+		"metadata":        expression.Any,
+		"pathWildCards":   expression.Any,
+		"queryParameters": expression.Any,
+	}),
+	"tls": expression.Object(map[string]*expression.Type{
+		"present":      expression.Bool,
+		"verified":     expression.Bool,
+		"version":      expression.String,
+		"serverName":   expression.String,
+		"subject":      expression.String,
+		"issuer":       expression.String,
+		"serialNumber": expression.String,
+		"dnsNames":     expression.List,
+		"notBefore":    expression.Number,
+		"notAfter":     expression.Number,
+	}),
+}
+
+// transformRequestInputs are the variables holding the values a client sent in its headers and
+// parameters.  A transform that fails because of one of these values is the client's fault.
+var transformRequestInputs = []string{
+	"request.metadata.",
+	"request.pathWildCards.",
+	"request.queryParameters.",
+}
+
+// newTransformClaimBuilder compiles the configured transforms.  If there are no transforms,
+// this function returns nil.
+func newTransformClaimBuilder(o Options) (*transformClaimBuilder, error) {
+	if len(o.Transforms) == 0 {
+		return nil, nil
+	}
+
+	tcb := &transformClaimBuilder{
+		transforms: make([]transform, 0, len(o.Transforms)),
+	}
+
+	for i, t := range o.Transforms {
+		switch {
+		case len(t.Claim) == 0:
+			return nil, fmt.Errorf("transform %d: %w: no claim", i, ErrInvalidTransform)
+		case t.Claim == ClaimConfirmation:
+			return nil, fmt.Errorf("transform %d: %w: %w", i, ErrInvalidTransform, ErrConfirmationClaim)
+		case len(t.Value) > 0 && t.Delete:
+			return nil, fmt.Errorf("transform %d (%s): %w: both a value and delete are set", i, t.Claim, ErrInvalidTransform)
+		case len(t.Value) == 0 && !t.Delete:
+			return nil, fmt.Errorf("transform %d (%s): %w: either a value or delete must be set", i, t.Claim, ErrInvalidTransform)
+		}
+
+		compiled := transform{claim: t.Claim}
+		if len(t.If) > 0 {
+			c, err := expression.CompileCondition(t.If, transformVariables)
+			if err != nil {
+				return nil, fmt.Errorf("transform %d (%s): %w: if: %w", i, t.Claim, ErrInvalidTransform, err)
+			}
+
+			compiled.condition = c
+		}
+
+		if len(t.Value) > 0 {
+			v, err := expression.CompileTemplate(t.Value, transformVariables)
+			if err != nil {
+				return nil, fmt.Errorf("transform %d (%s): %w: value: %w", i, t.Claim, ErrInvalidTransform, err)
+			}
+
+			compiled.value = v
+		}
+
+		tcb.transforms = append(tcb.transforms, compiled)
+	}
+
+	return tcb, nil
+}
+
+type transform struct {
+	claim     string
+	condition *expression.Condition
+	value     *expression.Template
+}
+
+// apply runs this transform against the claims, which are updated in place
+func (t transform) apply(vars map[string]any, target map[string]any) error {
+	if t.condition != nil {
+		if applies, err := t.condition.Eval(vars); err != nil || !applies {
+			return err
+		}
+	}
+
+	if t.value == nil {
+		delete(target, t.claim)
+		return nil
+	}
+
+	result, err := t.value.Eval(vars)
+	switch {
+	case err != nil:
+		return err
+	case result == nil:
+		delete(target, t.claim)
+	default:
+		target[t.claim] = result
+	}
+
+	return nil
+}
+
+// transformClaimBuilder is a ClaimBuilder that applies transforms, in order, to the claims.
+// It must run after every claim builder whose claims the transforms read or override.
+type transformClaimBuilder struct {
+	transforms []transform
+}
+
+func (tcb *transformClaimBuilder) AddClaims(_ context.Context, r *Request, target map[string]any) error {
+	vars := map[string]any{
+		"claims": target,
+		"request": map[string]any{
+			"format":          r.Format,
+			"trustReason":     r.TrustReason,
+			"dpopThumbprint":  r.DPoPThumbprint,
+			"metadata":        r.Metadata,
+			"pathWildCards":   r.PathWildCards,
+			"queryParameters": r.QueryParameters,
+		},
+		"tls": transformTLS(r.TLS),
+	}
+
+	for _, t := range tcb.transforms {
+		if err := t.apply(vars, target); err != nil {
+			return transformError(r, t.claim, err)
+		}
+	}
+
+	return nil
+}
+
+// transformError reports a failed transform.  A failure caused by a value the client sent is a 400,
+// and its cause is returned to the client.  Any other failure, such as a missing claim or one of the
+// wrong type, is a problem with the configuration or with another source of claims.  Those are a 500,
+// and their details are only logged.
+func transformError(r *Request, claim string, err error) error {
+	var evalErr *expression.EvalError
+	if errors.As(err, &evalErr) && slices.ContainsFunc(transformRequestInputs, func(prefix string) bool {
+		return strings.HasPrefix(evalErr.Reference, prefix)
+	}) {
+		return httpError{
+			err:  fmt.Errorf("%w: %s: %w", ErrTransformFailed, claim, evalErr),
+			code: http.StatusBadRequest,
+		}
+	}
+
+	r.Logger.Error("claim transform failed", zap.String("claim", claim), zap.Error(err))
+	return httpError{
+		err:  ErrTransformFailed,
+		code: http.StatusInternalServerError,
+	}
+}
+
+// transformTLS describes the TLS state, and the client certificate if there is one, to transforms
+func transformTLS(state *tls.ConnectionState) map[string]any {
+	v := map[string]any{
+		"present":      false,
+		"verified":     false,
+		"version":      "",
+		"serverName":   "",
+		"subject":      "",
+		"issuer":       "",
+		"serialNumber": "",
+		"dnsNames":     []string{},
+		"notBefore":    0,
+		"notAfter":     0,
+	}
+
+	if state == nil {
+		return v
+	}
+
+	v["version"] = tls.VersionName(state.Version)
+	v["serverName"] = state.ServerName
+	v["verified"] = len(state.VerifiedChains) > 0
+	if len(state.PeerCertificates) == 0 {
+		return v
+	}
+
+	cert := state.PeerCertificates[0]
+	v["present"] = true
+	v["subject"] = cert.Subject.CommonName
+	v["issuer"] = cert.Issuer.CommonName
+	if cert.SerialNumber != nil {
+		v["serialNumber"] = cert.SerialNumber.String()
+	}
+
+	v["dnsNames"] = append([]string{}, cert.DNSNames...)
+	v["notBefore"] = cert.NotBefore.Unix()
+	v["notAfter"] = cert.NotAfter.Unix()
+	return v
+}
@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/sallust"
+	"github.com/xmidt-org/themis/v2/expression"
+)
+
+func TestNewTransformClaimBuilder(t *testing.T) {
+	t.Run("Unset", func(t *testing.T) {
+		tcb, err := newTransformClaimBuilder(Options{})
+		assert.Nil(t, tcb)
+		assert.NoError(t, err)
+	})
+
+	testData := []struct {
+		name      string
+		transform Transform
+		expected  error
+	}{
+		{"NoClaim", Transform{Value: "1"}, ErrInvalidTransform},
+		{"ConfirmationClaim", Transform{Claim: ClaimConfirmation, Value: "${claims.mac}"}, ErrConfirmationClaim},
+		{"ValueAndDelete", Transform{Claim: "sub", Value: "1", Delete: true}, ErrInvalidTransform},
+		{"NoValueOrDelete", Transform{Claim: "sub"}, ErrInvalidTransform},
+		{"IfSyntax", Transform{Claim: "sub", If: "claims.", Delete: true}, expression.ErrSyntax},
+		{"IfType", Transform{Claim: "sub", If: "request.nosuch exists", Delete: true}, expression.ErrType},
+		{"IfNotBool", Transform{Claim: "sub", If: "request.format", Delete: true}, expression.ErrType},
+		{"ValueSyntax", Transform{Claim: "sub", Value: "mac:${claims.mac"}, expression.ErrSyntax},
+		{"ValueType", Transform{Claim: "sub", Value: "${tls.notAfter | lower}"}, expression.ErrType},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			tcb, err := newTransformClaimBuilder(Options{Transforms: []Transform{record.transform}})
+			assert.Nil(t, tcb)
+			assert.ErrorIs(t, err, ErrInvalidTransform)
+			assert.ErrorIs(t, err, record.expected)
+		})
+	}
+}
+
+func testTransformClaimBuilderSuccess(t *testing.T) {
+	notBefore := time.Unix(1700000000, 0)
+	state := &tls.ConnectionState{
+		Version:    tls.VersionTLS13,
+		ServerName: "themis.example.com",
+		PeerCertificates: []*x509.Certificate{{
+			Subject:      pkix.Name{CommonName: "device"},
+			Issuer:       pkix.Name{CommonName: "issuer"},
+			SerialNumber: big.NewInt(1234),
+			DNSNames:     []string{"device.example.com"},
+			NotBefore:    notBefore,
+			NotAfter:     notBefore.Add(time.Hour),
+		}},
+	}
+
+	testData := []struct {
+		name       string
+		transforms []Transform
+		state      *tls.ConnectionState
+		claims     map[string]any
+		expected   map[string]any
+	}{
+		{
+			name:       "Derive",
+			transforms: []Transform{{Claim: "sub", Value: "mac:${claims.mac | lower}"}},
+			claims:     map[string]any{"mac": "AABBCC"},
+			expected:   map[string]any{"mac": "AABBCC", "sub": "mac:aabbcc"},
+		},
+		{
+			name: "Sequential",
+			transforms: []Transform{
+				{Claim: "sub", Value: "mac:${claims.mac}"},
+				{Claim: "subject", Value: "${claims.sub}/${request.format}"},
+				{Claim: "mac", Delete: true},
+			},
+			claims:   map[string]any{"mac": "aabbcc"},
+			expected: map[string]any{"sub": "mac:aabbcc", "subject": "mac:aabbcc/" + FormatJWT},
+		},
+		{
+			name:       "DeleteIf",
+			transforms: []Transform{{Claim: "capabilities", If: "claims.trust < 500", Delete: true}},
+			claims:     map[string]any{"trust": 100, "capabilities": json.RawMessage(`["all"]`)},
+			expected:   map[string]any{"trust": 100},
+		},
+		{
+			name:       "DeleteIfNot",
+			transforms: []Transform{{Claim: "capabilities", If: "claims.trust < 500", Delete: true}},
+			claims:     map[string]any{"trust": 1000, "capabilities": json.RawMessage(`["all"]`)},
+			expected:   map[string]any{"trust": 1000, "capabilities": json.RawMessage(`["all"]`)},
+		},
+		{
+			name:       "DeleteMissing",
+			transforms: []Transform{{Claim: "capabilities", Delete: true}},
+			claims:     map[string]any{},
+			expected:   map[string]any{},
+		},
+		{
+			name:       "Null",
+			transforms: []Transform{{Claim: "mac", Value: "${claims.mac}"}},
+			claims:     map[string]any{"mac": nil},
+			expected:   map[string]any{},
+		},
+		{
+			name:       "Copy",
+			transforms: []Transform{{Claim: "scope", If: "claims.capabilities exists", Value: "${claims.capabilities}"}},
+			claims:     map[string]any{"capabilities": json.RawMessage(`["all"]`)},
+			expected:   map[string]any{"capabilities": json.RawMessage(`["all"]`), "scope": []any{"all"}},
+		},
+		{
+			name:       "NoTLS",
+			transforms: []Transform{{Claim: "tls", Value: "${tls}"}},
+			claims:     map[string]any{},
+			expected: map[string]any{"tls": map[string]any{
+				"present": false, "verified": false, "version": "", "serverName": "", "subject": "", "issuer": "",
+				"serialNumber": "", "dnsNames": []any{}, "notBefore": int64(0), "notAfter": int64(0),
+			}},
+		},
+		{
+			name:       "TLS",
+			transforms: []Transform{{Claim: "tls", If: "tls.present", Value: "${tls}"}},
+			state:      state,
+			claims:     map[string]any{},
+			expected: map[string]any{"tls": map[string]any{
+				"present": true, "verified": false, "version": "TLS 1.3", "serverName": "themis.example.com",
+				"subject": "device", "issuer": "issuer", "serialNumber": "1234", "dnsNames": []any{"device.example.com"},
+				"notBefore": int64(1700000000), "notAfter": int64(1700003600),
+			}},
+		},
+		{
+			name:       "NoCertificate",
+			transforms: []Transform{{Claim: "tls", If: "tls.present", Value: "${tls}"}},
+			state:      &tls.ConnectionState{Version: tls.VersionTLS12},
+			claims:     map[string]any{},
+			expected:   map[string]any{},
+		},
+		{
+			name: "Request",
+			transforms: []Transform{
+				{Claim: "serial", Value: "${request.metadata.serial}"},
+				{Claim: "trust_reason", Value: "${request.trustReason}"},
+			},
+			claims:   map[string]any{},
+			expected: map[string]any{"serial": "ABC", "trust_reason": UntrustedReason},
+		},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			tcb, err := newTransformClaimBuilder(Options{Transforms: record.transforms})
+			require.NoError(t, err)
+			require.NotNil(t, tcb)
+
+			r := NewRequest()
+			r.TLS = record.state
+			r.Format = FormatJWT
+			r.TrustReason = UntrustedReason
+			r.Metadata["serial"] = "ABC"
+			require.NoError(t, tcb.AddClaims(context.Background(), r, record.claims))
+			assert.Equal(t, record.expected, record.claims)
+		})
+	}
+}
+
+func testTransformClaimBuilderError(t *testing.T) {
+	testData := []struct {
+		name      string
+		transform Transform
+		claims    map[string]any
+		expected  int
+	}{
+		{"MissingClaim", Transform{Claim: "sub", Value: "mac:${claims.mac}"}, map[string]any{}, http.StatusInternalServerError},
+		{"WrongType", Transform{Claim: "sub", Value: "${claims.mac | lower}"}, map[string]any{"mac": 1}, http.StatusInternalServerError},
+		{"IfNotBool", Transform{Claim: "sub", If: "claims.mac", Delete: true}, map[string]any{"mac": "aabbcc"}, http.StatusInternalServerError},
+		{"UnsupportedClaim", Transform{Claim: "sub", Value: "${claims.mac}"}, map[string]any{"mac": make(chan int)}, http.StatusInternalServerError},
+		{"MissingParameter", Transform{Claim: "sub", Value: "mac:${request.queryParameters.mac}"}, map[string]any{}, http.StatusBadRequest},
+		{"MissingMetadata", Transform{Claim: "sub", If: "request.metadata.mac == \"a\"", Delete: true}, map[string]any{}, http.StatusBadRequest},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			tcb, err := newTransformClaimBuilder(Options{Transforms: []Transform{record.transform}})
+			require.NoError(t, err)
+
+			err = tcb.AddClaims(context.Background(), NewRequest(), record.claims)
+			assert.ErrorIs(t, err, ErrTransformFailed)
+
+			var coder interface{ StatusCode() int }
+			require.True(t, errors.As(err, &coder))
+			assert.Equal(t, record.expected, coder.StatusCode())
+			if record.expected == http.StatusInternalServerError {
+				// the details of server side failures are logged, never returned to the client
+				assert.Equal(t, ErrTransformFailed.Error(), err.Error())
+			}
+		})
+	}
+}
+
+func TestTransformClaimBuilder(t *testing.T) {
+	t.Run("Success", testTransformClaimBuilderSuccess)
+	t.Run("Error", testTransformClaimBuilderError)
+}
+
+func TestNewClaimBuildersTransforms(t *testing.T) {
+	builders, err := NewClaimBuilders(nil, nil, Options{
+		DisableTime: true,
+		PartnerID:   &PartnerID{},
+		Claims:      []Value{{Key: "capabilities", Value: []string{"all"}}},
+		Transforms: []Transform{
+			{Claim: "capabilities", If: "claims.trust < 500", Delete: true},
+			{Claim: "sub", Value: "mac:${claims.mac | lower}"},
+		},
+	}, true, nil, nil, nil)
+
+	require.NoError(t, err)
+
+	r := &Request{Logger: sallust.Default(), Claims: map[string]any{"mac": "AABBCC", ClaimTrust: 100}}
+	actual := make(map[string]any)
+	require.NoError(t, builders.AddClaims(context.Background(), r, actual))
+	assert.Equal(t, map[string]any{"mac": "AABBCC", ClaimTrust: 100, "sub": "mac:aabbcc"}, actual)
+
+	_, err = NewClaimBuilders(nil, nil, Options{PartnerID: &PartnerID{}, Transforms: []Transform{{Claim: "sub"}}}, true, nil, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidTransform)
+}
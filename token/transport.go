@@ -9,9 +9,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/go-kit/kit/endpoint"
@@ -26,6 +28,10 @@ import (
 
 var (
 	ErrVariableNotAllowed                  = errors.New("either header/parameter or variable can specified, but not all three")
+	ErrInvalidValueType                    = errors.New("the type of a value must be one of string, int, float, bool, stringList, or json")
+	ErrTypeNotAllowed                      = errors.New("only header and parameter values can have a type")
+	ErrSeparatorNotAllowed                 = errors.New("only list values can have a separator")
+	ErrInvalidValue                        = errors.New("invalid value")
 	ErrRemoteClaimsRequestEncodingFailure  = errors.New("failed to encode remote claims request")
 	ErrRemoteClaimsResponseDecodingFailure = errors.New("failed to decode response from remote claims endpoint")
 )
@@ -137,6 +143,13 @@ type headerParameterRequestBuilder struct {
 	header    string
 	parameter string
 	setter    func(string, any, *Request)
+
+	// typed indicates that values are converted to valueType, or to a list of
+	// valueType if list is set, rather than just using the first value as is
+	typed     bool
+	valueType string
+	list      bool
+	separator string
 }
 
 func (hprb headerParameterRequestBuilder) Build(original *http.Request, tr *Request) error {
@@ -144,8 +157,7 @@ func (hprb headerParameterRequestBuilder) Build(original *http.Request, tr *Requ
 		value := original.Header[hprb.header]
 		if len(value) > 0 {
 			tr.Logger = tr.Logger.With(zap.Strings(headerClaimsLoggerFieldPrefix+hprb.key, value))
-			hprb.setter(hprb.key, value[0], tr)
-			return nil
+			return hprb.set("header "+hprb.header, value, tr)
 		}
 	}
 
@@ -153,14 +165,104 @@ func (hprb headerParameterRequestBuilder) Build(original *http.Request, tr *Requ
 		value := original.Form[hprb.parameter]
 		if len(value) > 0 {
 			tr.Logger = tr.Logger.With(zap.Strings(parameterClaimsLoggerFieldPrefix+hprb.key, value))
-			hprb.setter(hprb.key, value[0], tr)
-			return nil
+			return hprb.set("parameter "+hprb.parameter, value, tr)
 		}
 	}
 
 	return nil
 }
 
+// set converts the values of a header or parameter, if this builder is typed, and sets the result
+func (hprb headerParameterRequestBuilder) set(source string, values []string, tr *Request) error {
+	if !hprb.typed {
+		hprb.setter(hprb.key, values[0], tr)
+		return nil
+	}
+
+	value, err := hprb.convert(values)
+	if err != nil {
+		return BuildError{Err: fmt.Errorf("%w: %s: %w", ErrInvalidValue, source, err)}
+	}
+
+	hprb.setter(hprb.key, value, tr)
+	return nil
+}
+
+// convert produces either a single value or, for list builders, a list of values
+func (hprb headerParameterRequestBuilder) convert(values []string) (any, error) {
+	if !hprb.list {
+		if len(values) > 1 {
+			return nil, fmt.Errorf("expected a single value, found %d", len(values))
+		}
+
+		return convertValue(hprb.valueType, values[0])
+	}
+
+	list := make([]any, 0, len(values))
+	for _, v := range values {
+		elements := []string{v}
+		if len(hprb.separator) > 0 {
+			elements = strings.Split(v, hprb.separator)
+		}
+
+		for _, e := range elements {
+			if len(hprb.separator) > 0 {
+				if e = strings.TrimSpace(e); len(e) == 0 {
+					continue
+				}
+			}
+
+			converted, err := convertValue(hprb.valueType, e)
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, converted)
+		}
+	}
+
+	return list, nil
+}
+
+// convertValue converts a single header or parameter value to one of the value types
+func convertValue(valueType, value string) (any, error) {
+	switch valueType {
+	case ValueTypeInt:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an int", value)
+		}
+
+		return i, nil
+
+	case ValueTypeFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, fmt.Errorf("%q is not a finite float", value)
+		}
+
+		return f, nil
+
+	case ValueTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a bool", value)
+		}
+
+		return b, nil
+
+	case ValueTypeJSON:
+		if !json.Valid([]byte(value)) {
+			return nil, fmt.Errorf("%q is not valid JSON", value)
+		}
+
+		return json.RawMessage(value), nil
+
+	default:
+		return value, nil
+	}
+}
+
 type variableRequestBuilder struct {
 	key      string
 	variable string
@@ -287,6 +389,24 @@ func NewRequestBuilders(o Options) (rbs RequestBuilders, errs error) {
 		rbs = append(rbs, partnerIDRequestBuilder{PartnerID: *o.PartnerID})
 	}
 
+	if o.DPoP != nil {
+		drb, err := newDPoPRequestBuilder(*o.DPoP)
+		if err != nil {
+			return nil, err
+		}
+
+		rbs = append(rbs, drb)
+	}
+
+	if o.CWT != nil || o.PASETO != nil {
+		preferred, err := defaultFormat(o)
+		if err != nil {
+			return nil, err
+		}
+
+		rbs = append(rbs, formatRequestBuilder{preferred: preferred, cwt: o.CWT != nil})
+	}
+
 	return append(rbs, RequestBuilderFunc(setConnectionState)), nil
 }
 
@@ -303,6 +423,10 @@ func newRequestBuilders(values []Value, setter func(string, any, *Request)) (rbs
 				header:    http.CanonicalHeaderKey(v.Header),
 				parameter: v.Parameter,
 				setter:    setter,
+				typed:     v.IsTyped(),
+				valueType: v.ValueType(),
+				list:      v.IsList(),
+				separator: v.Separator,
 			})
 		} else {
 			rbs = append(rbs, variableRequestBuilder{
@@ -362,9 +486,32 @@ func DecodeServerRequest(rb RequestBuilders) func(context.Context, *http.Request
 	}
 }
 
+// EncodeIssueResponse writes an issued token, which is either a string or an IssueResponse.  Any
+// refresh token in an IssueResponse is written to the RefreshTokenHeader.  Encrypted tokens are
+// written with the ContentTypeJWT content type, CWTs with the ContentTypeCWT content type, and
+// PASETO tokens with the ContentTypePASETO content type.
 func EncodeIssueResponse(_ context.Context, response http.ResponseWriter, value any) error {
-	response.Header().Set("Content-Type", "application/jose")
-	_, err := response.Write([]byte(value.(string)))
+	token, ok := value.(string)
+	if !ok {
+		ir := value.(IssueResponse)
+		token = ir.Token
+		if len(ir.RefreshToken) > 0 {
+			response.Header().Set(RefreshTokenHeader, ir.RefreshToken)
+		}
+	}
+
+	switch {
+	case isCWT(token):
+		response.Header().Set("Content-Type", ContentTypeCWT)
+	case isPASETO(token):
+		response.Header().Set("Content-Type", ContentTypePASETO)
+	case isEncrypted(token):
+		response.Header().Set("Content-Type", ContentTypeJWT)
+	default:
+		response.Header().Set("Content-Type", "application/jose")
+	}
+
+	_, err := response.Write([]byte(token))
 	return err
 }
 
@@ -522,6 +522,103 @@ func testNewRequestBuildersInvalidPartnerID(t *testing.T) {
 	}
 }
 
+func testNewRequestBuildersInvalidType(t *testing.T) {
+	testData := []struct {
+		name     string
+		value    Value
+		expected error
+	}{
+		{"UnknownType", Value{Key: "model", Header: "X-Midt-Model", Type: "uint"}, ErrInvalidValueType},
+		{"TypedVariable", Value{Key: "model", Variable: "model", Type: ValueTypeInt}, ErrTypeNotAllowed},
+		{"TypedStatic", Value{Key: "model", Value: 12, Type: ValueTypeInt}, ErrTypeNotAllowed},
+		{"ListVariable", Value{Key: "model", Variable: "model", List: true}, ErrTypeNotAllowed},
+		{"SeparatorNotList", Value{Key: "model", Header: "X-Midt-Model", Separator: ","}, ErrSeparatorNotAllowed},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			rb, err := NewRequestBuilders(Options{Claims: []Value{record.value}})
+			assert.ErrorIs(t, err, record.expected)
+			assert.Empty(t, rb)
+		})
+	}
+}
+
+func testNewRequestBuildersTyped(t *testing.T) {
+	testData := []struct {
+		name     string
+		value    Value
+		header   []string
+		form     string
+		expected any
+	}{
+		{"Untyped", Value{Header: "X-Value"}, []string{"1", "2"}, "", "1"},
+		{"String", Value{Header: "X-Value", Type: ValueTypeString}, []string{"1.2.3"}, "", "1.2.3"},
+		{"Int", Value{Header: "X-Value", Type: ValueTypeInt}, []string{"-12"}, "", int64(-12)},
+		{"Float", Value{Header: "X-Value", Type: ValueTypeFloat}, []string{"1.5"}, "", 1.5},
+		{"Bool", Value{Header: "X-Value", Type: ValueTypeBool}, []string{"true"}, "", true},
+		{"JSON", Value{Header: "X-Value", Type: ValueTypeJSON}, []string{`{"a": [1]}`}, "", json.RawMessage(`{"a": [1]}`)},
+		{"StringList", Value{Header: "X-Value", Type: ValueTypeStringList}, []string{"a", "b"}, "", []any{"a", "b"}},
+		{"IntList", Value{Header: "X-Value", Type: ValueTypeInt, List: true}, []string{"1", "2"}, "", []any{int64(1), int64(2)}},
+		{"Separator", Value{Header: "X-Value", List: true, Separator: ","}, []string{"a, b,", "c"}, "", []any{"a", "b", "c"}},
+		{"IntSeparator", Value{Header: "X-Value", Type: ValueTypeInt, List: true, Separator: ","}, []string{"1, 2"}, "", []any{int64(1), int64(2)}},
+		{"ParameterInt", Value{Parameter: "value", Type: ValueTypeInt}, nil, "value=12", int64(12)},
+		{"ParameterList", Value{Parameter: "value", Type: ValueTypeBool, List: true}, nil, "value=true&value=0", []any{true, false}},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			record.value.Key = "value"
+			rb, err := NewRequestBuilders(Options{Claims: []Value{record.value}})
+			require.NoError(t, err)
+
+			httpRequest := httptest.NewRequest("GET", "/test?"+record.form, nil)
+			httpRequest.Header["X-Value"] = record.header
+			require.NoError(t, httpRequest.ParseForm())
+
+			tokenRequest := NewRequest()
+			require.NoError(t, rb.Build(httpRequest, tokenRequest))
+			assert.Equal(t, map[string]any{"value": record.expected}, tokenRequest.Claims)
+		})
+	}
+}
+
+func testNewRequestBuildersInvalidTypedValue(t *testing.T) {
+	testData := []struct {
+		name    string
+		value   Value
+		header  []string
+		message string
+	}{
+		{"Int", Value{Type: ValueTypeInt}, []string{"1.5"}, `header X-Value: "1.5" is not an int`},
+		{"Float", Value{Type: ValueTypeFloat}, []string{"NaN"}, `header X-Value: "NaN" is not a finite float`},
+		{"Bool", Value{Type: ValueTypeBool}, []string{"yes"}, `header X-Value: "yes" is not a bool`},
+		{"JSON", Value{Type: ValueTypeJSON}, []string{"{"}, `header X-Value: "{" is not valid JSON`},
+		{"MultipleValues", Value{Type: ValueTypeString}, []string{"a", "b"}, "header X-Value: expected a single value, found 2"},
+		{"ListElement", Value{Type: ValueTypeInt, List: true, Separator: ","}, []string{"1, x"}, `header X-Value: "x" is not an int`},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			record.value.Key = "value"
+			record.value.Header = "X-Value"
+			rb, err := NewRequestBuilders(Options{Claims: []Value{record.value}})
+			require.NoError(t, err)
+
+			httpRequest := httptest.NewRequest("GET", "/test", nil)
+			httpRequest.Header["X-Value"] = record.header
+
+			err = rb.Build(httpRequest, NewRequest())
+			assert.ErrorIs(t, err, ErrInvalidValue)
+			assert.ErrorContains(t, err, record.message)
+
+			var buildErr BuildError
+			require.ErrorAs(t, err, &buildErr)
+			assert.Equal(t, http.StatusBadRequest, buildErr.StatusCode())
+		})
+	}
+}
+
 func TestNewRequestBuilders(t *testing.T) {
 	t.Run("InvalidClaim", testNewRequestBuildersInvalidClaim)
 	t.Run("InvalidMetadata", testNewRequestBuildersInvalidMetadata)
@@ -529,6 +626,9 @@ func TestNewRequestBuilders(t *testing.T) {
 	t.Run("InvalidQueryParameters", testNewRequestBuildersInvalidQueryParameters)
 	t.Run("MissingVariable", testNewRequestBuildersMissingVariable)
 	t.Run("InvalidPartnerID", testNewRequestBuildersInvalidPartnerID)
+	t.Run("InvalidType", testNewRequestBuildersInvalidType)
+	t.Run("Typed", testNewRequestBuildersTyped)
+	t.Run("InvalidTypedValue", testNewRequestBuildersInvalidTypedValue)
 	t.Run("Success", testNewRequestBuildersSuccess)
 }
 
@@ -838,6 +938,16 @@ func TestEncodeIssueResponse(t *testing.T) {
 
 	assert.Equal("application/jose", response.Header().Get("Content-Type"))
 	assert.Equal(expectedValue, response.Body.String())
+	assert.Empty(response.Header().Get(RefreshTokenHeader))
+
+	response = httptest.NewRecorder()
+	require.NoError(
+		EncodeIssueResponse(context.Background(), response, IssueResponse{Token: expectedValue, RefreshToken: "refresh"}),
+	)
+
+	assert.Equal("application/jose", response.Header().Get("Content-Type"))
+	assert.Equal(expectedValue, response.Body.String())
+	assert.Equal("refresh", response.Header().Get(RefreshTokenHeader))
 }
 
 func testDecodeRemoteClaimsResponseSuccess(t *testing.T) {
@@ -4,6 +4,9 @@ package token
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/prometheus/client_golang/prometheus"
@@ -18,6 +21,7 @@ import (
 
 var (
 	ErrRemoteClaimsEndpointMisconfigured = errors.New("remote claims builder must be configured if a remote claim builder endpoint was provided as an fx.Option")
+	ErrRotationAndReload                 = errors.New("a signing key cannot be both rotated and reloaded from its file")
 )
 
 func ProvideRemoteClaimsEndpoint(in provideRemoteClaimsEndpointIn) (out provideRemoteClaimsEndpointOut, err error) {
@@ -76,6 +80,7 @@ type TokenIn struct {
 	fx.In
 
 	Logger                  *zap.Logger
+	Lifecycle               fx.Lifecycle
 	Noncer                  random.Noncer `optional:"true"`
 	Keys                    key.Registry
 	Options                 Options
@@ -84,6 +89,14 @@ type TokenIn struct {
 	TrustCounter            *prometheus.CounterVec   `name:"trust_total"`
 	RemoteResults           *prometheus.CounterVec   `name:"remote_claims_api_result_total"`
 	RemoteDuration          *prometheus.HistogramVec `name:"remote_claims_api_request_duration_seconds"`
+	KeyRotations            *prometheus.CounterVec   `name:"key_rotation_total" optional:"true"`
+	KeyReloads              *prometheus.CounterVec   `name:"key_reload_total" optional:"true"`
+	IntrospectOutcomes      *prometheus.CounterVec   `name:"introspect_total" optional:"true"`
+	Revocations             Revocations              `optional:"true"`
+
+	// Client is used for the remote claims of profiles.  The remote claims endpoint of the
+	// default profile is provided separately as RemoteEndpoint.
+	Client xhttpclient.Interface `optional:"true"`
 }
 
 type TokenOut struct {
@@ -93,45 +106,235 @@ type TokenOut struct {
 	Factory       Factory
 	IssueHandler  IssueHandler
 	ClaimsHandler ClaimsHandler
+
+	// Verifier checks tokens signed by any key in the Registry
+	Verifier Verifier
+
+	// IntrospectHandler serves RFC 7662 introspection requests using Verifier
+	IntrospectHandler IntrospectHandler
+
+	// RefreshHandler redeems refresh tokens.  This component is nil unless refresh tokens are configured.
+	RefreshHandler RefreshHandler
+
+	// PASETOIssueHandler issues PASETO tokens regardless of the requested format.  This component is
+	// nil unless PASETO tokens are configured.
+	PASETOIssueHandler PASETOIssueHandler
+
+	// Profiles are the handlers of each configured profile.  This component is empty unless
+	// profiles are configured.
+	Profiles []ProfileHandlers
+}
+
+// keyRetention is how long replaced keys stay published, which is until every token they signed,
+// including refresh tokens and tokens issued under a TrustPolicy rule, has expired
+func keyRetention(o Options) time.Duration {
+	retention := o.Duration
+	if o.TrustPolicy != nil {
+		for _, rule := range o.TrustPolicy.Rules {
+			retention = max(retention, rule.Duration)
+		}
+	}
+
+	if o.Refresh != nil {
+		refreshDuration := o.Refresh.Duration
+		if refreshDuration <= 0 {
+			refreshDuration = DefaultRefreshDuration
+		}
+
+		retention = max(retention, refreshDuration)
+	}
+
+	return retention
+}
+
+// MaxLifetime is the longest that any token issued with the given Options, including refresh tokens
+// and the tokens of every profile, remains valid.  If some tokens never expire, this function returns zero.
+func MaxLifetime(o Options) time.Duration {
+	var lifetime time.Duration
+	for _, po := range append([]Options{o}, profileOptions(o.Profiles)...) {
+		if po.DisableTime || po.Duration <= 0 {
+			return 0
+		}
+
+		lifetime = max(lifetime, keyRetention(po))
+	}
+
+	return lifetime
+}
+
+// profileOptions returns the Options of each profile
+func profileOptions(profiles []Profile) []Options {
+	options := make([]Options, 0, len(profiles))
+	for _, p := range profiles {
+		options = append(options, p.Options)
+	}
+
+	return options
 }
 
 // TokenFactory returns an uber/fx style factory that produces the relevant components for
-// a single token factory.
+// a single token factory, along with the handlers of each configured profile.
 func TokenFactory(b ...RequestBuilder) func(TokenIn) (TokenOut, error) {
 	return func(in TokenIn) (TokenOut, error) {
-		if in.Options.ClientCertificates != nil {
-			in.Logger.Info("trust settings", zap.Any("trust_config", in.Options.ClientCertificates.Trust))
-		} else {
-			in.Logger.Info("trust settings", zap.Any("trust_config", Trust{}.enforceDefaults()))
+		if err := validateProfiles(in.Options.Profiles); err != nil {
+			return TokenOut{}, err
 		}
 
-		cb, err := NewClaimBuilders(in.Noncer, in.RemoteEndpoint, in.Options, in.DisableCertClaimBuilder, in.TrustCounter, in.RemoteResults, in.RemoteDuration)
+		out, err := newProfile(in, DefaultProfile, in.Options, in.RemoteEndpoint, b)
 		if err != nil {
 			return TokenOut{}, err
 		}
 
-		f, err := NewFactory(in.Options, cb, in.Keys)
+		v := NewVerifier(in.Keys, in.Options.Alg)
+		if in.Revocations != nil {
+			v = NewRevokingVerifier(v, in.Revocations)
+		}
+
+		out.Verifier = v
+		out.IntrospectHandler = NewIntrospectHandler(
+			NewIntrospectEndpoint(v, in.IntrospectOutcomes),
+		)
+
+		for _, p := range in.Options.Profiles {
+			var remoteEndpoint endpoint.Endpoint
+			if p.Remote != nil {
+				remoteEndpoint, err = newRemoteEndpoint(in.Client, p.Remote)
+				if err != nil {
+					return TokenOut{}, fmt.Errorf("profile %s: %w", p.Name, err)
+				}
+			}
+
+			po, err := newProfile(in, p.Name, p.Options, remoteEndpoint, b)
+			if err != nil {
+				return TokenOut{}, fmt.Errorf("profile %s: %w", p.Name, err)
+			}
+
+			out.Profiles = append(out.Profiles, ProfileHandlers{
+				Name:               p.Name,
+				IssueHandler:       po.IssueHandler,
+				ClaimsHandler:      po.ClaimsHandler,
+				RefreshHandler:     po.RefreshHandler,
+				PASETOIssueHandler: po.PASETOIssueHandler,
+			})
+		}
+
+		return out, nil
+	}
+}
+
+// newProfile produces the components that issue tokens for a single profile, whose metrics
+// are labelled with the profile's name
+func newProfile(in TokenIn, name string, o Options, remoteEndpoint endpoint.Endpoint, b []RequestBuilder) (TokenOut, error) {
+	if o.ClientCertificates != nil {
+		in.Logger.Info("trust settings", zap.String("profile", name), zap.Any("trust_config", o.ClientCertificates.Trust))
+	} else {
+		in.Logger.Info("trust settings", zap.String("profile", name), zap.Any("trust_config", Trust{}.enforceDefaults()))
+	}
+
+	profileLabel := prometheus.Labels{ProfileLabelKey: name}
+	cb, err := NewClaimBuilders(
+		in.Noncer,
+		remoteEndpoint,
+		o,
+		in.DisableCertClaimBuilder,
+		in.TrustCounter.MustCurryWith(profileLabel),
+		in.RemoteResults.MustCurryWith(profileLabel),
+		in.RemoteDuration.MustCurryWith(profileLabel),
+	)
+
+	if err != nil {
+		return TokenOut{}, err
+	}
+
+	f, err := newFactory(o, cb, in.Keys)
+	if err != nil {
+		return TokenOut{}, err
+	}
+
+	if o.Key.RotationInterval > 0 && o.Key.ReloadInterval > 0 {
+		return TokenOut{}, ErrRotationAndReload
+	}
+
+	retention := keyRetention(o)
+	if o.Key.ReloadInterval > 0 {
+		reloader, err := key.NewReloader(in.Keys, f.descriptor, f.currentPair(), retention, in.Logger, in.KeyReloads)
 		if err != nil {
 			return TokenOut{}, err
 		}
 
-		rb, err := NewRequestBuilders(in.Options)
+		reloader.OnReload(f.setPair)
+		in.Lifecycle.Append(fx.Hook{
+			OnStart: reloader.Start,
+			OnStop:  reloader.Stop,
+		})
+	}
+
+	if o.Key.RotationInterval > 0 {
+		rotator, err := key.NewRotator(in.Keys, f.descriptor, f.currentPair(), retention, in.Logger, in.KeyRotations)
 		if err != nil {
 			return TokenOut{}, err
 		}
 
-		rb = append(rb, b...)
-		return TokenOut{
-			ClaimBuilder: cb,
-			Factory:      f,
-			IssueHandler: NewIssueHandler(
-				NewIssueEndpoint(f),
-				rb,
-			),
-			ClaimsHandler: NewClaimsHandler(
-				NewClaimsEndpoint(cb),
-				rb,
-			),
-		}, nil
+		rotator.OnRotate(f.setPair)
+		in.Lifecycle.Append(fx.Hook{
+			OnStart: rotator.Start,
+			OnStop:  rotator.Stop,
+		})
 	}
+
+	rb, err := NewRequestBuilders(o)
+	if err != nil {
+		return TokenOut{}, err
+	}
+
+	rb = append(rb, b...)
+	out := TokenOut{
+		ClaimBuilder: cb,
+		Factory:      f,
+		ClaimsHandler: NewClaimsHandler(
+			NewClaimsEndpoint(cb),
+			// no token is issued by the claims endpoint, so it never requires a DPoP proof
+			slices.DeleteFunc(slices.Clone(rb), func(b RequestBuilder) bool {
+				_, ok := b.(*dpopRequestBuilder)
+				return ok
+			}),
+		),
+	}
+
+	issue := NewIssueEndpoint(f)
+	if o.Refresh != nil {
+		var (
+			remote ClaimBuilder
+			policy *trustPolicy
+		)
+
+		for _, b := range cb {
+			switch b := b.(type) {
+			case *remoteClaimBuilder:
+				remote = b
+			case *trustPolicy:
+				policy = b
+			}
+		}
+
+		rf := newRefresher(o, f, newRefreshVerifier(in.Keys, o.Alg), in.Revocations, remote, in.Noncer)
+		if policy != nil {
+			// refreshed tokens get the duration and claims of their cached trust
+			rf.reissue = append(rf.reissue, policy)
+		}
+
+		if name != DefaultProfile {
+			rf.profile = name
+		}
+
+		issue = NewRefreshingIssueEndpoint(rf)
+		out.RefreshHandler = NewRefreshHandler(NewRefreshEndpoint(rf), rb)
+	}
+
+	out.IssueHandler = NewIssueHandler(issue, rb)
+	if o.PASETO != nil {
+		out.PASETOIssueHandler = NewIssueHandler(issue, append(slices.Clone(rb), fixedFormat(FormatPASETO)))
+	}
+
+	return out, nil
 }
@@ -3,10 +3,17 @@
 package token
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/go-kit/kit/endpoint"
+	"github.com/golang-jwt/jwt"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/xmidt-org/sallust"
 	"github.com/xmidt-org/themis/v2/config"
 	"github.com/xmidt-org/themis/v2/key"
@@ -116,6 +123,47 @@ func testUnmarshalFactoryError(t *testing.T) {
 	assert.Nil(factory)
 }
 
+func testUnmarshalRotationAndReloadError(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		factory Factory
+
+		app = fx.New(
+			fx.NopLogger,
+			ProvideMetrics(),
+			fx.Provide(
+				sallust.Default,
+				config.ProvideViper,
+				fx.Annotate(func() config.ViperBuilder {
+					return config.Json(`
+						{
+							"token": {
+								"partnerID": {},
+								"key": {
+									"kid": "test",
+									"type": "ecdsa",
+									"rotationInterval": "1h",
+									"reloadInterval": "1m"
+								}
+							}
+						}
+					`)
+				}, fx.ResultTags(`group:"viperBuilders"`)),
+				func() key.Registry { return key.NewRegistry(nil) },
+				Unmarshal("token"),
+				xhttpclient.Unmarshal{Key: "client"}.Provide,
+				TokenFactory(),
+				ProvideRemoteClaimsEndpoint,
+				xmetricshttp.Unmarshal("prometheus", promhttp.HandlerOpts{}),
+			),
+			fx.Populate(&factory),
+		)
+	)
+
+	assert.ErrorIs(app.Err(), ErrRotationAndReload)
+	assert.Nil(factory)
+}
+
 func testUnmarshalRequestBuilderError(t *testing.T) {
 	var (
 		assert  = assert.New(t)
@@ -457,14 +505,281 @@ func testUnmarshalWithConfiguredRemoteEndpointAndClientSuccess(t *testing.T) {
 	assert.NotNil(factory)
 }
 
+func testUnmarshalRefresh(t *testing.T) {
+	testData := []struct {
+		configuration string
+		refresh       bool
+		paseto        bool
+	}{
+		{`{"token": {"partnerID": {}}}`, false, false},
+		{`{"token": {"partnerID": {}, "refresh": {"duration": "24h", "rotate": true}}}`, true, false},
+		{`{"token": {"alg": "EdDSA", "partnerID": {}, "paseto": {}, "refresh": {"duration": "24h"}}}`, true, true},
+	}
+
+	for _, record := range testData {
+		t.Run(record.configuration, func(t *testing.T) {
+			var (
+				assert = assert.New(t)
+				out    TokenOut
+
+				app = fxtest.New(t,
+					ProvideMetrics(),
+					fx.Provide(
+						sallust.Default,
+						config.ProvideViper,
+						fx.Annotate(func() config.ViperBuilder {
+							return config.Json(record.configuration)
+						}, fx.ResultTags(`group:"viperBuilders"`)),
+						func() key.Registry { return key.NewRegistry(nil) },
+						Unmarshal("token"),
+						TokenFactory(),
+						ProvideRemoteClaimsEndpoint,
+						xmetricshttp.Unmarshal("prometheus", promhttp.HandlerOpts{}),
+					),
+					fx.Invoke(func(in struct {
+						fx.In
+						IssueHandler       IssueHandler
+						RefreshHandler     RefreshHandler
+						PASETOIssueHandler PASETOIssueHandler
+					}) {
+						out.IssueHandler = in.IssueHandler
+						out.RefreshHandler = in.RefreshHandler
+						out.PASETOIssueHandler = in.PASETOIssueHandler
+					}),
+				)
+			)
+
+			assert.NoError(app.Err())
+			assert.NotNil(out.IssueHandler)
+			assert.Equal(record.refresh, out.RefreshHandler != nil)
+			assert.Equal(record.paseto, out.PASETOIssueHandler != nil)
+		})
+	}
+}
+
+// testProfilesApp starts an application with the given token configuration, populating the
+// components produced by TokenFactory
+func testProfilesApp(t *testing.T, configuration string, targets ...any) *fx.App {
+	return fx.New(
+		fx.NopLogger,
+		ProvideMetrics(),
+		fx.Provide(
+			sallust.Default,
+			config.ProvideViper,
+			fx.Annotate(func() config.ViperBuilder {
+				return config.Json(configuration)
+			}, fx.ResultTags(`group:"viperBuilders"`)),
+			func() key.Registry { return key.NewRegistry(nil) },
+			Unmarshal("token"),
+			TokenFactory(),
+			ProvideRemoteClaimsEndpoint,
+			xmetricshttp.Unmarshal("prometheus", promhttp.HandlerOpts{}),
+		),
+		fx.Populate(targets...),
+	)
+}
+
+func testUnmarshalProfilesSuccess(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		registry key.Registry
+		profiles []ProfileHandlers
+		trust    struct {
+			fx.In
+			Counter *prometheus.CounterVec `name:"trust_total"`
+		}
+
+		app = testProfilesApp(t, `
+			{
+				"token": {
+					"alg": "ES256",
+					"key": {"kid": "default", "type": "ecdsa", "bits": 256},
+					"partnerID": {},
+					"profiles": [
+						{
+							"name": "devices",
+							"alg": "ES256",
+							"key": {"kid": "devices", "type": "ecdsa", "bits": 256},
+							"partnerID": {"claim": "partner-id", "default": "comcast"},
+							"claims": [{"key": "profile", "value": "devices"}],
+							"refresh": {}
+						},
+						{
+							"name": "partners",
+							"alg": "ES256",
+							"key": {"kid": "partners", "type": "ecdsa", "bits": 256},
+							"partnerID": {},
+							"claims": [{"key": "profile", "value": "partners"}]
+						}
+					]
+				}
+			}
+		`, &registry, &profiles, &trust)
+	)
+
+	require.NoError(app.Err())
+	require.Len(profiles, 2)
+	assert.Equal("devices", profiles[0].Name)
+	assert.NotNil(profiles[0].RefreshHandler)
+	assert.Equal("partners", profiles[1].Name)
+	assert.Nil(profiles[1].RefreshHandler)
+
+	verifier := NewVerifier(registry, "ES256")
+	for _, p := range profiles {
+		assert.Nil(p.PASETOIssueHandler)
+		require.NotNil(p.ClaimsHandler)
+
+		response := httptest.NewRecorder()
+		p.IssueHandler.ServeHTTP(response, httptest.NewRequest("GET", "/issue/"+p.Name, nil))
+		require.Equal(http.StatusOK, response.Code)
+
+		// each profile signs with its own key and issues its own claims
+		token, _, err := new(jwt.Parser).ParseUnverified(response.Body.String(), jwt.MapClaims{})
+		require.NoError(err)
+		assert.Equal(p.Name, token.Header["kid"])
+
+		claims, err := verifier.Verify(response.Body.String())
+		require.NoError(err)
+		assert.Equal(p.Name, claims["profile"])
+	}
+
+	assert.Equal(1.0, testutil.ToFloat64(trust.Counter.With(prometheus.Labels{
+		TrustLabelKey:     strconv.Itoa(DefaultTrustLevelNoCertificates),
+		IssuerCNLabelKey:  "",
+		PartnerIDLabelKey: "comcast",
+		ReasonLabelKey:    NoCertificatesReason,
+		ProfileLabelKey:   "devices",
+	})))
+}
+
+func testUnmarshalProfilesError(t *testing.T) {
+	testData := []struct {
+		name          string
+		configuration string
+		expected      error
+	}{
+		{
+			name:          "Reserved",
+			configuration: `{"token": {"partnerID": {}, "profiles": [{"name": "paseto", "partnerID": {}}]}}`,
+			expected:      ErrReservedProfileName,
+		},
+		{
+			name:          "SameKid",
+			configuration: `{"token": {"key": {"kid": "test"}, "partnerID": {}, "profiles": [{"name": "devices", "key": {"kid": "test"}, "partnerID": {}}]}}`,
+		},
+		{
+			name:          "BadRemote",
+			configuration: `{"token": {"partnerID": {}, "profiles": [{"name": "devices", "partnerID": {}, "remote": {"method": "POST"}}]}}`,
+			expected:      ErrRemoteURLRequired,
+		},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				profiles []ProfileHandlers
+				app      = testProfilesApp(t, record.configuration, &profiles)
+			)
+
+			assert.Error(t, app.Err())
+			if record.expected != nil {
+				assert.ErrorIs(t, app.Err(), record.expected)
+			}
+
+			assert.Empty(t, profiles)
+		})
+	}
+}
+
+func TestKeyRetention(t *testing.T) {
+	testData := []struct {
+		name     string
+		options  Options
+		expected time.Duration
+	}{
+		{"Duration", Options{Duration: time.Hour}, time.Hour},
+		{"RefreshDefault", Options{Duration: time.Hour, Refresh: &Refresh{}}, DefaultRefreshDuration},
+		{"Refresh", Options{Duration: time.Hour, Refresh: &Refresh{Duration: 2 * time.Hour}}, 2 * time.Hour},
+		{
+			"TrustPolicy",
+			Options{
+				Duration: time.Hour,
+				TrustPolicy: &TrustPolicy{
+					Rules: []TrustRule{{Duration: time.Minute}, {Duration: 3 * time.Hour}, {Refuse: true}},
+				},
+			},
+			3 * time.Hour,
+		},
+		{
+			"TrustPolicyAndRefresh",
+			Options{
+				Duration:    time.Hour,
+				Refresh:     &Refresh{Duration: 2 * time.Hour},
+				TrustPolicy: &TrustPolicy{Rules: []TrustRule{{Duration: 4 * time.Hour}}},
+			},
+			4 * time.Hour,
+		},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			assert.Equal(t, record.expected, keyRetention(record.options))
+		})
+	}
+}
+
+func TestMaxLifetime(t *testing.T) {
+	testData := []struct {
+		name     string
+		options  Options
+		expected time.Duration
+	}{
+		{"Duration", Options{Duration: time.Hour}, time.Hour},
+		{"NoDuration", Options{}, 0},
+		{"DisableTime", Options{Duration: time.Hour, DisableTime: true}, 0},
+		{"Refresh", Options{Duration: time.Hour, Refresh: &Refresh{Duration: 2 * time.Hour}}, 2 * time.Hour},
+		{
+			"Profiles",
+			Options{
+				Duration: time.Hour,
+				Profiles: []Profile{
+					{Name: "short", Options: Options{Duration: time.Minute}},
+					{Name: "long", Options: Options{Duration: 3 * time.Hour}},
+				},
+			},
+			3 * time.Hour,
+		},
+		{
+			"ProfileWithoutDuration",
+			Options{
+				Duration: time.Hour,
+				Profiles: []Profile{{Name: "forever"}},
+			},
+			0,
+		},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			assert.Equal(t, record.expected, MaxLifetime(record.options))
+		})
+	}
+}
+
 func TestUnmarshal(t *testing.T) {
 	t.Run("Error", testUnmarshalError)
 	t.Run("ClaimBuilderError", testUnmarshalClaimBuilderError)
 	t.Run("FactoryError", testUnmarshalFactoryError)
+	t.Run("RotationAndReloadError", testUnmarshalRotationAndReloadError)
 	t.Run("RequestBuilderError", testUnmarshalRequestBuilderError)
 	t.Run("RemoteEndpointConflictMisconfigured", testUnmarshalRemoteEndpointMisconfigured)
 	t.Run("UnmarshalWithoutRemoteEndpointSuccess", testUnmarshalWithoutRemoteEndpointSuccess)
 	t.Run("UnmarshalWithProvidedRemoteEndpointSuccess", testUnmarshalWithProvidedRemoteEndpointSuccess)
 	t.Run("UnmarshalWithConfiguredRemoteEndpointSuccess", testUnmarshalWithConfiguredRemoteEndpointSuccess)
 	t.Run("UnmarshalWithConfiguredRemoteEndpointAndClientSuccess", testUnmarshalWithConfiguredRemoteEndpointAndClientSuccess)
+	t.Run("Refresh", testUnmarshalRefresh)
+	t.Run("ProfilesSuccess", testUnmarshalProfilesSuccess)
+	t.Run("ProfilesError", testUnmarshalProfilesError)
 }
@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/xmidt-org/themis/v2/key"
+)
+
+var (
+	ErrMalformedToken   = errors.New("the token is malformed")
+	ErrUnknownKid       = errors.New("the token was not signed by a known key")
+	ErrBadSignature     = errors.New("the token signature is not valid")
+	ErrTokenExpired     = errors.New("the token has expired")
+	ErrTokenNotYetValid = errors.New("the token is not yet valid")
+	ErrTokenRevoked     = errors.New("the token has been revoked")
+	ErrNotAccessToken   = errors.New("the token is not an access token")
+)
+
+// Verifier checks tokens issued by themis
+type Verifier interface {
+	// Verify checks a token's signature and time-based claims, returning the token's claims
+	// if it is valid.  The returned error wraps one of the sentinel errors in this package,
+	// e.g. ErrTokenExpired, to indicate why a token was rejected.
+	Verify(token string) (map[string]any, error)
+}
+
+// Revocations determines whether tokens have been revoked before they expired
+type Revocations interface {
+	// Revoked tests if a token with the given claims has been revoked
+	Revoked(claims map[string]any) bool
+}
+
+type verifier struct {
+	keys   key.Registry
+	alg    string
+	now    func() time.Time
+	parser *jwt.Parser
+
+	// refresh indicates that this verifier accepts only refresh tokens, rather than only access tokens
+	refresh bool
+}
+
+// NewVerifier creates a Verifier for tokens signed by any Pair in a key Registry.  The signing
+// algorithm in each token must match the algorithm of the Pair identified by the token's kid header.
+// For pairs registered without an algorithm, the given alg is required.  If alg is empty, DefaultAlg is used.
+//
+// The returned Verifier accepts only access tokens.  Refresh tokens, which are signed by the same keys,
+// are rejected with ErrNotAccessToken.
+func NewVerifier(kr key.Registry, alg string) Verifier {
+	return newVerifier(kr, alg, false)
+}
+
+// newRefreshVerifier creates a Verifier that accepts only refresh tokens, rejecting anything
+// else with ErrNotRefreshToken
+func newRefreshVerifier(kr key.Registry, alg string) Verifier {
+	return newVerifier(kr, alg, true)
+}
+
+func newVerifier(kr key.Registry, alg string, refresh bool) *verifier {
+	if len(alg) == 0 {
+		alg = DefaultAlg
+	}
+
+	return &verifier{
+		refresh: refresh,
+		keys:    kr,
+		alg:     alg,
+		now:     time.Now,
+		parser: &jwt.Parser{
+			// numeric claims are preserved as is, and time-based claims are checked by this verifier
+			UseJSONNumber:        true,
+			SkipClaimsValidation: true,
+		},
+	}
+}
+
+func (v *verifier) Verify(value string) (map[string]any, error) {
+	claims := jwt.MapClaims{}
+	token, err := v.parser.ParseWithClaims(value, claims, v.verificationKey)
+	if err != nil {
+		// jwt.ValidationError does not support errors.Is, so its fields are examined directly
+		var ve *jwt.ValidationError
+		switch {
+		case !errors.As(err, &ve):
+			return nil, fmt.Errorf("%w: %s", ErrMalformedToken, err)
+		case errors.Is(ve.Inner, ErrUnknownKid) || errors.Is(ve.Inner, ErrBadSignature):
+			return nil, ve.Inner
+		case ve.Errors&jwt.ValidationErrorSignatureInvalid != 0:
+			return nil, fmt.Errorf("%w: %s", ErrBadSignature, err)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrMalformedToken, err)
+		}
+	}
+
+	if err := v.checkUse(token, claims); err != nil {
+		return nil, err
+	}
+
+	if err := v.checkTime(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// checkUse verifies that a token is the kind this verifier accepts.  A refresh token is one with the
+// TypeRefreshToken typ header.  The ClaimTokenUse claim is checked as well, so that an access token
+// never carries it.
+func (v *verifier) checkUse(token *jwt.Token, claims jwt.MapClaims) error {
+	typ, _ := token.Header["typ"].(string)
+	switch {
+	case v.refresh && typ != TypeRefreshToken:
+		return fmt.Errorf("%w: typ %q", ErrNotRefreshToken, typ)
+	case !v.refresh && (typ == TypeRefreshToken || claims[ClaimTokenUse] == TokenUseRefresh):
+		return ErrNotAccessToken
+	default:
+		return nil
+	}
+}
+
+// verificationKey is the jwt.Keyfunc that locates the key which must have signed a token
+func (v *verifier) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	pair, ok := v.keys.Get(kid)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKid, kid)
+	}
+
+	// the algorithm is dictated by the key, never by the token
+	alg := pair.Alg()
+	if len(alg) == 0 {
+		alg = v.alg
+	}
+
+	if token.Method.Alg() != alg {
+		return nil, fmt.Errorf("%w: expected alg %s for kid %q, got %s", ErrBadSignature, alg, kid, token.Method.Alg())
+	}
+
+	if signer := pair.Signer(); signer != nil {
+		return signer.Public(), nil
+	}
+
+	return pair.Sign(), nil
+}
+
+// numericDate extracts a NumericDate claim, returning false if the claim is not present
+func numericDate(claims jwt.MapClaims, name string) (time.Time, bool, error) {
+	value, ok := claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	n, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("%w: %s is not a number", ErrMalformedToken, name)
+	}
+
+	seconds, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: %s is not a number", ErrMalformedToken, name)
+	}
+
+	return time.Unix(0, int64(seconds*float64(time.Second))), true, nil
+}
+
+// checkTime verifies the exp, nbf, and iat claims.  Each is optional, since themis can be
+// configured to omit any of them.
+func (v *verifier) checkTime(claims jwt.MapClaims) error {
+	now := v.now()
+	exp, ok, err := numericDate(claims, "exp")
+	if err != nil {
+		return err
+	} else if ok && !now.Before(exp) {
+		return fmt.Errorf("%w: expired at %s", ErrTokenExpired, exp.UTC().Format(time.RFC3339))
+	}
+
+	nbf, ok, err := numericDate(claims, "nbf")
+	if err != nil {
+		return err
+	} else if ok && now.Before(nbf) {
+		return fmt.Errorf("%w: not valid before %s", ErrTokenNotYetValid, nbf.UTC().Format(time.RFC3339))
+	}
+
+	iat, ok, err := numericDate(claims, "iat")
+	if err != nil {
+		return err
+	} else if ok && now.Before(iat) {
+		return fmt.Errorf("%w: issued in the future at %s", ErrTokenNotYetValid, iat.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+type revokingVerifier struct {
+	Verifier
+	revocations Revocations
+}
+
+// NewRevokingVerifier decorates a Verifier so that otherwise valid tokens are rejected with
+// ErrTokenRevoked if they have been revoked.
+func NewRevokingVerifier(v Verifier, r Revocations) Verifier {
+	return revokingVerifier{
+		Verifier:    v,
+		revocations: r,
+	}
+}
+
+func (rv revokingVerifier) Verify(value string) (map[string]any, error) {
+	claims, err := rv.Verifier.Verify(value)
+	if err != nil {
+		return nil, err
+	} else if rv.revocations.Revoked(claims) {
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
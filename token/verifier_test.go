@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package token
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/themis/v2/key"
+)
+
+// testSignToken signs a set of claims with a registered pair, as a factory would
+func testSignToken(t *testing.T, kr key.Registry, kid, alg string, claims jwt.MapClaims) string {
+	pair, ok := kr.Get(kid)
+	require.True(t, ok)
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(alg), claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(pair.Sign())
+	require.NoError(t, err)
+	return signed
+}
+
+func testVerifierAlgorithms(t *testing.T) {
+	testData := []struct {
+		alg        string
+		descriptor key.Descriptor
+	}{
+		{"RS256", key.Descriptor{Kid: "rsa", Type: key.KeyTypeRSA, Bits: 1024, Alg: "RS256"}},
+		{"PS384", key.Descriptor{Kid: "rsa-pss", Type: key.KeyTypeRSA, Bits: 1024, Alg: "PS384"}},
+		{"ES256", key.Descriptor{Kid: "ecdsa", Type: key.KeyTypeECDSA, Bits: 256, Alg: "ES256"}},
+		{AlgEdDSA, key.Descriptor{Kid: "ed25519", Type: key.KeyTypeEd25519, Alg: AlgEdDSA}},
+		{"HS256", key.Descriptor{Kid: "secret", Type: key.KeyTypeSecret, Bits: 256, Alg: "HS256"}},
+	}
+
+	for _, record := range testData {
+		t.Run(record.alg, func(t *testing.T) {
+			var (
+				assert   = assert.New(t)
+				require  = require.New(t)
+				registry = key.NewRegistry(rand.Reader)
+			)
+
+			_, err := registry.Register(record.descriptor)
+			require.NoError(err)
+
+			now := time.Now()
+			token := testSignToken(t, registry, record.descriptor.Kid, record.alg, jwt.MapClaims{
+				"sub": "test",
+				"iat": now.Unix(),
+				"nbf": now.Unix(),
+				"exp": now.Add(time.Hour).Unix(),
+			})
+
+			claims, err := NewVerifier(registry, "").Verify(token)
+			require.NoError(err)
+			assert.Equal("test", claims["sub"])
+			assert.Equal(json.Number(strconv.FormatInt(now.Add(time.Hour).Unix(), 10)), claims["exp"])
+		})
+	}
+}
+
+func testVerifierTime(t *testing.T) {
+	var (
+		registry = key.NewRegistry(rand.Reader)
+		now      = time.Unix(1700000000, 0)
+	)
+
+	_, err := registry.Register(key.Descriptor{Kid: "test", Type: key.KeyTypeECDSA, Bits: 256, Alg: "ES256"})
+	require.NoError(t, err)
+
+	testData := []struct {
+		name     string
+		claims   jwt.MapClaims
+		expected error
+	}{
+		{"NoTimeClaims", jwt.MapClaims{"sub": "test"}, nil},
+		{"Valid", jwt.MapClaims{"iat": now.Unix(), "nbf": now.Unix(), "exp": now.Unix() + 1}, nil},
+		{"Expired", jwt.MapClaims{"exp": now.Unix()}, ErrTokenExpired},
+		{"NotBefore", jwt.MapClaims{"nbf": now.Unix() + 1}, ErrTokenNotYetValid},
+		{"IssuedInFuture", jwt.MapClaims{"iat": now.Unix() + 60}, ErrTokenNotYetValid},
+		{"InvalidExp", jwt.MapClaims{"exp": "tomorrow"}, ErrMalformedToken},
+		{"InvalidNbf", jwt.MapClaims{"nbf": true}, ErrMalformedToken},
+		{"InvalidIat", jwt.MapClaims{"iat": []int{1}}, ErrMalformedToken},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			v := NewVerifier(registry, "ES256").(*verifier)
+			v.now = func() time.Time { return now }
+
+			claims, err := v.Verify(testSignToken(t, registry, "test", "ES256", record.claims))
+			if record.expected == nil {
+				assert.NoError(t, err)
+				assert.NotNil(t, claims)
+			} else {
+				assert.ErrorIs(t, err, record.expected)
+				assert.Nil(t, claims)
+			}
+		})
+	}
+}
+
+func testVerifierRejected(t *testing.T) {
+	var (
+		registry = key.NewRegistry(rand.Reader)
+		other    = key.NewRegistry(rand.Reader)
+		v        = NewVerifier(registry, "RS256")
+	)
+
+	_, err := registry.Register(key.Descriptor{Kid: "test", Type: key.KeyTypeRSA, Bits: 1024})
+	require.NoError(t, err)
+	_, err = other.Register(key.Descriptor{Kid: "test", Type: key.KeyTypeRSA, Bits: 1024})
+	require.NoError(t, err)
+	_, err = other.Register(key.Descriptor{Kid: "unknown", Type: key.KeyTypeRSA, Bits: 1024})
+	require.NoError(t, err)
+
+	valid := testSignToken(t, registry, "test", "RS256", jwt.MapClaims{"sub": "test"})
+	parts := strings.Split(valid, ".")
+
+	testData := []struct {
+		name     string
+		token    string
+		expected error
+	}{
+		{"UnknownKid", testSignToken(t, other, "unknown", "RS256", jwt.MapClaims{}), ErrUnknownKid},
+		{"NoKid", func() string {
+			pair, _ := registry.Get("test")
+			token, err := jwt.New(jwt.SigningMethodRS256).SignedString(pair.Sign())
+			require.NoError(t, err)
+			return token
+		}(), ErrUnknownKid},
+		{"OtherKey", testSignToken(t, other, "test", "RS256", jwt.MapClaims{"sub": "test"}), ErrBadSignature},
+		{"TamperedClaims", parts[0] + "." + jwt.EncodeSegment([]byte(`{"sub":"admin"}`)) + "." + parts[2], ErrBadSignature},
+		{"AlgMismatch", testSignToken(t, registry, "test", "RS384", jwt.MapClaims{}), ErrBadSignature},
+		{"None", jwt.EncodeSegment([]byte(`{"alg":"none","kid":"test"}`)) + "." + parts[1] + ".", ErrBadSignature},
+		{"Malformed", "this is not a token", ErrMalformedToken},
+		{"Empty", "", ErrMalformedToken},
+		{"RefreshType", func() string {
+			pair, _ := registry.Get("test")
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "test"})
+			token.Header["kid"] = "test"
+			token.Header["typ"] = TypeRefreshToken
+			signed, err := token.SignedString(pair.Sign())
+			require.NoError(t, err)
+			return signed
+		}(), ErrNotAccessToken},
+		{"RefreshUse", testSignToken(t, registry, "test", "RS256", jwt.MapClaims{ClaimTokenUse: TokenUseRefresh}), ErrNotAccessToken},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			claims, err := v.Verify(record.token)
+			assert.ErrorIs(t, err, record.expected)
+			assert.Nil(t, claims)
+		})
+	}
+}
+
+// testRevocations revokes tokens with a given jti
+type testRevocations string
+
+func (tr testRevocations) Revoked(claims map[string]any) bool {
+	return claims["jti"] == string(tr)
+}
+
+func testVerifierRevoked(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		registry = key.NewRegistry(rand.Reader)
+		v        = NewRevokingVerifier(NewVerifier(registry, "ES256"), testRevocations("revoked"))
+	)
+
+	_, err := registry.Register(key.Descriptor{Kid: "test", Type: key.KeyTypeECDSA, Bits: 256, Alg: "ES256"})
+	require.NoError(err)
+
+	claims, err := v.Verify(testSignToken(t, registry, "test", "ES256", jwt.MapClaims{"jti": "valid"}))
+	require.NoError(err)
+	assert.Equal("valid", claims["jti"])
+
+	claims, err = v.Verify(testSignToken(t, registry, "test", "ES256", jwt.MapClaims{"jti": "revoked"}))
+	assert.ErrorIs(err, ErrTokenRevoked)
+	assert.Nil(claims)
+
+	// verification failures take precedence
+	claims, err = v.Verify(testSignToken(t, registry, "test", "ES256", jwt.MapClaims{"jti": "revoked", "exp": 1}))
+	assert.ErrorIs(err, ErrTokenExpired)
+	assert.Nil(claims)
+}
+
+func TestVerifier(t *testing.T) {
+	t.Run("Algorithms", testVerifierAlgorithms)
+	t.Run("Time", testVerifierTime)
+	t.Run("Rejected", testVerifierRejected)
+	t.Run("Revoked", testVerifierRevoked)
+}
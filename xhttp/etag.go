@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package xhttp
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type ifNoneMatchKey struct{}
+
+// WithIfNoneMatch stores a request's If-None-Match header in the context for WriteCacheable.
+// This function may be used as a go-kit ServerBefore function.
+func WithIfNoneMatch(ctx context.Context, request *http.Request) context.Context {
+	return context.WithValue(ctx, ifNoneMatchKey{}, request.Header.Get("If-None-Match"))
+}
+
+// ETag computes a strong entity tag from a response body
+func ETag(data []byte) string {
+	return fmt.Sprintf(`"%x"`, sha256.Sum256(data))
+}
+
+// ETagMatches tests if an If-None-Match header value matches the given entity tag.
+// Weak comparison is used, as described in RFC 7232.
+func ETagMatches(ifNoneMatch, etag string) bool {
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriteCacheable writes a response body along with its ETag and the given Cache-Control header.
+// If the If-None-Match header stored in the context by WithIfNoneMatch matches the ETag, a 304
+// is written instead of the body.
+func WriteCacheable(ctx context.Context, response http.ResponseWriter, contentType, cacheControl string, data []byte) error {
+	etag := ETag(data)
+	response.Header().Set("ETag", etag)
+	response.Header().Set("Cache-Control", cacheControl)
+	if ifNoneMatch, _ := ctx.Value(ifNoneMatchKey{}).(string); len(ifNoneMatch) > 0 && ETagMatches(ifNoneMatch, etag) {
+		response.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	response.Header().Set("Content-Type", contentType)
+	_, err := response.Write(data)
+	return err
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+package xhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETagMatches(t *testing.T) {
+	testData := []struct {
+		ifNoneMatch string
+		expected    bool
+	}{
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", "abc"`, true},
+		{`*`, true},
+		{`"xyz"`, false},
+		{`abc`, false},
+		{``, false},
+	}
+
+	for _, record := range testData {
+		t.Run(record.ifNoneMatch, func(t *testing.T) {
+			assert.Equal(t, record.expected, ETagMatches(record.ifNoneMatch, `"abc"`))
+		})
+	}
+}
+
+func TestWriteCacheable(t *testing.T) {
+	var (
+		data = []byte(`{"value": 1}`)
+		etag = ETag(data)
+	)
+
+	testData := []struct {
+		name         string
+		ifNoneMatch  string
+		expectedCode int
+	}{
+		{"NoIfNoneMatch", "", http.StatusOK},
+		{"Match", etag, http.StatusNotModified},
+		{"NoMatch", `"other"`, http.StatusOK},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			var (
+				assert   = assert.New(t)
+				require  = require.New(t)
+				request  = httptest.NewRequest("GET", "/", nil)
+				response = httptest.NewRecorder()
+			)
+
+			if len(record.ifNoneMatch) > 0 {
+				request.Header.Set("If-None-Match", record.ifNoneMatch)
+			}
+
+			ctx := WithIfNoneMatch(context.Background(), request)
+			require.NoError(WriteCacheable(ctx, response, "application/json", "public, max-age=60", data))
+			assert.Equal(record.expectedCode, response.Code)
+			assert.Equal(etag, response.Header().Get("ETag"))
+			assert.Equal("public, max-age=60", response.Header().Get("Cache-Control"))
+			if record.expectedCode == http.StatusOK {
+				assert.Equal("application/json", response.Header().Get("Content-Type"))
+				assert.Equal(data, response.Body.Bytes())
+			} else {
+				assert.Empty(response.Body.Bytes())
+			}
+		})
+	}
+}